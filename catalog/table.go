@@ -0,0 +1,284 @@
+// Package catalog ürün kataloğunun CSV ve XLSX dosyalarıyla içe ve dışa
+// aktarımı için tablo okuma/yazma işlerini yapar. Satırların ürünlere
+// çevrilmesi ve doğrulanması controller'dadır; bu paket yalnızca başlığı
+// doğrular ve hücreleri sütun adıyla eşler.
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// Sütunlar, dışa aktarımdaki sırasıyla. İçe aktarmada sıra önemli değildir;
+// id yalnızca mevcut ürünleri eşleştirmek için kullanılır ve isteğe bağlıdır.
+var Columns = []string{
+	"id", "sku", "name", "description", "category", "price", "currency",
+	"unit", "min_quantity", "quantity_step", "stock", "reorder_threshold",
+	"is_active", "image_url",
+}
+
+// İçe aktarılan dosyada bulunması zorunlu sütunlar
+var RequiredColumns = []string{"sku", "name", "price"}
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+func ParseFormat(s string) (Format, bool) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case FormatCSV:
+		return FormatCSV, true
+	case FormatXLSX:
+		return FormatXLSX, true
+	}
+	return "", false
+}
+
+// FormatFromFilename biçimi dosya uzantısından çıkarır.
+func FormatFromFilename(name string) (Format, bool) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
+
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Row dosyadaki bir veri satırı. Line, başlık 1. satır olacak şekilde dosyadaki
+// satır numarasıdır; Values yalnızca başlıkta bulunan sütunları içerir.
+type Row struct {
+	Line   int
+	Values map[string]string
+}
+
+// Has sütunun başlıkta olup hücrenin dolu olduğunu söyler.
+func (r Row) Has(column string) bool {
+	return r.Values[column] != ""
+}
+
+// ReadRows dosyanın ilk sayfasını/tablosunu okur. Başlıktaki sütun adları büyük
+// küçük harf duyarsızdır; bilinmeyen veya tekrarlanan sütunlar ve eksik zorunlu
+// sütunlar hata döner. Tamamen boş satırlar atlanır.
+func ReadRows(r io.Reader, format Format, maxRows int) ([]Row, error) {
+	var records [][]string
+	var err error
+	switch format {
+	case FormatCSV:
+		records, err = readCSV(r)
+	case FormatXLSX:
+		records, err = readXLSX(r)
+	default:
+		return nil, fmt.Errorf("desteklenmeyen dosya biçimi: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("dosya boş, ilk satır sütun başlıkları olmalı")
+	}
+
+	header, err := parseHeader(records[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for i, record := range records[1:] {
+		row := Row{Line: i + 2, Values: make(map[string]string, len(header))}
+		empty := true
+		for j, column := range header {
+			if j < len(record) {
+				row.Values[column] = strings.TrimSpace(record[j])
+				if row.Values[column] != "" {
+					empty = false
+				}
+			}
+		}
+		if empty {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("dosya en fazla %d satır içerebilir", maxRows)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseHeader(record []string) ([]string, error) {
+	known := make(map[string]bool, len(Columns))
+	for _, column := range Columns {
+		known[column] = true
+	}
+
+	header := make([]string, len(record))
+	seen := make(map[string]bool)
+	for i, cell := range record {
+		column := strings.ToLower(strings.TrimSpace(cell))
+		if column == "" {
+			continue
+		}
+		if !known[column] {
+			return nil, fmt.Errorf("bilinmeyen sütun: %s (geçerli sütunlar: %s)", cell, strings.Join(Columns, ", "))
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("sütun birden fazla kez geçiyor: %s", column)
+		}
+		seen[column] = true
+		header[i] = column
+	}
+
+	for _, column := range RequiredColumns {
+		if !seen[column] {
+			return nil, fmt.Errorf("zorunlu sütun eksik: %s", column)
+		}
+	}
+	return header, nil
+}
+
+// readCSV virgül veya (Türkçe Excel'in kullandığı) noktalı virgülle ayrılmış
+// UTF-8 dosyayı okur; ayracı başlık satırından tespit eder.
+func readCSV(r io.Reader) ([][]string, error) {
+	br := bufio.NewReader(r)
+	if bom, _ := br.Peek(len(utf8BOM)); bytes.Equal(bom, utf8BOM) {
+		br.Discard(len(utf8BOM))
+	}
+
+	first, _ := br.Peek(br.Size())
+	if i := bytes.IndexByte(first, '\n'); i >= 0 {
+		first = first[:i]
+	}
+
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	if bytes.Count(first, []byte{';'}) > bytes.Count(first, []byte{','}) {
+		cr.Comma = ';'
+	}
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("CSV okunamadı: %w", err)
+	}
+	return records, nil
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, errors.New("XLSX dosyası okunamadı")
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil
+	}
+	records, err := f.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, errors.New("XLSX dosyası okunamadı")
+	}
+	return records, nil
+}
+
+// Writer dışa aktarılan satırları yazar. Başlık satırı NewWriter tarafından yazılır.
+type Writer interface {
+	Write(values []string) error
+	Close() error
+}
+
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	var writer Writer
+	switch format {
+	case FormatCSV:
+		// BOM, Excel'in Türkçe karakterleri doğru açması için
+		if _, err := w.Write(utf8BOM); err != nil {
+			return nil, err
+		}
+		writer = &csvWriter{w: csv.NewWriter(w)}
+	case FormatXLSX:
+		xw, err := newXLSXWriter(w)
+		if err != nil {
+			return nil, err
+		}
+		writer = xw
+	default:
+		return nil, fmt.Errorf("desteklenmeyen dosya biçimi: %s", format)
+	}
+
+	if err := writer.Write(Columns); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (cw *csvWriter) Write(values []string) error {
+	return cw.w.Write(values)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// XLSX bir zip arşivi olduğundan dosya Close'da bütün olarak yazılır; satırlar
+// bellekte değil excelize'ın geçici dosyasında tutulur.
+type xlsxWriter struct {
+	out  io.Writer
+	file *excelize.File
+	sw   *excelize.StreamWriter
+	row  int
+}
+
+const xlsxSheet = "Products"
+
+func newXLSXWriter(out io.Writer) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	if err := f.SetSheetName("Sheet1", xlsxSheet); err != nil {
+		return nil, err
+	}
+	sw, err := f.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{out: out, file: f, sw: sw}, nil
+}
+
+func (xw *xlsxWriter) Write(values []string) error {
+	xw.row++
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		row[i] = v
+	}
+	return xw.sw.SetRow(cell, row)
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.file.Close()
+	if err := xw.sw.Flush(); err != nil {
+		return err
+	}
+	return xw.file.Write(xw.out)
+}
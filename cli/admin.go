@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"tradesman-api/config"
+)
+
+func runCreateAdmin(args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := fs.String("email", "", "admin email adresi")
+	password := fs.String("password", "", "admin şifresi (boşsa ADMIN_PASSWORD okunur)")
+	name := fs.String("name", "Admin", "admin adı")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *password == "" {
+		*password = os.Getenv("ADMIN_PASSWORD")
+	}
+	if *email == "" || *password == "" {
+		return errors.New("kullanım: create-admin -email EMAIL -password ŞİFRE [-name AD]")
+	}
+
+	config.InitDatabase()
+
+	admin, err := config.CreateAdmin(*email, *password, *name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("👑 Admin oluşturuldu: #%d %s\n", admin.ID, admin.Email)
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package cli tek binary içindeki alt komutları (serve, migrate, seed,
+// create-admin, routes, reindex, stock-check) çalıştırır. Tüm komutlar aynı konfigürasyonu kullanır.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"tradesman-api/config"
+)
+
+type command struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+var commands = []command{
+	{"serve", "HTTP server'ı başlatır (varsayılan)", runServe},
+	{"migrate", "Şema migrasyonları: up | down [-steps N] | status", runMigrate},
+	{"seed", "Mobil ekip için demo esnaf, ürün ve müşteri verisi oluşturur", runSeed},
+	{"create-admin", "Admin kullanıcısı oluşturur: -email -password [-name]", runCreateAdmin},
+	{"routes", "Kayıtlı HTTP route tablosunu yazdırır", runRoutes},
+	{"reindex", "Ürün arama indeksini baştan oluşturur", runReindex},
+	{"stock-check", "Stokları stok defteriyle karşılaştırır [-fix]", runStockCheck},
+}
+
+// Run global bayrakları işler, konfigürasyonu yükler ve alt komutu çalıştırır.
+// Alt komut verilmezse serve çalışır.
+func Run(args []string) error {
+	fs := flag.NewFlagSet("tradesman-api", flag.ContinueOnError)
+	configFile := fs.String("config", "", "YAML/TOML konfigürasyon dosyası (CONFIG_FILE yerine)")
+	fs.Usage = func() { usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	if *configFile != "" {
+		os.Setenv("CONFIG_FILE", *configFile)
+	}
+
+	name := "serve"
+	rest := fs.Args()
+	if len(rest) > 0 {
+		name, rest = rest[0], rest[1:]
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		if _, err := config.Load(); err != nil {
+			return fmt.Errorf("konfigürasyon geçersiz:\n%w", err)
+		}
+		return cmd.run(rest)
+	}
+
+	usage(fs)
+	return fmt.Errorf("bilinmeyen komut: %s", name)
+}
+
+func usage(fs *flag.FlagSet) {
+	out := fs.Output()
+	fmt.Fprintln(out, "Kullanım: tradesman-api [-config dosya] <komut> [argümanlar]")
+	fmt.Fprintln(out, "\nKomutlar:")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-14s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintln(out, "\nBayraklar:")
+	fs.PrintDefaults()
+}
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"tradesman-api/config"
+	"tradesman-api/models"
+)
+
+// testEnv komutların kullanacağı geçici bir SQLite veritabanını ayarlar ve
+// dosya yolunu döner.
+func testEnv(t *testing.T, env string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.db")
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("APP_ENV", env)
+	t.Setenv("DB_DRIVER", config.DriverSQLite)
+	t.Setenv("DB_DSN", path)
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("LOG_LEVEL", "error")
+	return path
+}
+
+// run komutu çalıştırır ve standart çıktısını döner. Komutun açtığı veritabanı
+// bağlantısı test bitince kapatılır.
+func run(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	output := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		output <- buf.String()
+	}()
+
+	err = Run(args)
+	os.Stdout = stdout
+	w.Close()
+	out := <-output
+
+	if db := config.DB; db != nil {
+		t.Cleanup(func() {
+			if sqlDB, err := db.DB(); err == nil {
+				sqlDB.Close()
+			}
+		})
+	}
+	return out, err
+}
+
+func TestServeRefusesDefaultSecretInProduction(t *testing.T) {
+	for name, secret := range map[string]string{
+		"varsayılan": config.DefaultJWTSecret,
+		"boş":        "",
+		"kısa":       "kisa-bir-secret",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := testEnv(t, config.EnvProduction)
+			t.Setenv("JWT_SECRET", secret)
+
+			err := Run([]string{"serve"})
+			if err == nil || !strings.Contains(err.Error(), "production modunda") {
+				t.Fatalf("hata %v, beklenen production JWT secret hatası", err)
+			}
+			// Sunucu veritabanına bağlanmadan durur
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("veritabanı dosyası oluşturulmuş: %v", err)
+			}
+		})
+	}
+
+	// Geliştirme ortamında varsayılan secret yalnızca uyarı verir
+	testEnv(t, config.EnvDevelopment)
+	if _, err := config.Load(); err != nil {
+		t.Errorf("geliştirme ortamında yükleme hatası: %v", err)
+	}
+	testEnv(t, config.EnvProduction)
+	t.Setenv("JWT_SECRET", strings.Repeat("s", 32))
+	if _, err := config.Load(); err != nil {
+		t.Errorf("güçlü secret ile production yükleme hatası: %v", err)
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	testEnv(t, config.EnvDevelopment)
+	if _, err := run(t, "deploy"); err == nil || !strings.Contains(err.Error(), "bilinmeyen komut: deploy") {
+		t.Errorf("hata %v, beklenen bilinmeyen komut", err)
+	}
+	if _, err := run(t, "-config", filepath.Join(t.TempDir(), "yok.yaml"), "routes"); err == nil || !strings.Contains(err.Error(), "konfigürasyon") {
+		t.Errorf("hata %v, beklenen konfigürasyon hatası", err)
+	}
+}
+
+func TestMigrateCommand(t *testing.T) {
+	testEnv(t, config.EnvDevelopment)
+
+	out, err := run(t, "migrate", "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "uygulandı") || !strings.Contains(out, "baseline") || !strings.Contains(out, "bekliyor") {
+		t.Errorf("boş veritabanında durum beklenen gibi değil:\n%s", out)
+	}
+
+	out, err = run(t, "migrate", "up")
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := strings.Count(out, "⬆️")
+	if total == 0 {
+		t.Fatalf("hiç migrasyon uygulanmadı:\n%s", out)
+	}
+	if out, _ := run(t, "migrate", "up"); !strings.Contains(out, "Şema güncel") {
+		t.Errorf("ikinci up çıktısı beklenen gibi değil:\n%s", out)
+	}
+
+	out, err = run(t, "migrate", "down", "-steps", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out, "⬇️"); n != 2 {
+		t.Errorf("%d migrasyon geri alındı, beklenen 2:\n%s", n, out)
+	}
+	out, _ = run(t, "migrate", "status")
+	if strings.Count(out, "bekliyor") != 2 || strings.Count(out, "uygulandı") != total-2 {
+		t.Errorf("geri almadan sonra durum beklenen gibi değil:\n%s", out)
+	}
+	if out, _ := run(t, "migrate", "up"); strings.Count(out, "⬆️") != 2 {
+		t.Errorf("geri alınanlar yeniden uygulanmadı:\n%s", out)
+	}
+
+	for _, args := range [][]string{{"migrate"}, {"migrate", "down", "-steps", "0"}, {"migrate", "sideways"}} {
+		if _, err := run(t, args...); err == nil {
+			t.Errorf("%v: hata bekleniyordu", args)
+		}
+	}
+}
+
+func TestCreateAdminCommand(t *testing.T) {
+	testEnv(t, config.EnvDevelopment)
+	t.Setenv("ADMIN_PASSWORD", "")
+
+	for _, args := range [][]string{
+		{"create-admin", "-email", "admin@example.com"},
+		{"create-admin", "-email", "admin", "-password", "12345678"},
+		{"create-admin", "-email", "admin@example.com", "-password", "kisa"},
+	} {
+		if _, err := run(t, args...); err == nil {
+			t.Errorf("%v: hata bekleniyordu", args)
+		}
+	}
+
+	out, err := run(t, "create-admin", "-email", "admin@example.com", "-password", "12345678", "-name", "Yönetici")
+	if err != nil || !strings.Contains(out, "Admin oluşturuldu") {
+		t.Fatalf("admin oluşturulamadı: %v\n%s", err, out)
+	}
+	var admin models.User
+	if err := config.DB.Where("email = ?", "admin@example.com").First(&admin).Error; err != nil {
+		t.Fatal(err)
+	}
+	if admin.Role != models.RoleAdmin || admin.Name != "Yönetici" || !admin.EmailVerified() {
+		t.Errorf("admin beklenen gibi değil: %+v", admin)
+	}
+
+	// Şifre ADMIN_PASSWORD'den de okunur; kayıtlı adres tekrar kullanılamaz
+	t.Setenv("ADMIN_PASSWORD", "87654321")
+	if _, err := run(t, "create-admin", "-email", "admin@example.com"); err == nil || !strings.Contains(err.Error(), "zaten kayıtlı") {
+		t.Errorf("hata %v, beklenen zaten kayıtlı", err)
+	}
+}
+
+func TestSeedAndStockCheckCommands(t *testing.T) {
+	testEnv(t, config.EnvDevelopment)
+
+	if _, err := run(t, "seed", "-password", "demo1234"); err != nil {
+		t.Fatal(err)
+	}
+	var users, products int64
+	config.DB.Model(&models.User{}).Count(&users)
+	config.DB.Model(&models.Product{}).Count(&products)
+	if users == 0 || products == 0 {
+		t.Fatalf("demo veri oluşturulmadı: %d kullanıcı, %d ürün", users, products)
+	}
+
+	// Tekrar çalıştırıldığında mevcut kayıtlar atlanır
+	out, err := run(t, "seed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out, "zaten var, atlandı"); int64(n) != users {
+		t.Errorf("%d kullanıcı atlandı, beklenen %d:\n%s", n, users, out)
+	}
+	var count int64
+	config.DB.Model(&models.Product{}).Count(&count)
+	if count != products {
+		t.Errorf("ikinci seed'den sonra %d ürün, beklenen %d", count, products)
+	}
+
+	if out, err := run(t, "reindex"); err != nil || !strings.Contains(out, "ürün arama indeksine eklendi") {
+		t.Errorf("reindex: %v\n%s", err, out)
+	}
+
+	if out, err := run(t, "stock-check"); err != nil || !strings.Contains(out, "tutarlı") {
+		t.Fatalf("seed sonrası stok defteri tutarsız: %v\n%s", err, out)
+	}
+
+	// Defter dışı değiştirilen stok bulunur ve -fix ile düzeltilir
+	var product models.Product
+	config.DB.Order("id").First(&product)
+	ledger := product.Stock
+	config.DB.Model(&models.Product{}).Where("id = ?", product.ID).UpdateColumn("stock", ledger+3)
+	if _, err := run(t, "stock-check"); err == nil || !strings.Contains(err.Error(), "1 kalemde") {
+		t.Errorf("hata %v, beklenen 1 kalemde tutarsızlık", err)
+	}
+	if out, err := run(t, "stock-check", "-fix"); err != nil || !strings.Contains(out, "1 kalemin stoğu") {
+		t.Errorf("stock-check -fix: %v\n%s", err, out)
+	}
+	var fixed models.Product
+	config.DB.First(&fixed, product.ID)
+	if fixed.Stock != ledger {
+		t.Errorf("düzeltilen stok %v, beklenen %v", fixed.Stock, ledger)
+	}
+}
+
+func TestRoutesCommand(t *testing.T) {
+	testEnv(t, config.EnvDevelopment)
+
+	out, err := run(t, "routes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"METHOD", "POST    /auth/login", "GET     /products/:id", "controllers.(*ProductController).GetProduct"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("route tablosunda %q yok:\n%s", want, out)
+		}
+	}
+}
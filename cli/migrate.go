@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"tradesman-api/config"
+	"tradesman-api/migrations"
+)
+
+func runMigrate(args []string) error {
+	config.Connect()
+
+	if len(args) == 0 {
+		return errors.New("kullanım: migrate up | down [-steps N] | status")
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrations.Up(config.DB)
+		for _, m := range applied {
+			fmt.Printf("⬆️  %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("Şema güncel, uygulanacak migrasyon yok")
+		}
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := fs.Int("steps", 1, "geri alınacak migrasyon sayısı")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *steps < 1 {
+			return errors.New("steps en az 1 olmalı")
+		}
+
+		rolledBack, err := migrations.Down(config.DB, *steps)
+		for _, m := range rolledBack {
+			fmt.Printf("⬇️  %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(rolledBack) == 0 {
+			fmt.Println("Geri alınacak migrasyon yok")
+		}
+
+	case "status":
+		list, err := migrations.StatusList(config.DB)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSİYON\tAD\tDURUM\tUYGULANMA")
+		for _, s := range list {
+			state, appliedAt := "bekliyor", "-"
+			if s.Applied {
+				state = "uygulandı"
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Unknown {
+				state = "bilinmiyor"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("bilinmeyen migrate komutu: %s", args[0])
+	}
+
+	return nil
+}
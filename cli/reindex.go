@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"fmt"
+	"tradesman-api/config"
+	"tradesman-api/search"
+
+	"gorm.io/gorm"
+)
+
+func runReindex(args []string) error {
+	config.InitDatabase()
+
+	var count int
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		count, err = search.Rebuild(tx)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("arama indeksi oluşturulamadı: %w", err)
+	}
+
+	fmt.Printf("🔎 %d ürün arama indeksine eklendi\n", count)
+	return nil
+}
@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"time"
+	"tradesman-api/config"
+	"tradesman-api/inventory"
+	"tradesman-api/models"
+	"tradesman-api/money"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type seedProduct struct {
+	Name        string
+	Description string
+	Price       money.Amount // kuruş
+	Stock       float64
+	Unit        models.Unit
+	Category    string // kategori slug'ı
+}
+
+type seedCategory struct {
+	Name     string
+	Slug     string
+	Children []seedCategory
+}
+
+var demoCategories = []seedCategory{
+	{"Fırın & Pastane", "firin-pastane", []seedCategory{
+		{"Ekmek", "ekmek", nil},
+		{"Unlu Mamuller", "unlu-mamuller", nil},
+	}},
+	{"Meyve & Sebze", "meyve-sebze", []seedCategory{
+		{"Sebze", "sebze", nil},
+		{"Yeşillik", "yesillik", nil},
+	}},
+	{"Et & Tavuk", "et-tavuk", []seedCategory{
+		{"Kırmızı Et", "kirmizi-et", nil},
+	}},
+}
+
+type seedShop struct {
+	OwnerName   string
+	Email       string
+	Phone       string
+	Name        string
+	Description string
+	Address     string
+	Products    []seedProduct
+}
+
+var demoShops = []seedShop{
+	{
+		OwnerName: "Ahmet Yılmaz", Email: "firin@example.com", Phone: "0555-111-1111",
+		Name: "Yılmaz Fırını", Description: "Her sabah taze ekmek ve simit", Address: "Çarşı Sokak No:3",
+		Products: []seedProduct{
+			{"Ekmek", "Günlük taze ekmek", 10_00, 200, models.UnitPiece, "ekmek"},
+			{"Simit", "Sade veya susamlı simit", 15_00, 150, models.UnitPiece, "unlu-mamuller"},
+			{"Poğaça", "Peynirli poğaça", 20_00, 80, models.UnitPiece, "unlu-mamuller"},
+		},
+	},
+	{
+		OwnerName: "Ayşe Demir", Email: "manav@example.com", Phone: "0555-222-2222",
+		Name: "Demir Manav", Description: "Mevsim sebze ve meyveleri", Address: "Pazar Caddesi No:12",
+		Products: []seedProduct{
+			{"Domates", "Yerli domates", 35_00, 60, models.UnitKg, "sebze"},
+			{"Salatalık", "Çengelköy salatalık", 30_00, 40, models.UnitKg, "sebze"},
+			{"Maydanoz", "Taze maydanoz", 10_00, 50, models.UnitBunch, "yesillik"},
+		},
+	},
+	{
+		OwnerName: "Mehmet Kaya", Email: "kasap@example.com", Phone: "0555-333-3333",
+		Name: "Kaya Kasap", Description: "Günlük kesim dana ve kuzu eti", Address: "Meydan Sokak No:7",
+		Products: []seedProduct{
+			{"Dana Kıyma", "Yağsız dana kıyma", 450_00, 25, models.UnitKg, "kirmizi-et"},
+			{"Kuzu Pirzola", "Kuzu pirzola", 650_00, 15, models.UnitKg, "kirmizi-et"},
+		},
+	},
+}
+
+// Çeşidi ve seçenek grubu olan demo ürünler (ürün adına göre)
+var demoVariants = map[string][]models.ProductVariant{
+	"Simit": {
+		{Name: "Sade", SKU: "SIMIT-SADE", Price: 12_00, Stock: 50, IsActive: true},
+		{Name: "Susamlı", SKU: "SIMIT-SUSAM", Price: 15_00, Stock: 100, IsActive: true, SortOrder: 1},
+	},
+}
+
+var demoOptionGroups = map[string][]models.OptionGroup{
+	"Poğaça": {
+		{Name: "Ekstralar", MinSelect: 0, MaxSelect: 2, Options: []models.ProductOption{
+			{Name: "Ekstra peynir", PriceModifier: 10_00, IsActive: true},
+			{Name: "Zeytin", PriceModifier: 5_00, IsActive: true, SortOrder: 1},
+		}},
+	},
+}
+
+var demoCustomers = []struct {
+	Name  string
+	Email string
+	Phone string
+}{
+	{"Zeynep Çelik", "musteri1@example.com", "0555-444-4444"},
+	{"Can Öztürk", "musteri2@example.com", "0555-555-5555"},
+}
+
+func runSeed(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	password := fs.String("password", "123456", "demo kullanıcıların şifresi")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	config.InitDatabase()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("şifre hashlenemedi: %w", err)
+	}
+
+	// Daha önce oluşturulmuş demo kayıtlar atlanır, komut tekrar çalıştırılabilir
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		categories := make(map[string]uint)
+		if err := seedCategories(tx, demoCategories, nil, categories); err != nil {
+			return err
+		}
+
+		for _, s := range demoShops {
+			owner, created, err := seedUser(tx, s.OwnerName, s.Email, s.Phone, string(hashed), models.RoleShop)
+			if err != nil {
+				return err
+			}
+			if !created {
+				fmt.Printf("• %s zaten var, atlandı\n", s.Email)
+				continue
+			}
+
+			shop := models.Shop{
+				UserID:      owner.ID,
+				Name:        s.Name,
+				Description: s.Description,
+				Address:     s.Address,
+				Phone:       s.Phone,
+				IsActive:    true,
+			}
+			if err := tx.Create(&shop).Error; err != nil {
+				return fmt.Errorf("%s oluşturulamadı: %w", s.Name, err)
+			}
+
+			for _, p := range s.Products {
+				categoryID := categories[p.Category]
+				product := models.Product{
+					ShopID:      shop.ID,
+					CategoryID:  &categoryID,
+					Name:        p.Name,
+					Description: p.Description,
+					Price:       p.Price,
+					Currency:    money.DefaultCurrency,
+					Unit:        p.Unit,
+					IsActive:    true,
+				}
+				product.ApplyUnitDefaults()
+				if err := tx.Create(&product).Error; err != nil {
+					return fmt.Errorf("%s oluşturulamadı: %w", p.Name, err)
+				}
+				if err := seedOpeningStock(tx, product, nil, p.Stock, owner.ID); err != nil {
+					return err
+				}
+				if err := seedProductOptions(tx, product, owner.ID); err != nil {
+					return err
+				}
+			}
+			fmt.Printf("🏪 %s (%s) ve %d ürün oluşturuldu\n", s.Name, s.Email, len(s.Products))
+		}
+
+		for _, cu := range demoCustomers {
+			_, created, err := seedUser(tx, cu.Name, cu.Email, cu.Phone, string(hashed), models.RoleCustomer)
+			if err != nil {
+				return err
+			}
+			if created {
+				fmt.Printf("🛒 Müşteri %s oluşturuldu\n", cu.Email)
+			} else {
+				fmt.Printf("• %s zaten var, atlandı\n", cu.Email)
+			}
+		}
+		return nil
+	})
+}
+
+// seedCategories kategori ağacını oluşturur; slug'ı zaten olan kategoriler
+// yeniden oluşturulmaz. Oluşan ID'ler slug'a göre ids içine yazılır.
+func seedCategories(tx *gorm.DB, list []seedCategory, parentID *uint, ids map[string]uint) error {
+	for i, sc := range list {
+		var category models.Category
+		err := tx.Where(models.Category{Slug: sc.Slug}).
+			Attrs(models.Category{Name: sc.Name, ParentID: parentID, SortOrder: i}).
+			FirstOrCreate(&category).Error
+		if err != nil {
+			return fmt.Errorf("%s kategorisi oluşturulamadı: %w", sc.Name, err)
+		}
+		ids[sc.Slug] = category.ID
+
+		if err := seedCategories(tx, sc.Children, &category.ID, ids); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedProductOptions ürünün demo çeşitlerini ve seçenek gruplarını oluşturur.
+func seedProductOptions(tx *gorm.DB, product models.Product, ownerID uint) error {
+	for _, v := range demoVariants[product.Name] {
+		stock := v.Stock
+		v.ProductID, v.ShopID, v.Stock = product.ID, product.ShopID, 0
+		if err := tx.Create(&v).Error; err != nil {
+			return fmt.Errorf("%s çeşidi oluşturulamadı: %w", v.Name, err)
+		}
+		if err := seedOpeningStock(tx, product, &v.ID, stock, ownerID); err != nil {
+			return err
+		}
+	}
+	for _, g := range demoOptionGroups[product.Name] {
+		g.ProductID = product.ID
+		g.Options = append([]models.ProductOption(nil), g.Options...)
+		if err := tx.Create(&g).Error; err != nil {
+			return fmt.Errorf("%s seçenek grubu oluşturulamadı: %w", g.Name, err)
+		}
+	}
+	return nil
+}
+
+// seedOpeningStock demo stoğu deftere açılış hareketi olarak yazar.
+func seedOpeningStock(tx *gorm.DB, product models.Product, variantID *uint, stock float64, ownerID uint) error {
+	if stock == 0 {
+		return nil
+	}
+	movement := models.StockMovement{
+		ShopID:    product.ShopID,
+		ProductID: product.ID,
+		VariantID: variantID,
+		Type:      models.StockMovementAdjustment,
+		Quantity:  stock,
+		ActorID:   &ownerID,
+		Reason:    "Açılış stoğu",
+	}
+	if err := inventory.Record(tx, &movement); err != nil {
+		return fmt.Errorf("%s stoğu oluşturulamadı: %w", product.Name, err)
+	}
+	return nil
+}
+
+func seedUser(tx *gorm.DB, name, email, phone, hashedPassword string, role models.UserRole) (models.User, bool, error) {
+	var user models.User
+	var count int64
+	if err := tx.Unscoped().Model(&models.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
+		return user, false, err
+	}
+	if count > 0 {
+		return user, false, nil
+	}
+
+	verifiedAt := time.Now()
+	user = models.User{
+		Name:            name,
+		Email:           email,
+		Password:        hashedPassword,
+		Phone:           phone,
+		Role:            role,
+		EmailVerifiedAt: &verifiedAt, // Demo adresleri doğrulanmış sayılır
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return user, false, fmt.Errorf("%s oluşturulamadı: %w", email, err)
+	}
+	return user, true, nil
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"log"
+	"tradesman-api/config"
+	"tradesman-api/models"
+	"tradesman-api/notify"
+	"tradesman-api/routes"
+	"tradesman-api/storage"
+	"tradesman-api/webhooks"
+
+	"github.com/gin-gonic/gin"
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := config.App
+	if cfg.IsProduction() {
+		gin.SetMode(gin.ReleaseMode)
+	} else if cfg.JWTSecret == config.DefaultJWTSecret {
+		log.Println("⚠️  Varsayılan JWT secret kullanılıyor, production için JWT_SECRET ayarlayın")
+	}
+
+	// Veritabanı bağlantısı
+	config.InitDatabase()
+	config.BootstrapAdmin()
+
+	// Yüklenen dosyaların deposu
+	storage.Default = storage.NewLocal(cfg.StorageDir, cfg.StorageURL)
+
+	// Webhook teslimatları arka planda gönderilir
+	webhooks.Default = webhooks.NewDispatcher(config.DB, cfg.WebhookTimeout, cfg.WebhookMaxAttempts, cfg.WebhookAllowPrivate)
+	go webhooks.Default.Run(context.Background())
+
+	// Bildirimler arka planda gönderilir
+	notify.Default = notify.NewService(config.DB, notificationChannels(cfg))
+	go notify.Default.Run(context.Background())
+
+	// Routes kurulumu
+	r := routes.SetupRoutes()
+
+	// Server başlat
+	log.Printf("🚀 Server başlatılıyor... http://localhost%s", cfg.Addr())
+	log.Printf("📚 Swagger dokümantasyonu: http://localhost%s/swagger/index.html", cfg.Addr())
+
+	return r.Run(cfg.Addr())
+}
+
+// notificationChannels bildirim kanallarını kurar. SMTP ayarlanmışsa e-postalar
+// gönderilir; SMS, push ve SMTP'siz e-postalar giden kutusuna yazılır.
+func notificationChannels(cfg *config.Config) map[models.NotificationChannel]notify.Channel {
+	var outbox notify.Channel
+	switch cfg.NotifyOutbox {
+	case config.NotifyOutboxDatabase:
+		outbox = notify.Outbox{DB: config.DB}
+	case config.NotifyOutboxLog:
+		outbox = notify.Log{}
+	default:
+		outbox = notify.NewFile(cfg.NotifyOutbox)
+	}
+
+	email := outbox
+	if cfg.SMTPHost != "" {
+		email = notify.SMTP{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		}
+	}
+
+	return map[models.NotificationChannel]notify.Channel{
+		models.ChannelEmail: email,
+		models.ChannelSMS:   outbox,
+		models.ChannelPush:  outbox,
+	}
+}
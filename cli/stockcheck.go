@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"tradesman-api/config"
+	"tradesman-api/inventory"
+	"tradesman-api/models"
+
+	"gorm.io/gorm"
+)
+
+// runStockCheck stok kolonlarını stok defteriyle karşılaştırır; -fix ile
+// tutmayan kalemlerin stoğunu defter toplamına eşitler.
+func runStockCheck(args []string) error {
+	fs := flag.NewFlagSet("stock-check", flag.ContinueOnError)
+	fix := fs.Bool("fix", false, "stok kolonlarını defter toplamına eşitle")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	config.InitDatabase()
+
+	discrepancies, err := inventory.Check(config.DB)
+	if err != nil {
+		return fmt.Errorf("stok defteri okunamadı: %w", err)
+	}
+	if len(discrepancies) == 0 {
+		fmt.Println("✅ Tüm stoklar defterle tutarlı")
+		return nil
+	}
+
+	for _, d := range discrepancies {
+		item := fmt.Sprintf("ürün %d", d.ProductID)
+		if d.VariantID != nil {
+			item += fmt.Sprintf(", çeşit %d", *d.VariantID)
+		}
+		fmt.Printf("⚠️  %s: stok %s, defter %s\n", item, models.FormatQuantity(d.Stock), models.FormatQuantity(d.Ledger))
+	}
+
+	if !*fix {
+		return fmt.Errorf("%d kalemde stok defterle tutarsız (düzeltmek için -fix)", len(discrepancies))
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, d := range discrepancies {
+			if err := inventory.Fix(tx, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("stoklar düzeltilemedi: %w", err)
+	}
+	fmt.Printf("🔧 %d kalemin stoğu defter toplamına eşitlendi\n", len(discrepancies))
+	return nil
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"time"
+	"tradesman-api/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BootstrapAdmin, sistemde hiç admin yoksa konfigürasyondaki AdminEmail ve
+// AdminPassword (ADMIN_EMAIL, ADMIN_PASSWORD) ile ilk admin kullanıcısını oluşturur.
+func BootstrapAdmin() {
+	if App.AdminEmail == "" || App.AdminPassword == "" {
+		return
+	}
+
+	var count int64
+	if err := DB.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&count).Error; err != nil {
+		log.Fatal("Admin kontrolü yapılamadı:", err)
+	}
+	if count > 0 {
+		return
+	}
+
+	admin, err := CreateAdmin(App.AdminEmail, App.AdminPassword, App.AdminName)
+	if err != nil {
+		log.Fatal("Admin kullanıcısı oluşturulamadı: ", err)
+	}
+
+	log.Println("👑 İlk admin kullanıcısı oluşturuldu:", admin.Email)
+}
+
+// CreateAdmin verilen bilgilerle yeni bir admin kullanıcısı oluşturur.
+func CreateAdmin(email, password, name string) (*models.User, error) {
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, fmt.Errorf("geçersiz email: %q", email)
+	}
+	if len(password) < 8 {
+		return nil, errors.New("admin şifresi en az 8 karakter olmalı")
+	}
+	if name == "" {
+		name = "Admin"
+	}
+
+	var count int64
+	if err := DB.Model(&models.User{}).Where("email = ?", email).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("%s zaten kayıtlı, rolünü PUT /admin/users/{id}/role ile değiştirin", email)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("şifre hashlenemedi: %w", err)
+	}
+
+	// Adresi yönetici belirlediği için doğrulanmış sayılır
+	verifiedAt := time.Now()
+	admin := models.User{
+		Name:            name,
+		Email:           email,
+		Password:        string(hashedPassword),
+		Role:            models.RoleAdmin,
+		EmailVerifiedAt: &verifiedAt,
+	}
+	if err := DB.Create(&admin).Error; err != nil {
+		return nil, err
+	}
+
+	return &admin, nil
+}
@@ -0,0 +1,560 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Geliştirme ortamı için varsayılan JWT anahtarı. Production modunda kullanılamaz.
+const DefaultJWTSecret = "super-secret-key-change-in-production"
+
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+	EnvTest        = "test"
+)
+
+type Config struct {
+	Env             string
+	Port            int
+	DBDriver        string
+	DBDSN           string
+	DBPool          PoolConfig
+	MigrateOnStart  bool // Açılışta bekleyen migrasyonları uygula (production dışında varsayılan)
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	CORSOrigins     []string
+	TrustedProxies  []string // İstemci adresi için X-Forwarded-For başlığına güvenilen vekil sunucular
+	LogLevel        string
+	IdempotencyTTL  time.Duration // Idempotency-Key kayıtlarının saklanma süresi
+
+	// Hesap e-postaları (şifre sıfırlama, e-posta doğrulama)
+	AppURL               string        // Bağlantıların açılacağı istemci adresi
+	PasswordResetTTL     time.Duration // Şifre sıfırlama bağlantısının geçerlilik süresi
+	EmailVerificationTTL time.Duration // Doğrulama bağlantısının geçerlilik süresi
+	OTPTTL               time.Duration // Telefonla girişte SMS kodunun geçerlilik süresi
+	OTPIPHourlyLimit     int           // Bir IP adresinin saatte isteyebileceği en fazla SMS kodu
+	OTPHourlyLimit       int           // Tüm istemcilere saatte gönderilebilecek en fazla SMS kodu
+
+	// Yüklenen dosyalar (bkz. storage paketi)
+	StorageDir   string // Yerel depo dizini
+	StorageURL   string // Dosyaların sunulduğu adres; "/" ile başlıyorsa API sunar
+	MaxImageSize int    // Tek görselin en fazla boyutu (byte)
+
+	// Webhook teslimatları (bkz. webhooks paketi)
+	WebhookTimeout      time.Duration // Tek teslimat denemesinin süresi
+	WebhookMaxAttempts  int           // Teslimat başarısız sayılmadan önceki deneme sayısı
+	WebhookAllowPrivate bool          // Yerel ağ ve loopback adreslerine teslimat (production dışında varsayılan)
+
+	// Bildirimler (bkz. notify paketi). SMTPHost boşsa e-postalar da giden kutusuna yazılır.
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	NotifyOutbox string // Gönderilmeyen mesajların yazıldığı yer: "database", "log" veya dosya yolu
+
+	// İlk admin kullanıcısı (bkz. BootstrapAdmin)
+	AdminEmail    string
+	AdminPassword string
+	AdminName     string
+
+	migrateOnStartSet      bool
+	webhookAllowPrivateSet bool
+}
+
+// Veritabanı bağlantı havuzu ayarları. Sıfır değerler sürücü varsayılanını kullanır.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// NotifyOutbox değerleri; diğer değerler dosya yolu olarak kullanılır.
+const (
+	NotifyOutboxDatabase = "database" // notification_outbox tablosu
+	NotifyOutboxLog      = "log"      // Sunucu günlüğü
+)
+
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+var App *Config
+
+// Dosyadan okunan ayarlar. Süreler "15m", "720h" gibi metin olarak yazılır.
+type fileConfig struct {
+	Env      string `yaml:"env" toml:"env"`
+	Port     int    `yaml:"port" toml:"port"`
+	DBDriver string `yaml:"db_driver" toml:"db_driver"`
+	DBDSN    string `yaml:"db_dsn" toml:"db_dsn"`
+	DBPool   struct {
+		MaxOpenConns    int    `yaml:"max_open_conns" toml:"max_open_conns"`
+		MaxIdleConns    int    `yaml:"max_idle_conns" toml:"max_idle_conns"`
+		ConnMaxLifetime string `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+		ConnMaxIdleTime string `yaml:"conn_max_idle_time" toml:"conn_max_idle_time"`
+	} `yaml:"db_pool" toml:"db_pool"`
+	MigrateOnStart       *bool    `yaml:"migrate_on_start" toml:"migrate_on_start"`
+	JWTSecret            string   `yaml:"jwt_secret" toml:"jwt_secret"`
+	AccessTokenTTL       string   `yaml:"access_token_ttl" toml:"access_token_ttl"`
+	RefreshTokenTTL      string   `yaml:"refresh_token_ttl" toml:"refresh_token_ttl"`
+	CORSOrigins          []string `yaml:"cors_origins" toml:"cors_origins"`
+	TrustedProxies       []string `yaml:"trusted_proxies" toml:"trusted_proxies"`
+	LogLevel             string   `yaml:"log_level" toml:"log_level"`
+	IdempotencyTTL       string   `yaml:"idempotency_ttl" toml:"idempotency_ttl"`
+	AppURL               string   `yaml:"app_url" toml:"app_url"`
+	PasswordResetTTL     string   `yaml:"password_reset_ttl" toml:"password_reset_ttl"`
+	EmailVerificationTTL string   `yaml:"email_verification_ttl" toml:"email_verification_ttl"`
+	OTPTTL               string   `yaml:"otp_ttl" toml:"otp_ttl"`
+	OTPIPHourlyLimit     int      `yaml:"otp_ip_hourly_limit" toml:"otp_ip_hourly_limit"`
+	OTPHourlyLimit       int      `yaml:"otp_hourly_limit" toml:"otp_hourly_limit"`
+	StorageDir           string   `yaml:"storage_dir" toml:"storage_dir"`
+	StorageURL           string   `yaml:"storage_url" toml:"storage_url"`
+	MaxImageSize         int      `yaml:"max_image_size" toml:"max_image_size"`
+	WebhookTimeout       string   `yaml:"webhook_timeout" toml:"webhook_timeout"`
+	WebhookMaxAttempts   int      `yaml:"webhook_max_attempts" toml:"webhook_max_attempts"`
+	WebhookAllowPrivate  *bool    `yaml:"webhook_allow_private" toml:"webhook_allow_private"`
+	SMTPHost             string   `yaml:"smtp_host" toml:"smtp_host"`
+	SMTPPort             int      `yaml:"smtp_port" toml:"smtp_port"`
+	SMTPUsername         string   `yaml:"smtp_username" toml:"smtp_username"`
+	SMTPPassword         string   `yaml:"smtp_password" toml:"smtp_password"`
+	SMTPFrom             string   `yaml:"smtp_from" toml:"smtp_from"`
+	NotifyOutbox         string   `yaml:"notify_outbox" toml:"notify_outbox"`
+	AdminEmail           string   `yaml:"admin_email" toml:"admin_email"`
+	AdminPassword        string   `yaml:"admin_password" toml:"admin_password"`
+	AdminName            string   `yaml:"admin_name" toml:"admin_name"`
+}
+
+func defaults() *Config {
+	return &Config{
+		Env:      EnvDevelopment,
+		Port:     8080,
+		DBDriver: DriverSQLite,
+		DBDSN:    "tradesman.db",
+		DBPool: PoolConfig{
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: time.Hour,
+		},
+		JWTSecret:            DefaultJWTSecret,
+		AccessTokenTTL:       15 * time.Minute,
+		RefreshTokenTTL:      30 * 24 * time.Hour,
+		CORSOrigins:          []string{"*"},
+		LogLevel:             "info",
+		IdempotencyTTL:       24 * time.Hour,
+		AppURL:               "http://localhost:3000",
+		PasswordResetTTL:     time.Hour,
+		EmailVerificationTTL: 48 * time.Hour,
+		OTPTTL:               5 * time.Minute,
+		OTPIPHourlyLimit:     10,
+		OTPHourlyLimit:       1000,
+		StorageDir:           "uploads",
+		StorageURL:           "/uploads",
+		MaxImageSize:         5 << 20,
+		WebhookTimeout:       10 * time.Second,
+		WebhookMaxAttempts:   8,
+		SMTPPort:             587,
+		NotifyOutbox:         NotifyOutboxDatabase,
+		AdminName:            "Admin",
+	}
+}
+
+// Load ayarları sırasıyla varsayılanlardan, CONFIG_FILE ile verilen YAML/TOML
+// dosyasından ve ortam değişkenlerinden okur, doğrular ve App'e atar.
+func Load() (*Config, error) {
+	cfg := defaults()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	if !cfg.migrateOnStartSet {
+		cfg.MigrateOnStart = !cfg.IsProduction()
+	}
+	if !cfg.webhookAllowPrivateSet {
+		cfg.WebhookAllowPrivate = !cfg.IsProduction()
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	App = cfg
+	return cfg, nil
+}
+
+func (cfg *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("konfigürasyon dosyası okunamadı: %w", err)
+	}
+
+	var fc fileConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &fc)
+	case ".toml":
+		err = toml.Unmarshal(data, &fc)
+	default:
+		return fmt.Errorf("desteklenmeyen konfigürasyon dosyası türü: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("konfigürasyon dosyası çözümlenemedi: %w", err)
+	}
+
+	setString(&cfg.Env, fc.Env)
+	if fc.Port != 0 {
+		cfg.Port = fc.Port
+	}
+	setString(&cfg.DBDriver, fc.DBDriver)
+	setString(&cfg.DBDSN, fc.DBDSN)
+	setString(&cfg.JWTSecret, fc.JWTSecret)
+	setString(&cfg.LogLevel, fc.LogLevel)
+	setString(&cfg.AdminEmail, fc.AdminEmail)
+	setString(&cfg.AdminPassword, fc.AdminPassword)
+	setString(&cfg.AdminName, fc.AdminName)
+	setString(&cfg.StorageDir, fc.StorageDir)
+	setString(&cfg.StorageURL, fc.StorageURL)
+	if fc.MaxImageSize != 0 {
+		cfg.MaxImageSize = fc.MaxImageSize
+	}
+	if len(fc.CORSOrigins) > 0 {
+		cfg.CORSOrigins = fc.CORSOrigins
+	}
+	if len(fc.TrustedProxies) > 0 {
+		cfg.TrustedProxies = fc.TrustedProxies
+	}
+	setString(&cfg.SMTPHost, fc.SMTPHost)
+	setString(&cfg.SMTPUsername, fc.SMTPUsername)
+	setString(&cfg.SMTPPassword, fc.SMTPPassword)
+	setString(&cfg.SMTPFrom, fc.SMTPFrom)
+	setString(&cfg.NotifyOutbox, fc.NotifyOutbox)
+	setString(&cfg.AppURL, fc.AppURL)
+	if fc.SMTPPort != 0 {
+		cfg.SMTPPort = fc.SMTPPort
+	}
+	if fc.OTPIPHourlyLimit != 0 {
+		cfg.OTPIPHourlyLimit = fc.OTPIPHourlyLimit
+	}
+	if fc.OTPHourlyLimit != 0 {
+		cfg.OTPHourlyLimit = fc.OTPHourlyLimit
+	}
+	if fc.WebhookMaxAttempts != 0 {
+		cfg.WebhookMaxAttempts = fc.WebhookMaxAttempts
+	}
+	if fc.WebhookAllowPrivate != nil {
+		cfg.WebhookAllowPrivate = *fc.WebhookAllowPrivate
+		cfg.webhookAllowPrivateSet = true
+	}
+	if fc.MigrateOnStart != nil {
+		cfg.MigrateOnStart = *fc.MigrateOnStart
+		cfg.migrateOnStartSet = true
+	}
+	if fc.DBPool.MaxOpenConns != 0 {
+		cfg.DBPool.MaxOpenConns = fc.DBPool.MaxOpenConns
+	}
+	if fc.DBPool.MaxIdleConns != 0 {
+		cfg.DBPool.MaxIdleConns = fc.DBPool.MaxIdleConns
+	}
+	if err := setDuration(&cfg.DBPool.ConnMaxLifetime, "db_pool.conn_max_lifetime", fc.DBPool.ConnMaxLifetime); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.DBPool.ConnMaxIdleTime, "db_pool.conn_max_idle_time", fc.DBPool.ConnMaxIdleTime); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.IdempotencyTTL, "idempotency_ttl", fc.IdempotencyTTL); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.WebhookTimeout, "webhook_timeout", fc.WebhookTimeout); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.PasswordResetTTL, "password_reset_ttl", fc.PasswordResetTTL); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.EmailVerificationTTL, "email_verification_ttl", fc.EmailVerificationTTL); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.OTPTTL, "otp_ttl", fc.OTPTTL); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.AccessTokenTTL, "access_token_ttl", fc.AccessTokenTTL); err != nil {
+		return err
+	}
+	return setDuration(&cfg.RefreshTokenTTL, "refresh_token_ttl", fc.RefreshTokenTTL)
+}
+
+func (cfg *Config) loadEnv() error {
+	setString(&cfg.Env, os.Getenv("APP_ENV"))
+	if err := setInt(&cfg.Port, "PORT", os.Getenv("PORT")); err != nil {
+		return err
+	}
+	setString(&cfg.DBDriver, os.Getenv("DB_DRIVER"))
+	setString(&cfg.DBDSN, os.Getenv("DB_DSN"))
+	if err := setInt(&cfg.DBPool.MaxOpenConns, "DB_MAX_OPEN_CONNS", os.Getenv("DB_MAX_OPEN_CONNS")); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.DBPool.MaxIdleConns, "DB_MAX_IDLE_CONNS", os.Getenv("DB_MAX_IDLE_CONNS")); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.DBPool.ConnMaxLifetime, "DB_CONN_MAX_LIFETIME", os.Getenv("DB_CONN_MAX_LIFETIME")); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.DBPool.ConnMaxIdleTime, "DB_CONN_MAX_IDLE_TIME", os.Getenv("DB_CONN_MAX_IDLE_TIME")); err != nil {
+		return err
+	}
+	if value := os.Getenv("DB_MIGRATE_ON_START"); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("DB_MIGRATE_ON_START true/false olmalı: %q", value)
+		}
+		cfg.MigrateOnStart = b
+		cfg.migrateOnStartSet = true
+	}
+	setString(&cfg.JWTSecret, os.Getenv("JWT_SECRET"))
+	setString(&cfg.LogLevel, os.Getenv("LOG_LEVEL"))
+	setString(&cfg.AdminEmail, os.Getenv("ADMIN_EMAIL"))
+	setString(&cfg.AdminPassword, os.Getenv("ADMIN_PASSWORD"))
+	setString(&cfg.AdminName, os.Getenv("ADMIN_NAME"))
+	setString(&cfg.StorageDir, os.Getenv("STORAGE_DIR"))
+	setString(&cfg.StorageURL, os.Getenv("STORAGE_URL"))
+	if err := setInt(&cfg.MaxImageSize, "MAX_IMAGE_SIZE", os.Getenv("MAX_IMAGE_SIZE")); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.WebhookTimeout, "WEBHOOK_TIMEOUT", os.Getenv("WEBHOOK_TIMEOUT")); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.WebhookMaxAttempts, "WEBHOOK_MAX_ATTEMPTS", os.Getenv("WEBHOOK_MAX_ATTEMPTS")); err != nil {
+		return err
+	}
+	if value := os.Getenv("WEBHOOK_ALLOW_PRIVATE"); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("WEBHOOK_ALLOW_PRIVATE true/false olmalı: %q", value)
+		}
+		cfg.WebhookAllowPrivate = b
+		cfg.webhookAllowPrivateSet = true
+	}
+	setString(&cfg.SMTPHost, os.Getenv("SMTP_HOST"))
+	if err := setInt(&cfg.SMTPPort, "SMTP_PORT", os.Getenv("SMTP_PORT")); err != nil {
+		return err
+	}
+	setString(&cfg.SMTPUsername, os.Getenv("SMTP_USERNAME"))
+	setString(&cfg.SMTPPassword, os.Getenv("SMTP_PASSWORD"))
+	setString(&cfg.SMTPFrom, os.Getenv("SMTP_FROM"))
+	setString(&cfg.NotifyOutbox, os.Getenv("NOTIFY_OUTBOX"))
+	setString(&cfg.AppURL, os.Getenv("APP_URL"))
+	if err := setDuration(&cfg.PasswordResetTTL, "PASSWORD_RESET_TTL", os.Getenv("PASSWORD_RESET_TTL")); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.EmailVerificationTTL, "EMAIL_VERIFICATION_TTL", os.Getenv("EMAIL_VERIFICATION_TTL")); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.OTPTTL, "OTP_TTL", os.Getenv("OTP_TTL")); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.OTPIPHourlyLimit, "OTP_IP_HOURLY_LIMIT", os.Getenv("OTP_IP_HOURLY_LIMIT")); err != nil {
+		return err
+	}
+	if err := setInt(&cfg.OTPHourlyLimit, "OTP_HOURLY_LIMIT", os.Getenv("OTP_HOURLY_LIMIT")); err != nil {
+		return err
+	}
+	if origins := os.Getenv("CORS_ORIGINS"); origins != "" {
+		cfg.CORSOrigins = nil
+		for _, origin := range strings.Split(origins, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				cfg.CORSOrigins = append(cfg.CORSOrigins, origin)
+			}
+		}
+	}
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		cfg.TrustedProxies = nil
+		for _, proxy := range strings.Split(proxies, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				cfg.TrustedProxies = append(cfg.TrustedProxies, proxy)
+			}
+		}
+	}
+	if err := setDuration(&cfg.IdempotencyTTL, "IDEMPOTENCY_TTL", os.Getenv("IDEMPOTENCY_TTL")); err != nil {
+		return err
+	}
+	if err := setDuration(&cfg.AccessTokenTTL, "ACCESS_TOKEN_TTL", os.Getenv("ACCESS_TOKEN_TTL")); err != nil {
+		return err
+	}
+	return setDuration(&cfg.RefreshTokenTTL, "REFRESH_TOKEN_TTL", os.Getenv("REFRESH_TOKEN_TTL"))
+}
+
+// Validate tüm ayar hatalarını tek seferde döner.
+func (cfg *Config) Validate() error {
+	var errs []error
+
+	switch cfg.Env {
+	case EnvDevelopment, EnvProduction, EnvTest:
+	default:
+		errs = append(errs, fmt.Errorf("geçersiz ortam: %q (development, production, test)", cfg.Env))
+	}
+
+	if cfg.Port < 1 || cfg.Port > 65535 {
+		errs = append(errs, fmt.Errorf("geçersiz port: %d", cfg.Port))
+	}
+
+	switch cfg.DBDriver {
+	case DriverSQLite, DriverPostgres:
+	default:
+		errs = append(errs, fmt.Errorf("desteklenmeyen veritabanı sürücüsü: %q (sqlite, postgres)", cfg.DBDriver))
+	}
+
+	if cfg.DBDSN == "" {
+		errs = append(errs, errors.New("veritabanı DSN boş olamaz"))
+	}
+
+	if cfg.DBPool.MaxOpenConns < 0 || cfg.DBPool.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("bağlantı havuzu limitleri negatif olamaz"))
+	}
+	if cfg.DBPool.MaxOpenConns > 0 && cfg.DBPool.MaxIdleConns > cfg.DBPool.MaxOpenConns {
+		errs = append(errs, errors.New("boşta bağlantı sayısı açık bağlantı limitini aşamaz"))
+	}
+
+	if cfg.JWTSecret == "" {
+		errs = append(errs, errors.New("JWT secret boş olamaz"))
+	}
+	if cfg.IsProduction() {
+		if cfg.JWTSecret == DefaultJWTSecret {
+			errs = append(errs, errors.New("production modunda varsayılan JWT secret kullanılamaz"))
+		} else if len(cfg.JWTSecret) < 32 {
+			errs = append(errs, errors.New("production modunda JWT secret en az 32 karakter olmalı"))
+		}
+	}
+
+	if cfg.AccessTokenTTL <= 0 {
+		errs = append(errs, errors.New("access token süresi pozitif olmalı"))
+	}
+	if cfg.RefreshTokenTTL <= cfg.AccessTokenTTL {
+		errs = append(errs, errors.New("yenileme token süresi access token süresinden uzun olmalı"))
+	}
+
+	if cfg.IdempotencyTTL <= 0 {
+		errs = append(errs, errors.New("idempotency süresi pozitif olmalı"))
+	}
+
+	if u, err := url.Parse(cfg.AppURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("geçersiz uygulama adresi: %q (http veya https)", cfg.AppURL))
+	}
+	if cfg.PasswordResetTTL <= 0 || cfg.EmailVerificationTTL <= 0 {
+		errs = append(errs, errors.New("şifre sıfırlama ve e-posta doğrulama süreleri pozitif olmalı"))
+	}
+	if cfg.OTPTTL < time.Minute || cfg.OTPTTL > 30*time.Minute {
+		errs = append(errs, fmt.Errorf("SMS kodu süresi 1 ile 30 dakika arasında olmalı: %s", cfg.OTPTTL))
+	}
+	if cfg.OTPIPHourlyLimit <= 0 || cfg.OTPHourlyLimit <= 0 {
+		errs = append(errs, errors.New("SMS kodu gönderim sınırları pozitif olmalı"))
+	}
+	for _, proxy := range cfg.TrustedProxies {
+		if !validProxy(proxy) {
+			errs = append(errs, fmt.Errorf("geçersiz vekil sunucu adresi: %q (IP veya CIDR)", proxy))
+		}
+	}
+
+	if cfg.StorageDir == "" || cfg.StorageURL == "" {
+		errs = append(errs, errors.New("dosya deposu dizini ve adresi boş olamaz"))
+	}
+	if cfg.MaxImageSize <= 0 {
+		errs = append(errs, errors.New("görsel boyutu sınırı pozitif olmalı"))
+	}
+
+	if cfg.WebhookTimeout <= 0 {
+		errs = append(errs, errors.New("webhook zaman aşımı pozitif olmalı"))
+	}
+	if cfg.WebhookMaxAttempts < 1 || cfg.WebhookMaxAttempts > 20 {
+		errs = append(errs, fmt.Errorf("webhook deneme sayısı 1 ile 20 arasında olmalı: %d", cfg.WebhookMaxAttempts))
+	}
+
+	if cfg.SMTPHost != "" {
+		if cfg.SMTPPort < 1 || cfg.SMTPPort > 65535 {
+			errs = append(errs, fmt.Errorf("geçersiz SMTP portu: %d", cfg.SMTPPort))
+		}
+		if _, err := mail.ParseAddress(cfg.SMTPFrom); err != nil {
+			errs = append(errs, fmt.Errorf("SMTP gönderen adresi geçersiz: %q", cfg.SMTPFrom))
+		}
+	}
+	if cfg.NotifyOutbox == "" {
+		errs = append(errs, errors.New("bildirim giden kutusu boş olamaz (database, log veya dosya yolu)"))
+	}
+
+	if len(cfg.CORSOrigins) == 0 {
+		errs = append(errs, errors.New("en az bir CORS origin tanımlanmalı"))
+	}
+
+	switch cfg.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("geçersiz log seviyesi: %q (debug, info, warn, error)", cfg.LogLevel))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (cfg *Config) IsProduction() bool {
+	return cfg.Env == EnvProduction
+}
+
+func (cfg *Config) Addr() string {
+	return ":" + strconv.Itoa(cfg.Port)
+}
+
+// validProxy adresin bir IP veya CIDR bloğu olduğunu kontrol eder.
+func validProxy(value string) bool {
+	if _, _, err := net.ParseCIDR(value); err == nil {
+		return true
+	}
+	return net.ParseIP(value) != nil
+}
+
+func setString(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+func setInt(dst *int, name, value string) error {
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s sayı olmalı: %q", name, value)
+	}
+	*dst = n
+	return nil
+}
+
+func setDuration(dst *time.Duration, name, value string) error {
+	if value == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%s geçerli bir süre olmalı (ör. 15m, 720h): %q", name, value)
+	}
+	*dst = d
+	return nil
+}
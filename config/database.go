@@ -2,32 +2,112 @@ package config
 
 import (
 	"log"
-	"tradesman-api/models"
+	"strings"
+	"tradesman-api/migrations"
+	"tradesman-api/search"
 
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
 
-func InitDatabase() {
+// Connect veritabanı bağlantısını açar ve bağlantı havuzunu ayarlar.
+func Connect() {
 	var err error
-	DB, err = gorm.Open(sqlite.Open("tradesman.db"), &gorm.Config{})
+	DB, err = gorm.Open(dialector(App.DBDriver, App.DBDSN), &gorm.Config{
+		Logger: logger.Default.LogMode(gormLogLevel(App.LogLevel)),
+	})
 	if err != nil {
 		log.Fatal("Veritabanına bağlanılamadı:", err)
 	}
 
-	// Auto Migration
-	err = DB.AutoMigrate(
-		&models.User{},
-		&models.Shop{},
-		&models.Product{},
-		&models.Order{},
-		&models.OrderItem{},
-	)
+	sqlDB, err := DB.DB()
 	if err != nil {
-		log.Fatal("Veritabanı migrasyonu başarısız:", err)
+		log.Fatal("Veritabanı bağlantı havuzu alınamadı:", err)
+	}
+	sqlDB.SetMaxOpenConns(App.DBPool.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(App.DBPool.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(App.DBPool.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(App.DBPool.ConnMaxIdleTime)
+}
+
+// InitDatabase bağlantıyı açar ve şemanın güncel olduğundan emin olur.
+// MigrateOnStart kapalıysa bekleyen migrasyon varken server başlatılmaz.
+func InitDatabase() {
+	Connect()
+
+	if App.MigrateOnStart {
+		applied, err := migrations.Up(DB)
+		if err != nil {
+			log.Fatal("Veritabanı migrasyonu başarısız:", err)
+		}
+		for _, m := range applied {
+			log.Printf("⬆️  Migrasyon uygulandı: %04d_%s", m.Version, m.Name)
+		}
+	} else {
+		pending, err := migrations.Pending(DB)
+		if err != nil {
+			log.Fatal("Migrasyon durumu okunamadı:", err)
+		}
+		if len(pending) > 0 {
+			log.Fatalf("%d migrasyon bekliyor, önce `migrate up` çalıştırın", len(pending))
+		}
 	}
 
-	log.Println("✅ Veritabanı başarıyla bağlandı ve migrate edildi!")
+	if err := search.Check(DB); err != nil {
+		log.Fatal("Arama indeksi kullanılamıyor: ", err)
+	}
+
+	log.Printf("✅ Veritabanı (%s) başarıyla bağlandı, şema güncel!", App.DBDriver)
+}
+
+func dialector(driver, dsn string) gorm.Dialector {
+	if driver == DriverPostgres {
+		return postgres.Open(dsn)
+	}
+	return sqlite.Open(sqliteDSN(dsn))
+}
+
+// SQLite eşzamanlı yazmalar için varsayılan ayarlar. DSN'de açıkça verilenler ezilmez.
+//   - _busy_timeout: kilitli veritabanında hemen hata vermek yerine bekle
+//   - _journal_mode=WAL: okumalar yazmaları bloklamaz
+//   - _txlock=immediate: transaction'lar yazma kilidini baştan alır, okuma→yazma
+//     yükseltmesindeki "database is locked" kilitlenmeleri oluşmaz
+var sqliteDefaults = [][2]string{
+	{"_busy_timeout", "5000"},
+	{"_journal_mode", "WAL"},
+	{"_txlock", "immediate"},
+}
+
+func sqliteDSN(dsn string) string {
+	if strings.Contains(dsn, ":memory:") || strings.Contains(dsn, "mode=memory") {
+		return dsn
+	}
+
+	for _, param := range sqliteDefaults {
+		if strings.Contains(dsn, param[0]+"=") {
+			continue
+		}
+		if strings.Contains(dsn, "?") {
+			dsn += "&"
+		} else {
+			dsn += "?"
+		}
+		dsn += param[0] + "=" + param[1]
+	}
+	return dsn
+}
+
+func gormLogLevel(level string) logger.LogLevel {
+	switch level {
+	case "debug":
+		return logger.Info
+	case "error":
+		return logger.Error
+	default:
+		return logger.Warn
+	}
 }
@@ -0,0 +1,365 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"tradesman-api/config"
+	"tradesman-api/middleware"
+	"tradesman-api/models"
+	"tradesman-api/notify"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Hesap e-postalarının ve SMS kodlarının sınırları: aynı hesaba veya numaraya
+// aynı amaçla iki gönderim arasında en az sendCooldown beklenir, bir saatte en
+// fazla sendPerHour gönderim yapılır.
+const (
+	sendCooldown = time.Minute
+	sendPerHour  = 5
+)
+
+var errInvalidAccountToken = errors.New("Bağlantı geçersiz, kullanılmış veya süresi dolmuş")
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// @Summary Şifremi Unuttum
+// @Description Hesap varsa e-posta adresine şifre sıfırlama bağlantısı gönderir. Hesabın olup olmadığı belli edilmez; yanıt her zaman aynıdır. Aynı hesaba dakikada bir, saatte en fazla beş e-posta gönderilir.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param email body ForgotPasswordRequest true "Hesabın e-posta adresi"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /auth/forgot-password [post]
+func (ac *AuthController) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user models.User
+	err := config.DB.Where("email = ?", strings.TrimSpace(req.Email)).First(&user).Error
+	if err == nil && !user.IsSuspended {
+		// Sınıra takılan istekler de aynı yanıtı alır
+		if wait, err := accountEmailWait(user.ID, models.TokenPasswordReset); err != nil {
+			log.Printf("şifre sıfırlama sınırı okunamadı (kullanıcı %d): %v", user.ID, err)
+		} else if wait == 0 {
+			if err := sendAccountEmail(user, models.TokenPasswordReset); err != nil {
+				log.Printf("şifre sıfırlama bağlantısı oluşturulamadı (kullanıcı %d): %v", user.ID, err)
+			}
+		}
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "İstek işlenemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Bu adrese kayıtlı bir hesap varsa şifre sıfırlama bağlantısı gönderildi",
+	})
+}
+
+// @Summary Şifre Sıfırla
+// @Description Şifre sıfırlama e-postasındaki token ile yeni şifre belirler. Token tek kullanımlıktır; şifre değişince kullanıcının diğer sıfırlama bağlantıları ve tüm oturumları iptal edilir, daha önce verilen access token'lar reddedilir. Bağlantıyı açmak e-posta adresini de doğrular.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param reset body ResetPasswordRequest true "Token ve yeni şifre"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /auth/reset-password [post]
+func (ac *AuthController) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Şifre hashlenemedi"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := consumeAccountToken(tx, req.Token, models.TokenPasswordReset)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		// Token'ların iat değeri saniye hassasiyetinde olduğundan zaman saniyeye
+		// yuvarlanır; sıfırlamadan hemen sonra alınan token geçerli kalır
+		updates := map[string]interface{}{
+			"password":            string(hashedPassword),
+			"password_changed_at": now.Truncate(time.Second),
+		}
+		if !user.EmailVerified() {
+			updates["email_verified_at"] = now
+		}
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		// Aynı anda istenmiş diğer sıfırlama bağlantıları artık kullanılamaz
+		err = tx.Model(&models.AccountToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", user.ID, models.TokenPasswordReset).
+			Update("used_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", now).Error
+	})
+	if errors.Is(err, errInvalidAccountToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Şifre değiştirilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Şifreniz değiştirildi, yeni şifrenizle giriş yapabilirsiniz",
+	})
+}
+
+// @Summary E-posta Doğrula
+// @Description Doğrulama e-postasındaki token ile e-posta adresini doğrular. Token tek kullanımlıktır ve yalnızca gönderildiği adres için geçerlidir.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param token body VerifyEmailRequest true "Doğrulama token'ı"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /auth/verify-email [post]
+func (ac *AuthController) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user models.User
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		user, err = consumeAccountToken(tx, req.Token, models.TokenEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if !user.EmailVerified() {
+			if err := tx.Model(&user).Update("email_verified_at", now).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.AccountToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", user.ID, models.TokenEmailVerification).
+			Update("used_at", now).Error
+	})
+	if errors.Is(err, errInvalidAccountToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "E-posta doğrulanamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":           "E-posta adresiniz doğrulandı",
+		"email_verified_at": user.EmailVerifiedAt,
+	})
+}
+
+// @Summary Doğrulama E-postasını Tekrar Gönder
+// @Description Giriş yapmış kullanıcıya yeni bir doğrulama bağlantısı gönderir; önceki bağlantılar da süreleri dolana kadar geçerli kalır. Dakikada bir, saatte en fazla beş e-posta gönderilir; sınır aşılırsa 429 ve Retry-After döner.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /auth/resend-verification [post]
+func (ac *AuthController) ResendVerification(c *gin.Context) {
+	var user models.User
+	if err := config.DB.First(&user, middleware.GetUserID(c)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kullanıcı bulunamadı"})
+		return
+	}
+
+	if user.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Hesabınızda e-posta adresi yok"})
+		return
+	}
+	if user.EmailVerified() {
+		c.JSON(http.StatusConflict, gin.H{"error": "E-posta adresiniz zaten doğrulanmış"})
+		return
+	}
+
+	wait, err := accountEmailWait(user.ID, models.TokenEmailVerification)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Doğrulama e-postası gönderilemedi"})
+		return
+	}
+	if wait > 0 {
+		tooManyRequests(c, wait, "Çok fazla doğrulama e-postası istendi, lütfen daha sonra tekrar deneyin")
+		return
+	}
+
+	if err := sendAccountEmail(user, models.TokenEmailVerification); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Doğrulama e-postası gönderilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Doğrulama bağlantısı " + user.Email + " adresine gönderildi",
+	})
+}
+
+// accountEmailWait kullanıcıya bu amaçla yeni e-posta gönderilebilmesi için
+// beklenmesi gereken süreyi döner; 0 ise hemen gönderilebilir.
+func accountEmailWait(userID uint, purpose models.AccountTokenPurpose) (time.Duration, error) {
+	now := time.Now()
+
+	var sent []time.Time
+	err := config.DB.Model(&models.AccountToken{}).
+		Where("user_id = ? AND purpose = ? AND created_at > ?", userID, purpose, now.Add(-time.Hour)).
+		Order("created_at DESC").
+		Pluck("created_at", &sent).Error
+	if err != nil {
+		return 0, err
+	}
+	return sendLimitWait(sent, now), nil
+}
+
+// sendLimitWait son bir saatteki gönderim zamanlarına (yeniden eskiye sıralı)
+// göre bir sonraki gönderim için beklenmesi gereken süreyi döner.
+func sendLimitWait(sent []time.Time, now time.Time) time.Duration {
+	var wait time.Duration
+	if len(sent) > 0 {
+		wait = sent[0].Add(sendCooldown).Sub(now)
+	}
+	if len(sent) >= sendPerHour {
+		// Saatlik pencereden en eski gönderim düşünce yeniden gönderilebilir
+		if w := sent[sendPerHour-1].Add(time.Hour).Sub(now); w > wait {
+			wait = w
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+// tooManyRequests gönderim sınırı aşıldığında 429 ve Retry-After döner.
+func tooManyRequests(c *gin.Context, wait time.Duration, message string) {
+	seconds := int((wait + time.Second - 1) / time.Second)
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       message,
+		"retry_after": seconds,
+	})
+}
+
+// sendAccountEmail yeni bir token oluşturur ve bağlantısını kullanıcının
+// e-posta adresine gönderir. E-posta arka planda ve kullanıcının bildirim
+// tercihlerinden bağımsız olarak gönderilir.
+func sendAccountEmail(user models.User, purpose models.AccountTokenPurpose) error {
+	ttl, kind, path := config.App.EmailVerificationTTL, notify.EmailVerification, "/verify-email"
+	if purpose == models.TokenPasswordReset {
+		ttl, kind, path = config.App.PasswordResetTTL, notify.PasswordReset, "/reset-password"
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	// Sınır hesabına girmeyen eski, kullanılmış veya süresi dolmuş token'ları temizle
+	config.DB.Where("user_id = ? AND created_at < ? AND (used_at IS NOT NULL OR expires_at < ?)", user.ID, now.Add(-time.Hour), now).
+		Delete(&models.AccountToken{})
+
+	stored := models.AccountToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		Email:     user.Email,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := config.DB.Create(&stored).Error; err != nil {
+		return err
+	}
+
+	notify.Transactional(user.ID, kind, notify.AccountData{
+		Name:     user.Name,
+		Link:     strings.TrimRight(config.App.AppURL, "/") + path + "?token=" + url.QueryEscape(token),
+		Token:    token,
+		ValidFor: ttl,
+	})
+	return nil
+}
+
+// consumeAccountToken token'ı kullanıldı olarak işaretler ve sahibini döner.
+// Token bulunamazsa, başka amaç için verilmişse, kullanılmışsa, süresi
+// dolmuşsa veya kullanıcının e-posta adresi değişmişse errInvalidAccountToken
+// döner. Aynı token'la gelen eş zamanlı ikinci istek de reddedilir.
+func consumeAccountToken(tx *gorm.DB, raw string, purpose models.AccountTokenPurpose) (models.User, error) {
+	var user models.User
+
+	var stored models.AccountToken
+	err := tx.Where("token_hash = ? AND purpose = ?", hashToken(strings.TrimSpace(raw)), purpose).First(&stored).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, errInvalidAccountToken
+	}
+	if err != nil {
+		return user, err
+	}
+
+	now := time.Now()
+	if stored.UsedAt != nil || now.After(stored.ExpiresAt) {
+		return user, errInvalidAccountToken
+	}
+
+	result := tx.Model(&models.AccountToken{}).
+		Where("id = ? AND used_at IS NULL", stored.ID).
+		Update("used_at", now)
+	if result.Error != nil {
+		return user, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return user, errInvalidAccountToken
+	}
+
+	err = tx.First(&user, stored.UserID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && user.Email != stored.Email) {
+		return user, errInvalidAccountToken
+	}
+	return user, err
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+	"tradesman-api/config"
+	"tradesman-api/middleware"
+	"tradesman-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// issueAccountToken e-posta göndermeden kullanıcının adresine verilen süreyle
+// geçerli bir token kaydeder ve token'ı döner.
+func issueAccountToken(t *testing.T, user models.User, purpose models.AccountTokenPurpose, ttl time.Duration) string {
+	t.Helper()
+	token, err := randomToken(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := models.AccountToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := config.DB.Create(&stored).Error; err != nil {
+		t.Fatalf("token kaydedilemedi: %v", err)
+	}
+	return token
+}
+
+// accessTokenIssuedAt kullanıcı için verilen zamanda üretilmiş bir access token döner.
+func accessTokenIssuedAt(t *testing.T, user models.User, issuedAt time.Time) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, middleware.Claims{
+		UserID: user.ID,
+		Email:  user.Email,
+		Role:   user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+		},
+	}).SignedString([]byte(config.App.JWTSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func accountRouter() *gin.Engine {
+	ac := &AuthController{}
+	r := authRouter()
+	r.POST("/auth/reset-password", ac.ResetPassword)
+	r.POST("/auth/verify-email", ac.VerifyEmail)
+	return r
+}
+
+func TestResetPassword(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		user := testUser(t, "musteri@example.com", models.RoleCustomer)
+		r := accountRouter()
+		session := login(t, r, user.Email)
+		stolen := accessTokenIssuedAt(t, user, time.Now().Add(-time.Minute))
+		if code := withBearer(r, http.MethodGet, "/auth/me", stolen, nil).Code; code != http.StatusOK {
+			t.Fatalf("sıfırlamadan önce yanıt kodu %d, beklenen 200", code)
+		}
+
+		expired := issueAccountToken(t, user, models.TokenPasswordReset, -time.Minute)
+		w := postJSON(r, "/auth/reset-password", ResetPasswordRequest{Token: expired, Password: "yeni-sifre"})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("süresi dolmuş token yanıt kodu %d, beklenen 400: %s", w.Code, w.Body)
+		}
+
+		// Doğrulama token'ı şifre sıfırlamada kullanılamaz
+		verification := issueAccountToken(t, user, models.TokenEmailVerification, time.Hour)
+		w = postJSON(r, "/auth/reset-password", ResetPasswordRequest{Token: verification, Password: "yeni-sifre"})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("doğrulama token'ı yanıt kodu %d, beklenen 400: %s", w.Code, w.Body)
+		}
+
+		token := issueAccountToken(t, user, models.TokenPasswordReset, time.Hour)
+		w = postJSON(r, "/auth/reset-password", ResetPasswordRequest{Token: token, Password: "yeni-sifre"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		w = postJSON(r, "/auth/reset-password", ResetPasswordRequest{Token: token, Password: "baska-sifre"})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("ikinci kullanım yanıt kodu %d, beklenen 400: %s", w.Code, w.Body)
+		}
+
+		// Şifre değişince açık oturumlar kapanır
+		if _, code := refresh(t, r, session.RefreshToken); code != http.StatusUnauthorized {
+			t.Errorf("eski oturumun yenileme yanıt kodu %d, beklenen 401", code)
+		}
+		// Şifre değişmeden önce verilen access token'lar da geçersiz olur
+		if code := withBearer(r, http.MethodGet, "/auth/me", stolen, nil).Code; code != http.StatusUnauthorized {
+			t.Errorf("eski access token yanıt kodu %d, beklenen 401", code)
+		}
+		w = postJSON(r, "/auth/login", LoginRequest{Email: user.Email, Password: "yeni-sifre"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("yeni şifreyle giriş yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		var tokens tokenResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &tokens); err != nil {
+			t.Fatal(err)
+		}
+		if code := withBearer(r, http.MethodGet, "/auth/me", tokens.Token, nil).Code; code != http.StatusOK {
+			t.Errorf("yeni access token yanıt kodu %d, beklenen 200", code)
+		}
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		user := testUser(t, "musteri@example.com", models.RoleCustomer)
+		r := accountRouter()
+
+		expired := issueAccountToken(t, user, models.TokenEmailVerification, -time.Minute)
+		w := postJSON(r, "/auth/verify-email", VerifyEmailRequest{Token: expired})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("süresi dolmuş token yanıt kodu %d, beklenen 400: %s", w.Code, w.Body)
+		}
+
+		// Adres değiştikten sonra eski adrese gönderilen bağlantı geçersizdir
+		stale := issueAccountToken(t, user, models.TokenEmailVerification, time.Hour)
+		if err := config.DB.Model(&user).Update("email", "yeni@example.com").Error; err != nil {
+			t.Fatal(err)
+		}
+		w = postJSON(r, "/auth/verify-email", VerifyEmailRequest{Token: stale})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("eski adresin token'ı yanıt kodu %d, beklenen 400: %s", w.Code, w.Body)
+		}
+		var stored models.User
+		config.DB.First(&stored, user.ID)
+		if stored.EmailVerified() {
+			t.Fatal("değişen adres eski bağlantıyla doğrulandı")
+		}
+
+		token := issueAccountToken(t, stored, models.TokenEmailVerification, time.Hour)
+		w = postJSON(r, "/auth/verify-email", VerifyEmailRequest{Token: token})
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		w = postJSON(r, "/auth/verify-email", VerifyEmailRequest{Token: token})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("ikinci kullanım yanıt kodu %d, beklenen 400: %s", w.Code, w.Body)
+		}
+		config.DB.First(&stored, user.ID)
+		if !stored.EmailVerified() {
+			t.Error("e-posta adresi doğrulanmadı")
+		}
+	})
+}
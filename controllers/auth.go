@@ -1,6 +1,12 @@
 package controllers
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
 	"net/http"
 	"time"
 	"tradesman-api/config"
@@ -10,6 +16,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type AuthController struct{}
@@ -19,7 +26,7 @@ type RegisterRequest struct {
 	Email    string          `json:"email" binding:"required,email"`
 	Password string          `json:"password" binding:"required,min=6"`
 	Phone    string          `json:"phone"`
-	Role     models.UserRole `json:"role" binding:"required"`
+	Role     models.UserRole `json:"role" binding:"required,oneof=customer shop"`
 }
 
 type LoginRequest struct {
@@ -27,14 +34,29 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	AllDevices   bool   `json:"all_devices"` // Kullanıcının tüm yenileme token'larını iptal eder
+}
+
+type tokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
 // @Summary Kullanıcı Kaydı
-// @Description Yeni kullanıcı kaydı oluşturur
+// @Description Yeni müşteri veya esnaf kaydı oluşturur ve e-posta adresine doğrulama bağlantısı gönderir. Admin hesabı bu uç noktadan oluşturulamaz.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param user body RegisterRequest true "Kullanıcı bilgileri"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Router /auth/register [post]
 func (ac *AuthController) Register(c *gin.Context) {
 	var req RegisterRequest
@@ -43,10 +65,10 @@ func (ac *AuthController) Register(c *gin.Context) {
 		return
 	}
 
-	// Email kontrolü
+	// Email kontrolü; silinmiş hesaplar da benzersiz indekste olduğundan Unscoped
 	var existingUser models.User
-	if err := config.DB.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Bu email zaten kullanılıyor"})
+	if err := config.DB.Unscoped().Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Bu email zaten kullanılıyor"})
 		return
 	}
 
@@ -71,8 +93,13 @@ func (ac *AuthController) Register(c *gin.Context) {
 		return
 	}
 
+	// Doğrulama e-postası; gönderilemezse kullanıcı tekrar isteyebilir
+	if err := sendAccountEmail(user, models.TokenEmailVerification); err != nil {
+		log.Printf("doğrulama bağlantısı oluşturulamadı (kullanıcı %d): %v", user.ID, err)
+	}
+
 	// JWT token oluşturma
-	token, err := ac.generateToken(user.ID, user.Email, user.Role)
+	tokens, err := ac.issueTokens(user, "")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Token oluşturulamadı"})
 		return
@@ -81,12 +108,15 @@ func (ac *AuthController) Register(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Kullanıcı başarıyla oluşturuldu",
 		"user": gin.H{
-			"id":    user.ID,
-			"name":  user.Name,
-			"email": user.Email,
-			"role":  user.Role,
+			"id":             user.ID,
+			"name":           user.Name,
+			"email":          user.Email,
+			"role":           user.Role,
+			"email_verified": user.EmailVerified(),
 		},
-		"token": token,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    int(config.App.AccessTokenTTL.Seconds()),
 	})
 }
 
@@ -120,8 +150,13 @@ func (ac *AuthController) Login(c *gin.Context) {
 		return
 	}
 
+	if user.IsSuspended {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Hesabınız askıya alınmış"})
+		return
+	}
+
 	// JWT token oluşturma
-	token, err := ac.generateToken(user.ID, user.Email, user.Role)
+	tokens, err := ac.issueTokens(user, "")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Token oluşturulamadı"})
 		return
@@ -130,12 +165,15 @@ func (ac *AuthController) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Giriş başarılı",
 		"user": gin.H{
-			"id":    user.ID,
-			"name":  user.Name,
-			"email": user.Email,
-			"role":  user.Role,
+			"id":             user.ID,
+			"name":           user.Name,
+			"email":          user.Email,
+			"role":           user.Role,
+			"email_verified": user.EmailVerified(),
 		},
-		"token": token,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    int(config.App.AccessTokenTTL.Seconds()),
 	})
 }
 
@@ -158,27 +196,230 @@ func (ac *AuthController) Me(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"user": gin.H{
-			"id":         user.ID,
-			"name":       user.Name,
-			"email":      user.Email,
-			"phone":      user.Phone,
-			"role":       user.Role,
-			"created_at": user.CreatedAt,
+			"id":                user.ID,
+			"name":              user.Name,
+			"email":             user.Email,
+			"phone":             user.Phone,
+			"role":              user.Role,
+			"email_verified":    user.EmailVerified(),
+			"email_verified_at": user.EmailVerifiedAt,
+			"phone_e164":        user.PhoneE164,
+			"created_at":        user.CreatedAt,
 		},
 	})
 }
 
+// @Summary Token Yenile
+// @Description Yenileme token'ı ile yeni access ve yenileme token'ı üretir. Kullanılmış bir yenileme token'ı tekrar gönderilirse tüm token ailesi iptal edilir.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param token body RefreshRequest true "Yenileme token'ı"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/refresh [post]
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var stored models.RefreshToken
+	if err := config.DB.Where("token_hash = ?", hashToken(req.RefreshToken)).First(&stored).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Geçersiz yenileme token'ı"})
+		return
+	}
+
+	now := time.Now()
+
+	// Token'ı yalnızca hâlâ geçerliyse iptal et; aynı anda gelen ikinci istek tekrar kullanım sayılır
+	result := config.DB.Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", stored.ID).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Token yenilenemedi"})
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		// Tekrar kullanım: token çalınmış olabilir, tüm aileyi iptal et
+		if err := ac.revokeFamily(stored.FamilyID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Token yenilenemedi"})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yenileme token'ı daha önce kullanılmış, oturum sonlandırıldı"})
+		return
+	}
+
+	if now.After(stored.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Yenileme token'ının süresi dolmuş"})
+		return
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, stored.UserID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Kullanıcı bulunamadı"})
+		return
+	}
+
+	if user.IsSuspended {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Hesabınız askıya alınmış"})
+		return
+	}
+
+	tokens, err := ac.issueTokens(user, stored.FamilyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Token oluşturulamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    int(config.App.AccessTokenTTL.Seconds()),
+	})
+}
+
+// @Summary Çıkış Yap
+// @Description Mevcut access token'ı iptal eder. Yenileme token'ı gönderilirse ailesi, all_devices=true ise kullanıcının tüm yenileme token'ları iptal edilir.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param logout body LogoutRequest false "Çıkış seçenekleri"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /auth/logout [post]
+func (ac *AuthController) Logout(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	claims := middleware.GetClaims(c)
+
+	// Gövde isteğe bağlı; chunked isteklerde uzunluk bilinmediği için gövde
+	// her zaman okunur ve yalnızca boş gövde (io.EOF) yok sayılır
+	var req LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+
+	// Süresi dolmuş iptal kayıtlarını temizle
+	config.DB.Where("expires_at < ?", now).Delete(&models.RevokedToken{})
+
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		revoked := models.RevokedToken{
+			JTI:       claims.ID,
+			ExpiresAt: claims.ExpiresAt.Time,
+		}
+		if err := config.DB.Create(&revoked).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Çıkış yapılamadı"})
+			return
+		}
+	}
+
+	// Yenileme token'ları iptal edilemezse istemci oturumun kapandığını sanmasın
+	if req.AllDevices {
+		if err := revokeUserRefreshTokens(userID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Çıkış yapılamadı"})
+			return
+		}
+	} else if req.RefreshToken != "" {
+		var stored models.RefreshToken
+		err := config.DB.Where("token_hash = ? AND user_id = ?", hashToken(req.RefreshToken), userID).First(&stored).Error
+		if err == nil {
+			err = ac.revokeFamily(stored.FamilyID)
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Çıkış yapılamadı"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Çıkış yapıldı",
+	})
+}
+
+// issueTokens access token ile birlikte yeni bir yenileme token'ı üretir.
+// familyID boşsa yeni bir token ailesi başlatılır.
+func (ac *AuthController) issueTokens(user models.User, familyID string) (tokenPair, error) {
+	accessToken, err := ac.generateToken(user.ID, user.Email, user.Role)
+	if err != nil {
+		return tokenPair{}, err
+	}
+
+	if familyID == "" {
+		familyID, err = randomToken(16)
+		if err != nil {
+			return tokenPair{}, err
+		}
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return tokenPair{}, err
+	}
+
+	stored := models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(config.App.RefreshTokenTTL),
+	}
+	if err := config.DB.Create(&stored).Error; err != nil {
+		return tokenPair{}, err
+	}
+
+	return tokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// revokeFamily aynı girişten türeyen tüm yenileme token'larını iptal eder.
+func (ac *AuthController) revokeFamily(familyID string) error {
+	return config.DB.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// revokeUserRefreshTokens kullanıcının tüm açık oturumlarını sonlandırır.
+func revokeUserRefreshTokens(userID uint) error {
+	return config.DB.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
 func (ac *AuthController) generateToken(userID uint, email string, role models.UserRole) (string, error) {
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", err
+	}
+
 	claims := middleware.Claims{
 		UserID: userID,
 		Email:  email,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.App.AccessTokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte("super-secret-key-change-in-production"))
+	return token.SignedString([]byte(config.App.JWTSecret))
+}
+
+func randomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"tradesman-api/config"
+	"tradesman-api/middleware"
+	"tradesman-api/models"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const testPassword = "gizli-sifre"
+
+// testUser şifresi testPassword olan bir kullanıcı oluşturur.
+func testUser(t *testing.T, email string, role models.UserRole) models.User {
+	t.Helper()
+	hash, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	user := models.User{Name: "Kullanıcı", Email: email, Password: string(hash), Role: role}
+	if err := config.DB.Create(&user).Error; err != nil {
+		t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+	}
+	return user
+}
+
+// authRouter kimlik doğrulama uçlarını gerçek JWT doğrulamasıyla kurar.
+func authRouter() *gin.Engine {
+	ac := &AuthController{}
+	r := gin.New()
+	r.POST("/auth/register", ac.Register)
+	r.POST("/auth/login", ac.Login)
+	r.POST("/auth/refresh", ac.Refresh)
+	r.POST("/auth/logout", middleware.AuthMiddleware(), ac.Logout)
+	r.GET("/auth/me", middleware.AuthMiddleware(), ac.Me)
+	return r
+}
+
+type tokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// login kullanıcıyla giriş yapar ve token'ları döner.
+func login(t *testing.T, r *gin.Engine, email string) tokenResponse {
+	t.Helper()
+	w := postJSON(r, "/auth/login", LoginRequest{Email: email, Password: testPassword})
+	if w.Code != http.StatusOK {
+		t.Fatalf("giriş yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+	}
+	var tokens tokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &tokens); err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+// refresh yenileme token'ını kullanır; başarılıysa yeni token'ları döner.
+func refresh(t *testing.T, r *gin.Engine, token string) (tokenResponse, int) {
+	t.Helper()
+	w := postJSON(r, "/auth/refresh", RefreshRequest{RefreshToken: token})
+	var tokens tokenResponse
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &tokens); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tokens, w.Code
+}
+
+// jsonRequest gövdesi JSON olan bir istek hazırlar; body nil ise gövde boştur.
+func jsonRequest(method, path string, body interface{}) *http.Request {
+	if body == nil {
+		return httptest.NewRequest(method, path, nil)
+	}
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func postJSON(r *gin.Engine, path string, body interface{}) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, jsonRequest(http.MethodPost, path, body))
+	return w
+}
+
+// withBearer isteği access token'la gönderir.
+func withBearer(r *gin.Engine, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := jsonRequest(method, path, body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRefreshRotation(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		user := testUser(t, "musteri@example.com", models.RoleCustomer)
+		r := authRouter()
+
+		first := login(t, r, user.Email)
+		second, code := refresh(t, r, first.RefreshToken)
+		if code != http.StatusOK {
+			t.Fatalf("yenileme yanıt kodu %d, beklenen 200", code)
+		}
+		if second.RefreshToken == first.RefreshToken {
+			t.Fatal("yenileme token'ı değişmedi")
+		}
+		third, code := refresh(t, r, second.RefreshToken)
+		if code != http.StatusOK {
+			t.Fatalf("yenileme yanıt kodu %d, beklenen 200", code)
+		}
+
+		// Başka bir cihazdaki oturum ayrı bir ailedir
+		other := login(t, r, user.Email)
+
+		// Kullanılmış token tekrar gönderilince reddedilir ve ailesi iptal edilir
+		if _, code := refresh(t, r, first.RefreshToken); code != http.StatusUnauthorized {
+			t.Fatalf("tekrar kullanım yanıt kodu %d, beklenen 401", code)
+		}
+		if _, code := refresh(t, r, third.RefreshToken); code != http.StatusUnauthorized {
+			t.Errorf("aynı ailedeki son token yanıt kodu %d, beklenen 401", code)
+		}
+		if _, code := refresh(t, r, other.RefreshToken); code != http.StatusOK {
+			t.Errorf("diğer oturumun yanıt kodu %d, beklenen 200", code)
+		}
+	})
+}
+
+func TestLogout(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		user := testUser(t, "musteri@example.com", models.RoleCustomer)
+		r := authRouter()
+
+		tokens := login(t, r, user.Email)
+		w := withBearer(r, http.MethodPost, "/auth/logout", tokens.Token, LogoutRequest{RefreshToken: tokens.RefreshToken})
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		if w := withBearer(r, http.MethodGet, "/auth/me", tokens.Token, nil); w.Code != http.StatusUnauthorized {
+			t.Errorf("çıkıştan sonra access token yanıt kodu %d, beklenen 401", w.Code)
+		}
+		if _, code := refresh(t, r, tokens.RefreshToken); code != http.StatusUnauthorized {
+			t.Errorf("çıkıştan sonra yenileme yanıt kodu %d, beklenen 401", code)
+		}
+
+		// Uzunluğu bilinmeyen (chunked) gövde de okunur; boş gövde kabul edilir
+		chunked := func(token, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/auth/logout", io.NopCloser(strings.NewReader(body)))
+			req.ContentLength = -1
+			req.TransferEncoding = []string{"chunked"}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			return w
+		}
+		tokens = login(t, r, user.Email)
+		if w := chunked(tokens.Token, `{"refresh_token":`); w.Code != http.StatusBadRequest {
+			t.Errorf("bozuk chunked gövde yanıt kodu %d, beklenen 400: %s", w.Code, w.Body)
+		}
+		if w := chunked(tokens.Token, fmt.Sprintf(`{"refresh_token":%q}`, tokens.RefreshToken)); w.Code != http.StatusOK {
+			t.Fatalf("chunked gövde yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		if _, code := refresh(t, r, tokens.RefreshToken); code != http.StatusUnauthorized {
+			t.Errorf("chunked çıkıştan sonra yenileme yanıt kodu %d, beklenen 401", code)
+		}
+		tokens = login(t, r, user.Email)
+		if w := chunked(tokens.Token, ""); w.Code != http.StatusOK {
+			t.Errorf("boş chunked gövde yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		if _, code := refresh(t, r, tokens.RefreshToken); code != http.StatusOK {
+			t.Errorf("gövdesiz çıkıştan sonra yenileme yanıt kodu %d, beklenen 200", code)
+		}
+
+		// Yenileme token'ları iptal edilemezse çıkış başarılı sayılmaz
+		tokens = login(t, r, user.Email)
+		err := config.DB.Callback().Update().Before("gorm:update").Register("test:fail", func(db *gorm.DB) {
+			if db.Statement.Table == "refresh_tokens" {
+				db.AddError(errors.New("veritabanı hatası"))
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w = withBearer(r, http.MethodPost, "/auth/logout", tokens.Token, LogoutRequest{AllDevices: true})
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("yanıt kodu %d, beklenen 500: %s", w.Code, w.Body)
+		}
+	})
+}
+
+func TestRegisterRoles(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		r := authRouter()
+
+		for _, role := range []models.UserRole{models.RoleAdmin, "", "superuser"} {
+			w := postJSON(r, "/auth/register", RegisterRequest{Name: "Ali", Email: "ali@example.com", Password: testPassword, Role: role})
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("%q rolüyle kayıt yanıt kodu %d, beklenen 400: %s", role, w.Code, w.Body)
+			}
+		}
+		var admins int64
+		config.DB.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&admins)
+		if admins != 0 {
+			t.Fatalf("kayıtla %d admin oluşturuldu", admins)
+		}
+
+		w := postJSON(r, "/auth/register", RegisterRequest{Name: "Ali", Email: "ali@example.com", Password: testPassword, Role: models.RoleShop})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("yanıt kodu %d, beklenen 201: %s", w.Code, w.Body)
+		}
+		var stored models.User
+		if err := config.DB.Where("email = ?", "ali@example.com").First(&stored).Error; err != nil {
+			t.Fatal(err)
+		}
+		if stored.Role != models.RoleShop {
+			t.Errorf("rol %q, beklenen %q", stored.Role, models.RoleShop)
+		}
+	})
+}
@@ -0,0 +1,484 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"tradesman-api/catalog"
+	"tradesman-api/config"
+	"tradesman-api/middleware"
+	"tradesman-api/models"
+	"tradesman-api/money"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxImportSize = 10 << 20
+	maxImportRows = 5000
+)
+
+// İçe aktarma sonucu; dry run'da da aynı rapor döner.
+type ImportReport struct {
+	DryRun    bool              `json:"dry_run"`
+	TotalRows int               `json:"total_rows"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Failed    int               `json:"failed"`
+	Errors    []ImportRowErrors `json:"errors"`
+}
+
+type ImportRowErrors struct {
+	Line   int      `json:"line"` // Dosyadaki satır numarası (başlık 1. satır)
+	ID     string   `json:"id,omitempty"`
+	SKU    string   `json:"sku"`
+	Errors []string `json:"errors"`
+}
+
+// İçe aktarılacak, doğrulanmış ürün
+type importedProduct struct {
+	product  models.Product
+	isNew    bool
+	setStock bool // Satırda stok hücresi dolu; boşsa stok defterine dokunulmaz
+}
+
+// Dosyadaki satırlarla eşleşebilecek mevcut ürünler
+type existingProducts struct {
+	bySKU map[string]models.Product
+	byID  map[uint]models.Product
+}
+
+// @Summary Ürünleri İçe Aktar
+// @Description Dükkanın ürünlerini CSV veya XLSX dosyasından ekler/günceller (sadece esnaflar). Satırlar id doluysa ürün ID'siyle, değilse SKU ile eşleştirilir; böylece SKU'su olmayan ürünler de dışa aktarılıp geri yüklenebilir. İlk satır sütun başlıklarıdır; sku, name ve price sütunları zorunludur, yeni ürünlerde sku hücresi de zorunludur. Mevcut üründe boş bırakılan hücreler değeri değiştirmez. Bir satır bile hatalıysa hiçbir değişiklik yapılmaz ve 422 döner; dry_run=true ile sadece doğrulama raporu alınır.
+// @Tags Products
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV veya XLSX dosyası"
+// @Param format query string false "csv veya xlsx (verilmezse dosya uzantısından)"
+// @Param dry_run query bool false "Sadece doğrula, kaydetme"
+// @Success 200 {object} ImportReport
+// @Failure 400 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Failure 422 {object} ImportReport
+// @Router /products/import [post]
+func (pc *ProductController) ImportProducts(c *gin.Context) {
+	shop, ok := loadOwnShop(c)
+	if !ok {
+		return
+	}
+
+	dryRun, err := queryBool(c, "dry_run")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	file, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Dosya en fazla %d MB olabilir", maxImportSize>>20)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dosya yüklenmeli (file alanı)"})
+		return
+	}
+
+	format, ok := catalog.FormatFromFilename(file.Filename)
+	if value := c.Query("format"); value != "" {
+		format, ok = catalog.ParseFormat(value)
+	}
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dosya biçimi csv veya xlsx olmalı"})
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dosya okunamadı"})
+		return
+	}
+	defer f.Close()
+
+	rows, err := catalog.ReadRows(f, format, maxImportRows)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	categories, err := loadCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kategoriler getirilemedi"})
+		return
+	}
+
+	existing, err := loadExistingProducts(shop.ID, rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ürünler getirilemedi"})
+		return
+	}
+
+	report := ImportReport{DryRun: dryRun != nil && *dryRun, TotalRows: len(rows), Errors: []ImportRowErrors{}}
+	imported := make([]importedProduct, 0, len(rows))
+	lines := make(map[string]int, len(rows))
+	productLines := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		item, errs := importRow(shop, row, existing, categories)
+		sku := row.Values["sku"]
+		if first, ok := lines[sku]; ok && sku != "" {
+			errs = append(errs, fmt.Sprintf("SKU dosyada daha önce geçiyor (satır %d)", first))
+		} else {
+			lines[sku] = row.Line
+		}
+		if !item.isNew {
+			if first, ok := productLines[item.product.ID]; ok {
+				errs = append(errs, fmt.Sprintf("ürün dosyada daha önce geçiyor (satır %d)", first))
+			} else {
+				productLines[item.product.ID] = row.Line
+			}
+		}
+
+		if len(errs) > 0 {
+			report.Failed++
+			report.Errors = append(report.Errors, ImportRowErrors{Line: row.Line, ID: row.Values["id"], SKU: sku, Errors: errs})
+			continue
+		}
+		if item.isNew {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+		imported = append(imported, item)
+	}
+
+	if report.DryRun {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	if report.Failed > 0 {
+		c.JSON(http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	// Stok kolonu doğrudan yazılmaz; dosyadaki stokla fark deftere düzeltme
+	// hareketi olarak yazılır. Stok hücresi boş satırlarda, dosya okunduktan
+	// sonra yapılan satış ve girişler geri alınmasın diye sayım yazılmaz.
+	actorID := middleware.GetUserID(c)
+	var alerts []*models.StockAlert
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range imported {
+			product := &imported[i].product
+			stock := product.Stock
+			if imported[i].isNew {
+				product.Stock = 0
+				if err := createWithActive(tx, product, product.IsActive); err != nil {
+					return err
+				}
+			} else if err := tx.Omit(clause.Associations, "stock").Save(product).Error; err != nil {
+				return err
+			}
+			if !imported[i].setStock {
+				continue
+			}
+			alert, err := recordStockCount(tx, *product, nil, stock, actorID, "Toplu içe aktarma")
+			if err != nil {
+				return err
+			}
+			alerts = append(alerts, alert)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ürünler kaydedilemedi"})
+		return
+	}
+	publishStockAlerts(alerts...)
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Ürünleri Dışa Aktar
+// @Description Dükkanın tüm ürünlerini (pasifler dahil) içe aktarmayla aynı sütunlarla CSV veya XLSX olarak indirir (sadece esnaflar)
+// @Tags Products
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param format query string false "csv (varsayılan) veya xlsx"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Router /products/export [get]
+func (pc *ProductController) ExportProducts(c *gin.Context) {
+	shop, ok := loadOwnShop(c)
+	if !ok {
+		return
+	}
+
+	format, ok := catalog.ParseFormat(c.DefaultQuery("format", string(catalog.FormatCSV)))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dosya biçimi csv veya xlsx olmalı"})
+		return
+	}
+
+	categories, err := loadCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kategoriler getirilemedi"})
+		return
+	}
+	slugs := make(map[uint]string, len(categories))
+	for _, category := range categories {
+		slugs[category.ID] = category.Slug
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="products-%d.%s"`, shop.ID, format))
+	c.Status(http.StatusOK)
+
+	// Başlıklar gönderildikten sonra oluşan hatalar istemciye bildirilemez;
+	// yarım kalan dosya bağlantının kesilmesiyle anlaşılır.
+	writer, err := catalog.NewWriter(c.Writer, format)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var batch []models.Product
+	err = config.DB.Where("shop_id = ?", shop.ID).Order("id").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for _, product := range batch {
+				if err := writer.Write(exportRow(product, slugs)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		c.Error(err)
+		c.Abort()
+	}
+}
+
+// loadOwnShop isteği yapan esnafın dükkanını getirir; yoksa yanıtı yazar.
+func loadOwnShop(c *gin.Context) (models.Shop, bool) {
+	var shop models.Shop
+	if err := config.DB.Where("user_id = ?", middleware.GetUserID(c)).First(&shop).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Önce bir dükkan oluşturmalısınız"})
+		return shop, false
+	}
+	return shop, true
+}
+
+// loadExistingProducts dosyada geçen ID'lere ve SKU'lara sahip mevcut ürünleri
+// getirir. SKU'lar büyük/küçük harf duyarlıdır; geçersiz ID'ler atlanır,
+// importRow bunları satır hatası olarak raporlar.
+func loadExistingProducts(shopID uint, rows []catalog.Row) (existingProducts, error) {
+	skus := make([]string, 0, len(rows))
+	var ids []uint
+	for _, row := range rows {
+		if sku := row.Values["sku"]; sku != "" {
+			skus = append(skus, sku)
+		}
+		if id, err := strconv.ParseUint(row.Values["id"], 10, 32); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+
+	existing := existingProducts{
+		bySKU: make(map[string]models.Product, len(skus)),
+		byID:  make(map[uint]models.Product, len(ids)),
+	}
+	for start := 0; start < len(skus); start += 500 {
+		end := min(start+500, len(skus))
+		var batch []models.Product
+		if err := config.DB.Where("shop_id = ? AND sku IN ?", shopID, skus[start:end]).Find(&batch).Error; err != nil {
+			return existing, err
+		}
+		for _, product := range batch {
+			existing.bySKU[*product.SKU] = product
+		}
+	}
+	for start := 0; start < len(ids); start += 500 {
+		end := min(start+500, len(ids))
+		var batch []models.Product
+		if err := config.DB.Where("shop_id = ? AND id IN ?", shopID, ids[start:end]).Find(&batch).Error; err != nil {
+			return existing, err
+		}
+		for _, product := range batch {
+			existing.byID[product.ID] = product
+		}
+	}
+	return existing, nil
+}
+
+// importRow satırı yeni ya da mevcut ürüne uygular ve tüm hataları toplar.
+// id doluysa ürün ID'siyle, değilse SKU ile eşleştirilir. Mevcut üründe boş
+// hücreler değeri değiştirmez; id ile eşleşen satırda sku verilirse ürünün
+// SKU'su güncellenir.
+func importRow(shop models.Shop, row catalog.Row, existing existingProducts, categories []models.Category) (importedProduct, []string) {
+	var errs []string
+	v := row.Values
+
+	sku := v["sku"]
+	if len(sku) > 64 {
+		errs = append(errs, "sku en fazla 64 karakter olabilir")
+	}
+
+	var product models.Product
+	var found bool
+	if row.Has("id") {
+		id, err := strconv.ParseUint(v["id"], 10, 32)
+		if err != nil || id == 0 {
+			errs = append(errs, "id pozitif bir tam sayı olmalı")
+		} else if product, found = existing.byID[uint(id)]; !found {
+			errs = append(errs, "ürün bulunamadı: id "+v["id"])
+		}
+		if found && sku != "" {
+			if other, ok := existing.bySKU[sku]; ok && other.ID != product.ID {
+				errs = append(errs, "SKU dükkanınızda başka bir üründe kullanılıyor")
+			}
+			product.SKU = &sku
+		}
+	} else if sku == "" {
+		errs = append(errs, "sku boş olamaz (mevcut ürünler id ile eşleştirilebilir)")
+	} else {
+		product, found = existing.bySKU[sku]
+	}
+
+	item := importedProduct{isNew: !found}
+	storedStock, storedUnit := product.Stock, product.Unit
+	if !found {
+		product = models.Product{ShopID: shop.ID, SKU: &sku, Currency: money.DefaultCurrency, IsActive: true}
+		if !row.Has("name") {
+			errs = append(errs, "name yeni ürün için zorunlu")
+		}
+		if !row.Has("price") {
+			errs = append(errs, "price yeni ürün için zorunlu")
+		}
+	}
+
+	if row.Has("name") {
+		product.Name = v["name"]
+	}
+	if row.Has("description") {
+		product.Description = v["description"]
+	}
+	if row.Has("image_url") {
+		product.ImageURL = v["image_url"]
+	}
+	if row.Has("category") {
+		if category, ok := findCategory(categories, v["category"]); ok {
+			product.CategoryID = &category.ID
+		} else {
+			errs = append(errs, "kategori bulunamadı: "+v["category"])
+		}
+	}
+	if row.Has("price") {
+		price, err := money.Parse(decimalCell(v["price"]))
+		if err != nil || price <= 0 {
+			errs = append(errs, "price sıfırdan büyük, en fazla 2 ondalıklı bir sayı olmalı")
+		}
+		product.Price = price
+	}
+	if row.Has("currency") {
+		product.Currency = strings.ToUpper(v["currency"])
+		if !money.ValidCurrency(product.Currency) {
+			errs = append(errs, "geçersiz para birimi: "+v["currency"])
+		}
+	}
+	if row.Has("unit") {
+		product.Unit = models.Unit(strings.ToLower(v["unit"]))
+	}
+	quantities := []struct {
+		column string
+		dst    *float64
+	}{
+		{"min_quantity", &product.MinQuantity},
+		{"quantity_step", &product.QuantityStep},
+		{"stock", &product.Stock},
+		{"reorder_threshold", &product.ReorderThreshold},
+	}
+	for _, q := range quantities {
+		if !row.Has(q.column) {
+			continue
+		}
+		n, err := strconv.ParseFloat(decimalCell(v[q.column]), 64)
+		if err != nil || n < 0 {
+			errs = append(errs, q.column+" sıfır veya pozitif bir sayı olmalı")
+			continue
+		}
+		*q.dst = n
+	}
+	if row.Has("is_active") {
+		active, err := strconv.ParseBool(strings.ToLower(v["is_active"]))
+		if err != nil {
+			errs = append(errs, "is_active true veya false olmalı")
+		}
+		product.IsActive = active
+	}
+
+	product.ApplyUnitDefaults()
+	if len(errs) == 0 {
+		if err := product.ValidateUnit(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if found && len(errs) == 0 && product.Unit != storedUnit {
+		if err := checkUnitChange(config.DB, product.ID); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	// Aktif çeşidi olan ürünün stoğu çeşitlerde tutulur; ürün stoğu değiştirilemez
+	item.setStock = row.Has("stock")
+	if found && item.setStock && models.RoundQuantity(product.Stock) != models.RoundQuantity(storedStock) {
+		if _, err := validateStockTarget(product, nil); err != nil {
+			errs = append(errs, "stok çeşitlerde tutuluyor, ürünün stock hücresi değiştirilemez")
+		}
+	}
+
+	item.product = product
+	return item, errs
+}
+
+// decimalCell Türkçe Excel'in ondalık virgülünü (12,50) noktaya çevirir.
+func decimalCell(s string) string {
+	if !strings.Contains(s, ".") {
+		return strings.Replace(s, ",", ".", 1)
+	}
+	return s
+}
+
+func exportRow(p models.Product, categorySlugs map[uint]string) []string {
+	sku, category := "", ""
+	if p.SKU != nil {
+		sku = *p.SKU
+	}
+	if p.CategoryID != nil {
+		category = categorySlugs[*p.CategoryID]
+	}
+	return []string{
+		strconv.FormatUint(uint64(p.ID), 10),
+		sku,
+		p.Name,
+		p.Description,
+		category,
+		p.Price.String(),
+		p.Currency,
+		string(p.Unit),
+		models.FormatQuantity(p.MinQuantity),
+		models.FormatQuantity(p.QuantityStep),
+		models.FormatQuantity(p.Stock),
+		models.FormatQuantity(p.ReorderThreshold),
+		strconv.FormatBool(p.IsActive),
+		p.ImageURL,
+	}
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"tradesman-api/config"
+	"tradesman-api/inventory"
+	"tradesman-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// postImport CSV içeriğini dosya olarak içe aktarma uç noktasına yükler.
+func postImport(r *gin.Engine, csv string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, _ := form.CreateFormFile("file", "urunler.csv")
+	file.Write([]byte(csv))
+	form.Close()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/products/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestImportProductsKeepsStock(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		_, shop, product := testCatalog(t, 10)
+		owner := models.User{ID: shop.UserID, Role: models.RoleShop}
+
+		// Ürünler okunduktan sonra, içe aktarma yazılmadan önce gelen satış
+		sold := false
+		err := config.DB.Callback().Query().After("gorm:query").Register("test:sale", func(db *gorm.DB) {
+			if sold || db.Statement.Table != "products" {
+				return
+			}
+			sold = true
+			err := config.DB.Transaction(func(tx *gorm.DB) error {
+				return inventory.Record(tx, &models.StockMovement{
+					ShopID:    shop.ID,
+					ProductID: product.ID,
+					Type:      models.StockMovementAdjustment,
+					Quantity:  -3,
+					ActorID:   &owner.ID,
+					Reason:    "Satış",
+				})
+			})
+			if err != nil {
+				t.Errorf("satış yazılamadı: %v", err)
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pc := &ProductController{}
+		r := asUser(owner)
+		r.POST("/products/import", pc.ImportProducts)
+
+		w := postImport(r, fmt.Sprintf("id,sku,name,price\n%d,,Köy Ekmeği,\n", product.ID))
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		if !sold {
+			t.Fatal("satış içe aktarma sırasında yazılmadı")
+		}
+
+		var stored models.Product
+		if err := config.DB.First(&stored, product.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if stored.Name != "Köy Ekmeği" {
+			t.Errorf("ürün adı %q, beklenen %q", stored.Name, "Köy Ekmeği")
+		}
+		if stored.Stock != 7 {
+			t.Errorf("stok %v, beklenen 7 (stok sütunu olmayan dosya stoğu değiştirmemeli)", stored.Stock)
+		}
+
+		discrepancies, err := inventory.Check(config.DB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(discrepancies) > 0 {
+			t.Errorf("stok defterle tutmuyor: %s", fmt.Sprint(discrepancies))
+		}
+	})
+}
+
+func TestImportProductsVariantStock(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		_, shop, product := testCatalog(t, 10)
+		owner := models.User{ID: shop.UserID, Role: models.RoleShop}
+		addVariant(t, product)
+
+		pc := &ProductController{}
+		r := asUser(owner)
+		r.POST("/products/import", pc.ImportProducts)
+
+		w := postImport(r, fmt.Sprintf("id,sku,name,price,stock\n%d,,Köy Ekmeği,,25\n", product.ID))
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("yanıt kodu %d, beklenen 422: %s", w.Code, w.Body)
+		}
+		var report ImportReport
+		if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		if report.Failed != 1 || len(report.Errors) != 1 || report.Errors[0].Line != 2 {
+			t.Errorf("rapor beklenen gibi değil: %s", w.Body)
+		}
+
+		// Dışa aktarılan stok değiştirilmeden geri yüklenebilir
+		w = postImport(r, fmt.Sprintf("id,sku,name,price,stock\n%d,,Köy Ekmeği,,10\n", product.ID))
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+
+		var stored models.Product
+		if err := config.DB.First(&stored, product.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if stored.Stock != 10 {
+			t.Errorf("ürün stoğu %v, beklenen 10", stored.Stock)
+		}
+
+		discrepancies, err := inventory.Check(config.DB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(discrepancies) > 0 {
+			t.Errorf("stok defterle tutmuyor: %s", fmt.Sprint(discrepancies))
+		}
+	})
+}
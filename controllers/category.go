@@ -0,0 +1,400 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"tradesman-api/config"
+	"tradesman-api/models"
+	"tradesman-api/search"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryController struct{}
+
+type CategoryRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Slug        string `json:"slug" binding:"omitempty,max=100"`
+	ParentID    *uint  `json:"parent_id"`
+	Description string `json:"description"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+// @Summary Kategori Ağacı
+// @Description Tüm kategorileri alt kategorileriyle birlikte ağaç olarak listeler
+// @Tags Categories
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /categories [get]
+func (cc *CategoryController) GetCategories(c *gin.Context) {
+	categories, err := loadCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kategoriler getirilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"categories": categoryTree(categories, nil),
+	})
+}
+
+// @Summary Kategori Detayı
+// @Description Kategoriyi alt kategorileri ve kökten itibaren yolu (path) ile getirir. ID yerine slug da kullanılabilir.
+// @Tags Categories
+// @Produce json
+// @Param id path string true "Kategori ID veya slug"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /categories/{id} [get]
+func (cc *CategoryController) GetCategory(c *gin.Context) {
+	categories, err := loadCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kategoriler getirilemedi"})
+		return
+	}
+
+	category, ok := findCategory(categories, c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kategori bulunamadı"})
+		return
+	}
+
+	category.Children = categoryTree(categories, &category.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"category": category,
+		"path":     categoryPath(categories, category),
+	})
+}
+
+// @Summary Kategorideki Ürünler
+// @Description Kategoriye ve tüm alt kategorilerine ait aktif ürünleri sayfalı olarak listeler. ID yerine slug da kullanılabilir.
+// @Tags Categories
+// @Produce json
+// @Param id path string true "Kategori ID veya slug"
+// @Param page query int false "Sayfa numarası (varsayılan 1)"
+// @Param per_page query int false "Sayfa başına kayıt (varsayılan 20, en fazla 100)"
+// @Param sort query string false "Sıralama: id, name, price, stock, created_at (azalan için başına -)"
+// @Param shop_id query int false "Esnaf filtresi"
+// @Param min_price query number false "En düşük fiyat"
+// @Param max_price query number false "En yüksek fiyat"
+// @Param in_stock query bool false "Sadece stokta olanlar (true) veya tükenenler (false)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /categories/{id}/products [get]
+func (cc *CategoryController) GetCategoryProducts(c *gin.Context) {
+	categories, err := loadCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kategoriler getirilemedi"})
+		return
+	}
+
+	category, ok := findCategory(categories, c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kategori bulunamadı"})
+		return
+	}
+
+	lq, err := parseListQuery(c, productListOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := visibleProducts(config.DB.Model(&models.Product{})).
+		Where("category_id IN ?", categoryDescendantIDs(categories, category.ID))
+
+	shopID, err := queryUint(c, "shop_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if shopID != nil {
+		query = query.Where("shop_id = ?", *shopID)
+	}
+
+	query, err = filterProducts(c, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var products []models.Product
+	pagination, err := findPage(c, query, lq, &products, "Shop", "Category")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ürünler getirilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"category":   category,
+		"path":       categoryPath(categories, category),
+		"products":   products,
+		"pagination": pagination,
+	})
+}
+
+// @Summary Kategori Oluştur
+// @Description Yeni kategori oluşturur (sadece admin). Slug verilmezse isimden üretilir.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category body CategoryRequest true "Kategori bilgileri"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/categories [post]
+func (cc *CategoryController) CreateCategory(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	categories, err := loadCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kategoriler getirilemedi"})
+		return
+	}
+
+	category := models.Category{}
+	if status, err := applyCategoryRequest(&category, req, categories); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := config.DB.Create(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kategori oluşturulamadı"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Kategori başarıyla oluşturuldu",
+		"category": category,
+	})
+}
+
+// @Summary Kategori Güncelle
+// @Description Kategori bilgilerini ve üst kategorisini günceller (sadece admin)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Kategori ID"
+// @Param category body CategoryRequest true "Kategori bilgileri"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/categories/{id} [put]
+func (cc *CategoryController) UpdateCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz kategori ID"})
+		return
+	}
+
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var category models.Category
+	if err := config.DB.First(&category, categoryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kategori bulunamadı"})
+		return
+	}
+
+	categories, err := loadCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kategoriler getirilemedi"})
+		return
+	}
+
+	if status, err := applyCategoryRequest(&category, req, categories); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := config.DB.Save(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kategori güncellenemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Kategori başarıyla güncellendi",
+		"category": category,
+	})
+}
+
+// @Summary Kategori Sil
+// @Description Alt kategorisi ve ürünü olmayan kategoriyi siler (sadece admin)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Kategori ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/categories/{id} [delete]
+func (cc *CategoryController) DeleteCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz kategori ID"})
+		return
+	}
+
+	var category models.Category
+	if err := config.DB.First(&category, categoryID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kategori bulunamadı"})
+		return
+	}
+
+	var children, products int64
+	config.DB.Model(&models.Category{}).Where("parent_id = ?", category.ID).Count(&children)
+	config.DB.Model(&models.Product{}).Where("category_id = ?", category.ID).Count(&products)
+	if children > 0 || products > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Alt kategorisi veya ürünü olan kategori silinemez"})
+		return
+	}
+
+	if err := config.DB.Delete(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kategori silinemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Kategori başarıyla silindi",
+	})
+}
+
+// applyCategoryRequest isteği kategoriye uygular; üst kategorinin varlığını,
+// döngü oluşmamasını ve slug'ın benzersizliğini kontrol eder.
+func applyCategoryRequest(category *models.Category, req CategoryRequest, categories []models.Category) (int, error) {
+	if req.ParentID != nil {
+		if _, ok := findCategory(categories, strconv.FormatUint(uint64(*req.ParentID), 10)); !ok {
+			return http.StatusBadRequest, errors.New("Üst kategori bulunamadı")
+		}
+		if category.ID != 0 {
+			for _, id := range categoryDescendantIDs(categories, category.ID) {
+				if id == *req.ParentID {
+					return http.StatusBadRequest, errors.New("Kategori kendisinin veya alt kategorisinin altına taşınamaz")
+				}
+			}
+		}
+	}
+
+	slug := slugify(req.Slug)
+	if slug == "" {
+		slug = slugify(req.Name)
+	}
+	if slug == "" {
+		return http.StatusBadRequest, errors.New("Geçerli bir slug üretilemedi")
+	}
+
+	var count int64
+	config.DB.Model(&models.Category{}).Where("slug = ? AND id <> ?", slug, category.ID).Count(&count)
+	if count > 0 {
+		return http.StatusConflict, errors.New("Bu slug başka bir kategoride kullanılıyor")
+	}
+
+	category.Name = strings.TrimSpace(req.Name)
+	category.Slug = slug
+	category.ParentID = req.ParentID
+	category.Description = req.Description
+	category.SortOrder = req.SortOrder
+	return 0, nil
+}
+
+// Kategori tablosu küçük olduğundan ağaç işlemleri tamamı belleğe alınarak yapılır.
+func loadCategories() ([]models.Category, error) {
+	var categories []models.Category
+	err := config.DB.Order("sort_order, name, id").Find(&categories).Error
+	return categories, err
+}
+
+// findCategory kategoriyi ID ya da slug ile bulur.
+func findCategory(categories []models.Category, key string) (models.Category, bool) {
+	id, err := strconv.ParseUint(key, 10, 32)
+	for _, category := range categories {
+		if (err == nil && category.ID == uint(id)) || (err != nil && category.Slug == key) {
+			return category, true
+		}
+	}
+	return models.Category{}, false
+}
+
+func categoryTree(categories []models.Category, parentID *uint) []models.Category {
+	var tree []models.Category
+	for _, category := range categories {
+		if (parentID == nil && category.ParentID == nil) ||
+			(parentID != nil && category.ParentID != nil && *category.ParentID == *parentID) {
+			category.Children = categoryTree(categories, &category.ID)
+			tree = append(tree, category)
+		}
+	}
+	return tree
+}
+
+// categoryDescendantIDs kategorinin kendisi dahil tüm alt kategorilerinin ID'lerini döner.
+func categoryDescendantIDs(categories []models.Category, id uint) []uint {
+	ids := []uint{id}
+	for i := 0; i < len(ids); i++ {
+		for _, category := range categories {
+			if category.ParentID != nil && *category.ParentID == ids[i] {
+				ids = append(ids, category.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// categoryPath kökten kategorinin kendisine kadar olan kategorileri döner.
+func categoryPath(categories []models.Category, category models.Category) []gin.H {
+	var path []gin.H
+	current := category
+	for {
+		path = append([]gin.H{{"id": current.ID, "name": current.Name, "slug": current.Slug}}, path...)
+		if current.ParentID == nil {
+			return path
+		}
+		parent, ok := findCategory(categories, strconv.FormatUint(uint64(*current.ParentID), 10))
+		if !ok {
+			return path
+		}
+		current = parent
+	}
+}
+
+// validateCategoryID ürüne atanacak kategorinin var olduğunu kontrol eder.
+func validateCategoryID(id *uint) error {
+	if id == nil {
+		return nil
+	}
+	var category models.Category
+	if err := config.DB.First(&category, *id).Error; err != nil {
+		return errors.New("Kategori bulunamadı")
+	}
+	return nil
+}
+
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range search.Fold(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"tradesman-api/config"
+	"tradesman-api/models"
+)
+
+func TestCategoryTree(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		_, _, product := testCatalog(t, 10)
+
+		cc := &CategoryController{}
+		pc := &ProductController{}
+		r := asUser(models.User{ID: 1, Role: models.RoleAdmin})
+		r.POST("/admin/categories", cc.CreateCategory)
+		r.PUT("/admin/categories/:id", cc.UpdateCategory)
+		r.DELETE("/admin/categories/:id", cc.DeleteCategory)
+		r.GET("/categories", cc.GetCategories)
+		r.GET("/categories/:id", cc.GetCategory)
+		r.GET("/categories/:id/products", cc.GetCategoryProducts)
+		r.GET("/products", pc.GetProducts)
+
+		create := func(name string, parent *models.Category) models.Category {
+			t.Helper()
+			req := CategoryRequest{Name: name}
+			if parent != nil {
+				req.ParentID = &parent.ID
+			}
+			w := postJSON(r, "/admin/categories", req)
+			if w.Code != http.StatusCreated {
+				t.Fatalf("%s: yanıt kodu %d, beklenen 201: %s", name, w.Code, w.Body)
+			}
+			var resp struct {
+				Category models.Category `json:"category"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			return resp.Category
+		}
+		get := func(path string, dest interface{}) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			if w.Code == http.StatusOK && dest != nil {
+				if err := json.Unmarshal(w.Body.Bytes(), dest); err != nil {
+					t.Fatal(err)
+				}
+			}
+			return w
+		}
+
+		food := create("Gıda", nil)
+		bakery := create("Fırın Ürünleri", &food)
+		bread := create("Ekmek", &bakery)
+		drinks := create("İçecek", nil)
+		if food.Slug != "gida" || bakery.Slug != "firin-urunleri" || drinks.Slug != "icecek" {
+			t.Errorf("sluglar %q %q %q", food.Slug, bakery.Slug, drinks.Slug)
+		}
+		if w := postJSON(r, "/admin/categories", CategoryRequest{Name: "GIDA"}); w.Code != http.StatusConflict {
+			t.Errorf("aynı slug: yanıt kodu %d, beklenen 409", w.Code)
+		}
+
+		// Kategori kendisinin veya alt kategorisinin altına taşınamaz
+		for _, parent := range []uint{food.ID, bread.ID} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, jsonRequest(http.MethodPut, fmt.Sprintf("/admin/categories/%d", food.ID), CategoryRequest{Name: "Gıda", ParentID: &parent}))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("üst kategori %d: yanıt kodu %d, beklenen 400: %s", parent, w.Code, w.Body)
+			}
+		}
+
+		var tree struct {
+			Categories []models.Category `json:"categories"`
+		}
+		get("/categories", &tree)
+		if len(tree.Categories) != 2 || tree.Categories[0].ID != food.ID ||
+			len(tree.Categories[0].Children) != 1 || len(tree.Categories[0].Children[0].Children) != 1 ||
+			tree.Categories[0].Children[0].Children[0].ID != bread.ID {
+			t.Errorf("kategori ağacı beklenen gibi değil: %+v", tree.Categories)
+		}
+
+		var detail struct {
+			Path []struct {
+				Slug string `json:"slug"`
+			} `json:"path"`
+		}
+		get("/categories/ekmek", &detail)
+		if len(detail.Path) != 3 || detail.Path[0].Slug != "gida" || detail.Path[2].Slug != "ekmek" {
+			t.Errorf("kategori yolu beklenen gibi değil: %+v", detail.Path)
+		}
+
+		// Ürünler üst kategorilerin listelerinde de görünür
+		config.DB.Model(&product).Update("category_id", bread.ID)
+		for path, want := range map[string]int64{
+			"/categories/gida/products":                        1,
+			"/categories/firin-urunleri/products":              1,
+			"/categories/icecek/products":                      0,
+			fmt.Sprintf("/products?category_id=%d", food.ID):   1,
+			fmt.Sprintf("/products?category_id=%d", drinks.ID): 0,
+		} {
+			var page productPage
+			if w := get(path, &page); w.Code != http.StatusOK {
+				t.Fatalf("%s: yanıt kodu %d: %s", path, w.Code, w.Body)
+			}
+			if page.Pagination.Total != want {
+				t.Errorf("%s: %d ürün, beklenen %d", path, page.Pagination.Total, want)
+			}
+		}
+		if w := get("/categories/yok/products", nil); w.Code != http.StatusNotFound {
+			t.Errorf("olmayan kategori: yanıt kodu %d, beklenen 404", w.Code)
+		}
+
+		// Alt kategorisi veya ürünü olan kategori silinemez
+		for _, id := range []uint{bakery.ID, bread.ID} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/admin/categories/%d", id), nil))
+			if w.Code != http.StatusConflict {
+				t.Errorf("kategori %d silme: yanıt kodu %d, beklenen 409", id, w.Code)
+			}
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/admin/categories/%d", drinks.ID), nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("boş kategori silme: yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+	})
+}
+
+func TestSlugify(t *testing.T) {
+	for in, want := range map[string]string{
+		"Fırın Ürünleri":      "firin-urunleri",
+		"  Şarküteri & Et  ":  "sarkuteri-et",
+		"İÇECEK":              "icecek",
+		"Süt/Yoğurt--Peynir!": "sut-yogurt-peynir",
+		"!!!":                 "",
+	} {
+		if got := slugify(in); got != want {
+			t.Errorf("slugify(%q) = %q, beklenen %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"tradesman-api/config"
+	"tradesman-api/middleware"
+	"tradesman-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// postIdempotent isteği Idempotency-Key başlığıyla gönderir.
+func postIdempotent(r *gin.Engine, path, key string, body interface{}) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := jsonRequest(http.MethodPost, path, body)
+	req.Header.Set(middleware.IdempotencyKeyHeader, key)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateOrderIdempotency(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		customer, shop, product := testCatalog(t, 10)
+
+		oc := &OrderController{}
+		r := asUser(customer)
+		r.POST("/orders", middleware.Idempotency(), oc.CreateOrder)
+		req := CreateOrderRequest{
+			ShopID: shop.ID,
+			Items:  []OrderItem{{ProductID: product.ID, Quantity: 1}},
+		}
+
+		first := postIdempotent(r, "/orders", "siparis-1", req)
+		if first.Code != http.StatusCreated {
+			t.Fatalf("yanıt kodu %d, beklenen 201: %s", first.Code, first.Body)
+		}
+
+		// Aynı istek handler çalışmadan ilk yanıtla döner
+		w := postIdempotent(r, "/orders", "siparis-1", req)
+		if w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "true" {
+			t.Fatalf("yanıt kodu %d, beklenen tekrarlanmış 201: %s", w.Code, w.Body)
+		}
+		if w.Body.String() != first.Body.String() {
+			t.Errorf("tekrarlanan yanıt %s, beklenen %s", w.Body, first.Body)
+		}
+
+		// Aynı anahtar farklı gövdeyle kullanılamaz
+		req.Items[0].Quantity = 2
+		w = postIdempotent(r, "/orders", "siparis-1", req)
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("yanıt kodu %d, beklenen 422: %s", w.Code, w.Body)
+		}
+
+		var orders int64
+		config.DB.Model(&models.Order{}).Count(&orders)
+		if orders != 1 {
+			t.Errorf("%d sipariş oluştu, beklenen 1", orders)
+		}
+		checkLedger(t)
+	})
+}
+
+func TestIdempotencyPanicReleasesKey(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		user := models.User{Name: "Müşteri", Email: "musteri@example.com", Password: "x", Role: models.RoleCustomer}
+		if err := config.DB.Create(&user).Error; err != nil {
+			t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+		}
+
+		calls := 0
+		r := asUser(user)
+		r.Use(gin.Recovery())
+		r.POST("/orders", middleware.Idempotency(), func(c *gin.Context) {
+			calls++
+			if calls == 1 {
+				panic("beklenmeyen hata")
+			}
+			c.JSON(http.StatusCreated, gin.H{"id": 1})
+		})
+
+		w := postIdempotent(r, "/orders", "siparis-1", gin.H{"shop_id": 1})
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("yanıt kodu %d, beklenen 500: %s", w.Code, w.Body)
+		}
+
+		// Anahtar bırakıldığı için tekrar deneme handler'ı yeniden çalıştırır
+		w = postIdempotent(r, "/orders", "siparis-1", gin.H{"shop_id": 1})
+		if w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "" {
+			t.Fatalf("yanıt kodu %d, beklenen tekrarlanmamış 201: %s", w.Code, w.Body)
+		}
+		if calls != 2 {
+			t.Errorf("handler %d kez çalıştı, beklenen 2", calls)
+		}
+	})
+}
@@ -0,0 +1,369 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"tradesman-api/config"
+	"tradesman-api/media"
+	"tradesman-api/models"
+	"tradesman-api/storage"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Bir ürüne yüklenebilecek en fazla görsel sayısı
+const maxProductImages = 10
+
+var errTooManyImages = fmt.Errorf("Bir ürünün en fazla %d görseli olabilir", maxProductImages)
+
+type ReorderImagesRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required,min=1"` // Ürünün tüm görselleri, istenen sırayla
+}
+
+// Depoya yazılmayı bekleyen, işlenmiş görsel
+type pendingImage struct {
+	image  models.ProductImage
+	data   []byte
+	thumbs []media.Thumbnail
+}
+
+// @Summary Ürün Görseli Yükle
+// @Description Ürüne bir veya birden fazla görsel yükler (sadece ürün sahibi). JPEG, PNG, GIF ve WebP kabul edilir; tür dosya içeriğinden tespit edilir. Her görsel için small (150px), medium (400px) ve large (800px) thumbnail üretilir. Görseller mevcutların sonuna eklenir.
+// @Tags Products
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Param images formData file true "Görsel dosyaları (aynı alan adıyla birden fazla)"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Router /products/{id}/images [post]
+func (pc *ProductController) UploadProductImages(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	maxSize := int64(config.App.MaxImageSize)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxProductImages*maxSize+1<<20)
+	form, err := c.MultipartForm()
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "İstek çok büyük"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz multipart form"})
+		return
+	}
+
+	files := form.File["images"]
+	if len(files) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "En az bir görsel yüklenmeli (images alanı)"})
+		return
+	}
+
+	// Dosyaları işlemeden önce hızlı kontrol; kesin kontrol kayıt transaction'ında yapılır
+	var existing int64
+	if err := config.DB.Model(&models.ProductImage{}).Where("product_id = ?", product.ID).Count(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Görseller getirilemedi"})
+		return
+	}
+	if int(existing)+len(files) > maxProductImages {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errTooManyImages.Error()})
+		return
+	}
+
+	// Önce tüm dosyalar doğrulanır; biri hatalıysa hiçbiri kaydedilmez
+	pending := make([]pendingImage, 0, len(files))
+	for i, file := range files {
+		if file.Size > maxSize {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": fmt.Sprintf("%s en fazla %d KB olabilir", file.Filename, maxSize>>10),
+			})
+			return
+		}
+
+		data, err := readFormFile(file, maxSize)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": file.Filename + " okunamadı"})
+			return
+		}
+
+		processed, err := media.Process(data)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": file.Filename + ": " + err.Error()})
+			return
+		}
+
+		key, err := newImageKey(product.ID, processed.Ext)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Görsel kaydedilemedi"})
+			return
+		}
+
+		pending = append(pending, pendingImage{
+			image: models.ProductImage{
+				ProductID:   product.ID,
+				Key:         key,
+				ContentType: processed.ContentType,
+				Size:        int64(len(data)),
+				Width:       processed.Width,
+				Height:      processed.Height,
+				SortOrder:   i,
+			},
+			data:   data,
+			thumbs: processed.Thumbnails,
+		})
+	}
+
+	ctx := c.Request.Context()
+	var stored []string
+	for _, p := range pending {
+		keys, err := storeImage(ctx, p)
+		stored = append(stored, keys...)
+		if err != nil {
+			removeStoredFiles(stored)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Görsel kaydedilemedi"})
+			return
+		}
+	}
+
+	images := make([]models.ProductImage, len(pending))
+	for i, p := range pending {
+		images[i] = p.image
+	}
+	// Eşzamanlı yüklemeler sınırı aşmasın diye sayım, ürün satırı kilitlenerek
+	// kayıtla aynı transaction'da tekrarlanır
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockForUpdate(tx).First(&models.Product{}, product.ID).Error; err != nil {
+			return err
+		}
+		var current struct {
+			Count     int
+			NextOrder int
+		}
+		err := tx.Model(&models.ProductImage{}).
+			Select("COUNT(*) AS count, COALESCE(MAX(sort_order) + 1, 0) AS next_order").
+			Where("product_id = ?", product.ID).
+			Scan(&current).Error
+		if err != nil {
+			return err
+		}
+		if current.Count+len(images) > maxProductImages {
+			return errTooManyImages
+		}
+		for i := range images {
+			images[i].SortOrder += current.NextOrder
+		}
+		return tx.Create(&images).Error
+	})
+	if err != nil {
+		removeStoredFiles(stored)
+		if errors.Is(err, errTooManyImages) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Görsel kaydedilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Görseller başarıyla yüklendi",
+		"images":  images,
+	})
+}
+
+// @Summary Ürün Görsellerini Sırala
+// @Description Ürün görsellerinin sırasını değiştirir (sadece ürün sahibi). Listede ürünün tüm görselleri bulunmalı; ilk görsel kapak görselidir.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Param order body ReorderImagesRequest true "Görsel ID'leri"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /products/{id}/images/order [put]
+func (pc *ProductController) ReorderProductImages(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	var req ReorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var images []models.ProductImage
+	if err := config.DB.Where("product_id = ?", product.ID).Find(&images).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Görseller getirilemedi"})
+		return
+	}
+
+	byID := make(map[uint]*models.ProductImage, len(images))
+	for i := range images {
+		byID[images[i].ID] = &images[i]
+	}
+	if len(req.ImageIDs) != len(images) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Listede ürünün tüm görselleri bir kez bulunmalı"})
+		return
+	}
+	for i, id := range req.ImageIDs {
+		img, ok := byID[id]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Listede ürünün tüm görselleri bir kez bulunmalı"})
+			return
+		}
+		img.SortOrder = i
+		delete(byID, id)
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, img := range images {
+			if err := tx.Model(&img).Update("sort_order", img.SortOrder).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Görsel sırası güncellenemedi"})
+		return
+	}
+
+	images, err = loadProductImages(product.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Görseller getirilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Görsel sırası güncellendi",
+		"images":  images,
+	})
+}
+
+// @Summary Ürün Görseli Sil
+// @Description Ürün görselini ve thumbnail'lerini siler (sadece ürün sahibi)
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Param imageId path int true "Görsel ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /products/{id}/images/{imageId} [delete]
+func (pc *ProductController) DeleteProductImage(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	imageID, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz görsel ID"})
+		return
+	}
+
+	var image models.ProductImage
+	if err := config.DB.Where("product_id = ?", product.ID).First(&image, imageID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Görsel bulunamadı"})
+		return
+	}
+
+	if err := config.DB.Delete(&image).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Görsel silinemedi"})
+		return
+	}
+	removeStoredFiles(image.Keys())
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Görsel başarıyla silindi",
+	})
+}
+
+func loadProductImages(productID uint) ([]models.ProductImage, error) {
+	var images []models.ProductImage
+	err := config.DB.Where("product_id = ?", productID).Order("sort_order, id").Find(&images).Error
+	return images, err
+}
+
+// readFormFile dosyayı en fazla limit byte olacak şekilde okur.
+func readFormFile(file *multipart.FileHeader, limit int64) ([]byte, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, errors.New("dosya çok büyük")
+	}
+	return data, nil
+}
+
+// newImageKey tahmin edilemeyen bir depo anahtarı üretir (ör. products/3/9f2c….jpg).
+func newImageKey(productID uint, ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("products/%d/%s%s", productID, hex.EncodeToString(b), ext), nil
+}
+
+// storeImage orijinal dosyayı ve thumbnail'leri depoya yazar; yazılan
+// anahtarları hata durumunda da döner.
+func storeImage(ctx context.Context, p pendingImage) ([]string, error) {
+	var keys []string
+	if err := storage.Default.Put(ctx, p.image.Key, bytes.NewReader(p.data), p.image.ContentType); err != nil {
+		return keys, err
+	}
+	keys = append(keys, p.image.Key)
+
+	thumbType := "image/png"
+	if media.ThumbnailExt(p.image.ContentType) == ".jpg" {
+		thumbType = "image/jpeg"
+	}
+	for _, thumb := range p.thumbs {
+		key := p.image.ThumbnailKey(thumb.Size)
+		if err := storage.Default.Put(ctx, key, bytes.NewReader(thumb.Data), thumbType); err != nil {
+			return keys, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// removeStoredFiles dosyaları depodan siler. Kayıtlar zaten silindiğinden
+// hatalar yalnızca loglanır.
+func removeStoredFiles(keys []string) {
+	for _, key := range keys {
+		if err := storage.Default.Delete(context.Background(), key); err != nil {
+			log.Printf("⚠️  Dosya silinemedi (%s): %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"tradesman-api/config"
+	"tradesman-api/models"
+	"tradesman-api/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testPNG verilen boyutta tek renkli bir PNG üretir.
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// useTestStorage depoyu test dizinindeki yerel depoya çevirir ve dizini döner.
+func useTestStorage(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	previous := storage.Default
+	storage.Default = storage.NewLocal(dir, "/uploads")
+	t.Cleanup(func() { storage.Default = previous })
+	return dir
+}
+
+// postImages dosyaları images alanıyla ürünün görsellerine yükler.
+func postImages(r *gin.Engine, productID uint, files ...[]byte) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for i, data := range files {
+		file, _ := form.CreateFormFile("images", fmt.Sprintf("gorsel%d.png", i))
+		file.Write(data)
+	}
+	form.Close()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/products/%d/images", productID), &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// gatedStorage Put çağrılarını release kapanana kadar bekletir ve her
+// bekleyen çağrıyı arrived kanalına bildirir.
+type gatedStorage struct {
+	storage.Storage
+	arrived chan struct{}
+	release chan struct{}
+}
+
+func (s *gatedStorage) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	select {
+	case <-s.release:
+	default:
+		s.arrived <- struct{}{}
+		<-s.release
+	}
+	return s.Storage.Put(ctx, key, r, contentType)
+}
+
+// storedFiles depo dizinindeki dosya sayısını döner.
+func storedFiles(t *testing.T, dir string) int {
+	t.Helper()
+	n := 0
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			n++
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestProductImages(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		dir := useTestStorage(t)
+		_, shop, product := testCatalog(t, 10)
+		owner := models.User{ID: shop.UserID, Role: models.RoleShop}
+
+		pc := &ProductController{}
+		r := asUser(owner)
+		r.POST("/products/:id/images", pc.UploadProductImages)
+		r.PUT("/products/:id/images/order", pc.ReorderProductImages)
+		r.DELETE("/products/:id/images/:imageId", pc.DeleteProductImage)
+		r.DELETE("/products/:id", pc.DeleteProduct)
+
+		w := postImages(r, product.ID, testPNG(t, 1000, 500), testPNG(t, 100, 300))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("yanıt kodu %d, beklenen 201: %s", w.Code, w.Body)
+		}
+		var resp struct {
+			Images []models.ProductImage `json:"images"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Images) != 2 || resp.Images[0].Width != 1000 || resp.Images[0].ContentType != "image/png" || resp.Images[1].SortOrder != 1 {
+			t.Fatalf("görseller beklenen gibi değil: %s", w.Body)
+		}
+		if len(resp.Images[0].Thumbnails) != 3 || resp.Images[0].URL == "" {
+			t.Errorf("görsel adresleri eksik: %+v", resp.Images[0])
+		}
+		// Her görsel için orijinal ve üç thumbnail
+		if n := storedFiles(t, dir); n != 8 {
+			t.Errorf("depoda %d dosya, beklenen 8", n)
+		}
+
+		// Thumbnail en-boy oranını korur ve küçük görseller büyütülmez
+		thumbSizes := map[string][2]int{"small": {150, 75}, "medium": {400, 200}, "large": {800, 400}}
+		var stored models.ProductImage
+		config.DB.First(&stored, resp.Images[0].ID)
+		for size, want := range thumbSizes {
+			f, err := os.Open(filepath.Join(dir, filepath.FromSlash(stored.ThumbnailKey(size))))
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, _, err := image.DecodeConfig(f)
+			f.Close()
+			if err != nil || cfg.Width != want[0] || cfg.Height != want[1] {
+				t.Errorf("%s thumbnail %dx%d, beklenen %dx%d", size, cfg.Width, cfg.Height, want[0], want[1])
+			}
+		}
+		var small models.ProductImage
+		config.DB.First(&small, resp.Images[1].ID)
+		f, err := os.Open(filepath.Join(dir, filepath.FromSlash(small.ThumbnailKey("large"))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg, _, err := image.DecodeConfig(f)
+		f.Close()
+		if err != nil || cfg.Width != 100 || cfg.Height != 300 {
+			t.Errorf("küçük görselin large thumbnail'i %dx%d, beklenen 100x300", cfg.Width, cfg.Height)
+		}
+
+		// Tür içerikten tespit edilir; biri hatalıysa hiçbiri kaydedilmez
+		if w := postImages(r, product.ID, testPNG(t, 10, 10), []byte("<html>resim değil</html>")); w.Code != http.StatusBadRequest {
+			t.Errorf("görsel olmayan dosya: yanıt kodu %d, beklenen 400", w.Code)
+		}
+		maxSize := config.App.MaxImageSize
+		config.App.MaxImageSize = 1 << 10
+		if w := postImages(r, product.ID, testPNG(t, 300, 300)); w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("büyük dosya: yanıt kodu %d, beklenen 413", w.Code)
+		}
+		config.App.MaxImageSize = maxSize
+		tooMany := make([][]byte, maxProductImages-1)
+		for i := range tooMany {
+			tooMany[i] = testPNG(t, 10, 10)
+		}
+		if w := postImages(r, product.ID, tooMany...); w.Code != http.StatusBadRequest {
+			t.Errorf("görsel sınırı: yanıt kodu %d, beklenen 400", w.Code)
+		}
+		var count int64
+		config.DB.Model(&models.ProductImage{}).Where("product_id = ?", product.ID).Count(&count)
+		if count != 2 || storedFiles(t, dir) != 8 {
+			t.Errorf("reddedilen yüklemelerden sonra %d kayıt, %d dosya", count, storedFiles(t, dir))
+		}
+
+		// Sıralama ürünün tüm görsellerini içermeli
+		first, second := resp.Images[0].ID, resp.Images[1].ID
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, jsonRequest(http.MethodPut, fmt.Sprintf("/products/%d/images/order", product.ID), ReorderImagesRequest{ImageIDs: []uint{second}}))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("eksik sıralama: yanıt kodu %d, beklenen 400", w.Code)
+		}
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, jsonRequest(http.MethodPut, fmt.Sprintf("/products/%d/images/order", product.ID), ReorderImagesRequest{ImageIDs: []uint{second, first}}))
+		if w.Code != http.StatusOK {
+			t.Fatalf("sıralama yanıt kodu %d: %s", w.Code, w.Body)
+		}
+		if images, _ := loadProductImages(product.ID); len(images) != 2 || images[0].ID != second {
+			t.Errorf("kapak görseli değişmedi: %+v", images)
+		}
+
+		// Silinen görselin dosyaları depodan da silinir
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/products/%d/images/%d", product.ID, first), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("silme yanıt kodu %d: %s", w.Code, w.Body)
+		}
+		if n := storedFiles(t, dir); n != 4 {
+			t.Errorf("görsel silindikten sonra depoda %d dosya, beklenen 4", n)
+		}
+
+		// Ürün silinince kalan görselleri de silinir
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/products/%d", product.ID), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("ürün silme yanıt kodu %d: %s", w.Code, w.Body)
+		}
+		if n := storedFiles(t, dir); n != 0 {
+			t.Errorf("ürün silindikten sonra depoda %d dosya kaldı", n)
+		}
+	})
+}
+
+func TestConcurrentImageUploads(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		const uploads, perUpload = 4, 3
+		dir := useTestStorage(t)
+		// Tüm yüklemeler ön kontrolü geçip depoya yazmaya başlayana kadar beklenir
+		gate := &gatedStorage{Storage: storage.Default, arrived: make(chan struct{}, uploads), release: make(chan struct{})}
+		storage.Default = gate
+		_, shop, product := testCatalog(t, 10)
+
+		pc := &ProductController{}
+		r := asUser(models.User{ID: shop.UserID, Role: models.RoleShop})
+		r.POST("/products/:id/images", pc.UploadProductImages)
+
+		files := make([][]byte, perUpload)
+		for i := range files {
+			files[i] = testPNG(t, 10, 10)
+		}
+
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			statuses = map[int]int{}
+		)
+		start := make(chan struct{})
+		for i := 0; i < uploads; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				w := postImages(r, product.ID, files...)
+
+				mu.Lock()
+				statuses[w.Code]++
+				mu.Unlock()
+			}()
+		}
+		close(start)
+		for i := 0; i < uploads; i++ {
+			<-gate.arrived
+		}
+		close(gate.release)
+		wg.Wait()
+
+		// Sınır 10 görsel: dört yüklemeden yalnızca üçü sığar
+		want := maxProductImages / perUpload
+		if statuses[http.StatusCreated] != want || statuses[http.StatusBadRequest] != uploads-want {
+			t.Errorf("yanıt kodları %v, beklenen %d×201 ve %d×400", statuses, want, uploads-want)
+		}
+
+		images, err := loadProductImages(product.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(images) != want*perUpload {
+			t.Fatalf("%d görsel kaydedildi, beklenen %d", len(images), want*perUpload)
+		}
+		for i, img := range images {
+			if img.SortOrder != i {
+				t.Errorf("görsel %d sırası %d, beklenen %d", img.ID, img.SortOrder, i)
+			}
+		}
+		// Reddedilen yüklemelerin dosyaları depoda kalmaz
+		if n := storedFiles(t, dir); n != want*perUpload*4 {
+			t.Errorf("depoda %d dosya, beklenen %d", n, want*perUpload*4)
+		}
+	})
+}
@@ -0,0 +1,271 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"tradesman-api/config"
+	"tradesman-api/inventory"
+	"tradesman-api/migrations"
+	"tradesman-api/models"
+	"tradesman-api/money"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Entegrasyon testleri her zaman geçici bir SQLite veritabanında, bu değişken
+// verilmişse ayrıca Postgres'te çalışır. Postgres'te her test kendi şemasını
+// oluşturup siler; veritabanındaki diğer tablolara dokunulmaz.
+//
+//	TEST_POSTGRES_DSN="host=localhost user=tradesman password=secret dbname=tradesman_test sslmode=disable" go test ./...
+const postgresDSNEnv = "TEST_POSTGRES_DSN"
+
+// forEachDatabase testi her veritabanı sürücüsü için ayrı bir alt test olarak,
+// migrasyonları uygulanmış boş bir veritabanıyla çalıştırır.
+func forEachDatabase(t *testing.T, fn func(t *testing.T)) {
+	t.Run(config.DriverSQLite, func(t *testing.T) {
+		openTestDB(t, config.DriverSQLite, filepath.Join(t.TempDir(), "test.db"))
+		fn(t)
+	})
+	t.Run(config.DriverPostgres, func(t *testing.T) {
+		dsn := os.Getenv(postgresDSNEnv)
+		if dsn == "" {
+			t.Skip(postgresDSNEnv + " verilmedi")
+		}
+		openTestDB(t, config.DriverPostgres, postgresSchema(t, dsn))
+		fn(t)
+	})
+}
+
+// openTestDB config.DB'yi verilen veritabanına bağlar ve migrasyonları uygular.
+func openTestDB(t *testing.T, driver, dsn string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	t.Setenv("APP_ENV", config.EnvTest)
+	t.Setenv("DB_DRIVER", driver)
+	t.Setenv("DB_DSN", dsn)
+	t.Setenv("LOG_LEVEL", "error")
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("konfigürasyon yüklenemedi: %v", err)
+	}
+
+	config.Connect()
+	t.Cleanup(func() {
+		if sqlDB, err := config.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if _, err := migrations.Up(config.DB); err != nil {
+		t.Fatalf("migrasyonlar uygulanamadı: %v", err)
+	}
+}
+
+// postgresSchema test için yeni bir şema oluşturur, test bitince siler ve
+// bağlantıları bu şemaya yönlendiren DSN'i döner.
+func postgresSchema(t *testing.T, dsn string) string {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("Postgres'e bağlanılamadı: %v", err)
+	}
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if err := db.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("test şeması oluşturulamadı: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if strings.Contains(dsn, "://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			t.Fatalf("geçersiz %s: %v", postgresDSNEnv, err)
+		}
+		q := u.Query()
+		q.Set("search_path", schema)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	return dsn + " search_path=" + schema
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		all := migrations.All()
+
+		pending, err := migrations.Pending(config.DB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pending) != 0 {
+			t.Fatalf("%d migrasyon uygulanmamış", len(pending))
+		}
+
+		rolledBack, err := migrations.Down(config.DB, len(all))
+		if err != nil {
+			t.Fatalf("migrasyonlar geri alınamadı: %v", err)
+		}
+		if len(rolledBack) != len(all) {
+			t.Errorf("%d migrasyon geri alındı, beklenen %d", len(rolledBack), len(all))
+		}
+		if config.DB.Migrator().HasTable(&models.User{}) {
+			t.Error("geri alma sonrası users tablosu hâlâ var")
+		}
+
+		applied, err := migrations.Up(config.DB)
+		if err != nil {
+			t.Fatalf("migrasyonlar yeniden uygulanamadı: %v", err)
+		}
+		if len(applied) != len(all) {
+			t.Errorf("%d migrasyon uygulandı, beklenen %d", len(applied), len(all))
+		}
+	})
+}
+
+func TestCreateOrder(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		customer, shop, product := testCatalog(t, 10)
+
+		oc := &OrderController{}
+		r := asUser(customer)
+		r.POST("/orders", oc.CreateOrder)
+
+		body, _ := json.Marshal(CreateOrderRequest{
+			ShopID: shop.ID,
+			Items:  []OrderItem{{ProductID: product.ID, Quantity: 3}},
+			Note:   "Kapıya bırakın",
+		})
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("yanıt kodu %d, beklenen 201: %s", w.Code, w.Body)
+		}
+
+		var resp struct {
+			Order models.Order `json:"order"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Order.TotalAmount != money.Amount(3000) {
+			t.Errorf("sipariş toplamı %v, beklenen 30.00", resp.Order.TotalAmount)
+		}
+		if len(resp.Order.OrderItems) != 1 || resp.Order.OrderItems[0].Quantity != 3 {
+			t.Errorf("sipariş kalemleri beklenen gibi değil: %+v", resp.Order.OrderItems)
+		}
+
+		var stored models.Product
+		if err := config.DB.First(&stored, product.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if stored.Stock != 7 {
+			t.Errorf("stok %v, beklenen 7", stored.Stock)
+		}
+
+		var sale models.StockMovement
+		err := config.DB.Where("order_id = ? AND type = ?", resp.Order.ID, models.StockMovementSale).First(&sale).Error
+		if err != nil {
+			t.Fatalf("satış hareketi bulunamadı: %v", err)
+		}
+		if sale.Quantity != -3 || sale.BalanceAfter != 7 {
+			t.Errorf("satış hareketi %v / %v, beklenen -3 / 7", sale.Quantity, sale.BalanceAfter)
+		}
+
+		discrepancies, err := inventory.Check(config.DB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(discrepancies) > 0 {
+			t.Errorf("stok defterle tutmuyor: %s", fmt.Sprint(discrepancies))
+		}
+	})
+}
+
+func TestSearchProducts(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		_, shop, _ := testCatalog(t, 10)
+
+		products := []models.Product{
+			{Name: "Poğaça", Description: "Sabah fırından çıkan, simit tadında peynirli poğaça"},
+			{Name: "Susamlı Simit", Description: "Taze ve çıtır"},
+			{Name: "Çay", Description: "Demlik çay"},
+		}
+		for i := range products {
+			products[i].ShopID = shop.ID
+			products[i].Price = money.Amount(500)
+			products[i].Currency = money.DefaultCurrency
+			products[i].Unit = models.UnitPiece
+			products[i].IsActive = true
+			products[i].ApplyUnitDefaults()
+			if err := config.DB.Create(&products[i]).Error; err != nil {
+				t.Fatalf("ürün oluşturulamadı: %v", err)
+			}
+		}
+
+		pc := &ProductController{}
+		r := gin.New()
+		r.GET("/products/search", pc.SearchProducts)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products/search?q="+url.QueryEscape("SİMİT"), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+
+		var resp struct {
+			Products []ProductSearchResult `json:"products"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Products) != 2 {
+			t.Fatalf("%d sonuç, beklenen 2: %s", len(resp.Products), w.Body)
+		}
+		// İsimde geçen eşleşme açıklamadakinden önce gelir
+		if resp.Products[0].ID != products[1].ID {
+			t.Errorf("ilk sonuç %q, beklenen %q", resp.Products[0].Name, products[1].Name)
+		}
+		if want := "Susamlı <mark>Simit</mark>"; resp.Products[0].Highlight.Name != want {
+			t.Errorf("işaretlenmiş isim %q, beklenen %q", resp.Products[0].Highlight.Name, want)
+		}
+
+		// Stoğu biten ürünleri gizleyen dükkanın tükenen ürünü (poğaça) sonuçlarda yer almaz
+		if err := config.DB.Model(&shop).Update("hide_out_of_stock", true).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := config.DB.Model(&models.Product{}).Where("id = ?", products[1].ID).Update("stock", 5).Error; err != nil {
+			t.Fatal(err)
+		}
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products/search?q=simit", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		resp.Products = nil
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Products) != 1 || resp.Products[0].ID != products[1].ID {
+			t.Errorf("tükenen ürün gizlenmedi: %s", w.Body)
+		}
+	})
+}
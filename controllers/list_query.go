@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"tradesman-api/money"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+	// Çok büyük page değerlerinde (page-1)*per_page taşmasın diye üst sınır
+	maxOffset = math.MaxInt32
+)
+
+// Liste uç noktalarının ortak sayfalama ve sıralama parametreleri:
+// ?page=2&per_page=20&sort=-price (başında "-" azalan sıralama)
+type ListQuery struct {
+	Page    int
+	PerPage int
+	Sort    string
+	Desc    bool
+	column  string
+	table   string
+}
+
+// table: sıralama kolonlarının tablosu; JOIN'li sorgularda belirsiz kalmasınlar
+// diye kolonlar tablo adıyla yazılır (boşsa kolon olduğu gibi kullanılır)
+// sorts: sort parametresinde kabul edilen anahtar → kolon eşlemesi
+// defaultSort: parametre yoksa kullanılacak anahtar (ör. "-created_at")
+type listOptions struct {
+	table       string
+	sorts       map[string]string
+	defaultSort string
+}
+
+func parseListQuery(c *gin.Context, opts listOptions) (ListQuery, error) {
+	lq := ListQuery{Page: 1, PerPage: defaultPerPage}
+
+	if page := c.Query("page"); page != "" {
+		p, err := strconv.Atoi(page)
+		if err != nil || p < 1 {
+			return lq, errors.New("page 1 veya daha büyük bir sayı olmalı")
+		}
+		lq.Page = p
+	}
+
+	if perPage := c.Query("per_page"); perPage != "" {
+		p, err := strconv.Atoi(perPage)
+		if err != nil || p < 1 || p > maxPerPage {
+			return lq, fmt.Errorf("per_page 1 ile %d arasında olmalı", maxPerPage)
+		}
+		lq.PerPage = p
+	}
+
+	sortKey := c.DefaultQuery("sort", opts.defaultSort)
+	if strings.HasPrefix(sortKey, "-") {
+		lq.Desc = true
+		sortKey = strings.TrimPrefix(sortKey, "-")
+	}
+
+	column, ok := opts.sorts[sortKey]
+	if !ok {
+		keys := make([]string, 0, len(opts.sorts))
+		for key := range opts.sorts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		return lq, fmt.Errorf("geçersiz sort değeri, kullanılabilir: %s", strings.Join(keys, ", "))
+	}
+	lq.Sort = sortKey
+	lq.table = opts.table
+	lq.column = lq.qualify(column)
+
+	return lq, nil
+}
+
+func (lq ListQuery) qualify(column string) string {
+	if lq.table == "" {
+		return column
+	}
+	return lq.table + "." + column
+}
+
+// Offset istenen sayfanın ilk kaydının sırasıdır; maxOffset ile sınırlanır.
+func (lq ListQuery) Offset() int {
+	if lq.Page-1 > maxOffset/lq.PerPage {
+		return maxOffset
+	}
+	return (lq.Page - 1) * lq.PerPage
+}
+
+// Apply sıralama ve sayfalamayı sorguya ekler. Aynı değerli satırların sayfalar
+// arasında kaymaması için id ikincil sıralama olarak eklenir.
+func (lq ListQuery) Apply(db *gorm.DB) *gorm.DB {
+	direction := "ASC"
+	if lq.Desc {
+		direction = "DESC"
+	}
+
+	db = db.Order(lq.column + " " + direction)
+	if id := lq.qualify("id"); lq.column != id {
+		db = db.Order(id + " " + direction)
+	}
+
+	return db.Offset(lq.Offset()).Limit(lq.PerPage)
+}
+
+// findPage sorgunun toplam kayıt sayısını alır, istenen sayfayı preloads ile
+// dest içine yükler ve sayfalama başlıklarını yazar.
+func findPage(c *gin.Context, query *gorm.DB, lq ListQuery, dest interface{}, preloads ...string) (gin.H, error) {
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page := lq.Apply(query)
+	for _, p := range preloads {
+		page = page.Preload(p)
+	}
+	if err := page.Find(dest).Error; err != nil {
+		return nil, err
+	}
+
+	return paginate(c, lq, total), nil
+}
+
+func (lq ListQuery) totalPages(total int64) int {
+	return int(math.Ceil(float64(total) / float64(lq.PerPage)))
+}
+
+// paginate toplam kayıt sayısını X-Total-Count ve Link başlıklarına yazar ve
+// yanıta eklenecek sayfalama bilgisini döner.
+func paginate(c *gin.Context, lq ListQuery, total int64) gin.H {
+	totalPages := lq.totalPages(total)
+
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+
+	var links []string
+	addLink := func(page int, rel string) {
+		u := *c.Request.URL
+		q := u.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("per_page", strconv.Itoa(lq.PerPage))
+		u.RawQuery = q.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, requestURL(c, &u), rel))
+	}
+	if totalPages > 0 {
+		addLink(1, "first")
+		if lq.Page > 1 {
+			addLink(min(lq.Page-1, totalPages), "prev")
+		}
+		if lq.Page < totalPages {
+			addLink(lq.Page+1, "next")
+		}
+		addLink(totalPages, "last")
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+
+	return gin.H{
+		"page":        lq.Page,
+		"per_page":    lq.PerPage,
+		"total":       total,
+		"total_pages": totalPages,
+	}
+}
+
+func requestURL(c *gin.Context, u *url.URL) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + u.RequestURI()
+}
+
+// LIKE kalıbında joker anlamı taşıyan karakterler
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern q'yu (küçük harfle) içeren değerleri bulan LIKE kalıbını
+// döner. Kullanıcının yazdığı % ve _ joker olarak yorumlanmaz; sorguda
+// LIKE ? ESCAPE '\' biçiminde kullanılmalıdır.
+func containsPattern(q string) string {
+	return "%" + likeEscaper.Replace(strings.ToLower(q)) + "%"
+}
+
+// Filtre parametresi yardımcıları. Parametre yoksa nil döner.
+
+func queryAmount(c *gin.Context, name string) (*money.Amount, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	a, err := money.Parse(value)
+	if err != nil || a < 0 {
+		return nil, fmt.Errorf("%s en fazla 2 ondalık basamaklı pozitif bir tutar olmalı", name)
+	}
+	return &a, nil
+}
+
+func queryUint(c *gin.Context, name string) (*uint, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%s geçerli bir ID olmalı", name)
+	}
+	id := uint(n)
+	return &id, nil
+}
+
+func queryBool(c *gin.Context, name string) (*bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s true veya false olmalı", name)
+	}
+	return &b, nil
+}
+
+// queryTime RFC3339 (2024-05-01T10:00:00Z) veya tarih (2024-05-01) kabul eder.
+// endOfDay true ise yalnızca tarih verildiğinde günün sonu kullanılır (to filtreleri için).
+func queryTime(c *gin.Context, name string, endOfDay bool) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("%s tarih (2006-01-02) veya RFC3339 formatında olmalı", name)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
+// filterDateRange from ve to parametrelerini column üzerinde aralık filtresi
+// olarak uygular.
+func filterDateRange(c *gin.Context, query *gorm.DB, column string) (*gorm.DB, error) {
+	from, err := queryTime(c, "from", false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := queryTime(c, "to", true)
+	if err != nil {
+		return nil, err
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, errors.New("from, to tarihinden sonra olamaz")
+	}
+
+	if from != nil {
+		query = query.Where(column+" >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where(column+" <= ?", *to)
+	}
+	return query, nil
+}
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"tradesman-api/config"
+	"tradesman-api/models"
+	"tradesman-api/money"
+
+	"github.com/gin-gonic/gin"
+)
+
+type productPage struct {
+	Products []struct {
+		Name  string       `json:"name"`
+		Price money.Amount `json:"price"`
+	} `json:"products"`
+	Pagination struct {
+		Page       int   `json:"page"`
+		PerPage    int   `json:"per_page"`
+		Total      int64 `json:"total"`
+		TotalPages int   `json:"total_pages"`
+	} `json:"pagination"`
+}
+
+func TestListProducts(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		_, shop, _ := testCatalog(t, 10)
+		// testCatalog'un ürünü 10.00; fiyatları 1.00–4.00 olan dört ürün daha
+		for i, name := range []string{"Simit", "Poğaça", "Açma", "Börek"} {
+			p := models.Product{ShopID: shop.ID, Name: name, Price: money.Amount(100 * (i + 1)), Currency: money.DefaultCurrency, Unit: models.UnitPiece, IsActive: true}
+			p.ApplyUnitDefaults()
+			if i == 3 {
+				p.Stock = 0
+			} else {
+				p.Stock = 5
+			}
+			if err := config.DB.Create(&p).Error; err != nil {
+				t.Fatalf("ürün oluşturulamadı: %v", err)
+			}
+		}
+
+		pc := &ProductController{}
+		r := gin.New()
+		r.GET("/products", pc.GetProducts)
+		get := func(query string) (*httptest.ResponseRecorder, productPage) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products"+query, nil))
+			var page productPage
+			if w.Code == http.StatusOK {
+				if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+					t.Fatal(err)
+				}
+			}
+			return w, page
+		}
+
+		w, page := get("?per_page=2&page=2&sort=-price")
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		if len(page.Products) != 2 || page.Products[0].Price != 300 || page.Products[1].Price != 200 {
+			t.Errorf("ikinci sayfa beklenen gibi değil: %s", w.Body)
+		}
+		if page.Pagination.Total != 5 || page.Pagination.TotalPages != 3 || page.Pagination.Page != 2 {
+			t.Errorf("sayfalama bilgisi beklenen gibi değil: %+v", page.Pagination)
+		}
+		if got := w.Header().Get("X-Total-Count"); got != "5" {
+			t.Errorf("X-Total-Count %q, beklenen 5", got)
+		}
+		link := w.Header().Get("Link")
+		for _, want := range []string{
+			`page=1&per_page=2&sort=-price>; rel="first"`,
+			`page=1&per_page=2&sort=-price>; rel="prev"`,
+			`page=3&per_page=2&sort=-price>; rel="next"`,
+			`page=3&per_page=2&sort=-price>; rel="last"`,
+		} {
+			if !strings.Contains(link, want) {
+				t.Errorf("Link başlığında %s yok: %s", want, link)
+			}
+		}
+
+		// Filtreler toplam sayıya da uygulanır
+		w, page = get("?min_price=2&max_price=4&in_stock=true&sort=name")
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		if page.Pagination.Total != 2 || len(page.Products) != 2 || page.Products[0].Name != "Açma" || page.Products[1].Name != "Poğaça" {
+			t.Errorf("filtrelenmiş liste beklenen gibi değil: %s", w.Body)
+		}
+
+		// Son sayfadan sonrası boş döner; önceki sayfa bağlantısı son sayfayı gösterir
+		w, page = get("?per_page=100&page=9223372036854775807")
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		if len(page.Products) != 0 || page.Pagination.Total != 5 {
+			t.Errorf("son sayfadan sonrası beklenen gibi değil: %s", w.Body)
+		}
+		if link := w.Header().Get("Link"); !strings.Contains(link, `page=1&per_page=100>; rel="prev"`) || strings.Contains(link, `rel="next"`) {
+			t.Errorf("Link başlığı beklenen gibi değil: %s", link)
+		}
+
+		for _, query := range []string{"?sort=password", "?sort=-user_id", "?page=0", "?page=abc", "?per_page=101", "?per_page=0", "?min_price=abc", "?min_price=5&max_price=1", "?in_stock=belki"} {
+			if w, _ := get(query); w.Code != http.StatusBadRequest {
+				t.Errorf("%s: yanıt kodu %d, beklenen 400", query, w.Code)
+			}
+		}
+	})
+}
+
+func TestListQueryQualifiesSortColumns(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		_, shop, product := testCatalog(t, 10)
+
+		for _, sort := range []string{"id", "-created_at", "name"} {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/products?sort="+sort, nil)
+			lq, err := parseListQuery(c, productListOptions)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Her iki tabloda da id, name ve created_at kolonları var
+			query := config.DB.Model(&models.Product{}).Joins("JOIN shops ON shops.id = products.shop_id").Where("shops.id = ?", shop.ID)
+			var products []models.Product
+			if _, err := findPage(c, query, lq, &products); err != nil {
+				t.Fatalf("sort=%s: %v", sort, err)
+			}
+			if len(products) != 1 || products[0].ID != product.ID {
+				t.Errorf("sort=%s: %d ürün döndü", sort, len(products))
+			}
+		}
+	})
+}
+
+func TestListShopsSearchEscapesWildcards(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		for i, name := range []string{"Kasap_Ali", "KasapXAli", "Manav %100"} {
+			owner := testUser(t, fmt.Sprintf("esnaf%d@example.com", i), models.RoleShop)
+			if err := config.DB.Create(&models.Shop{UserID: owner.ID, Name: name, IsActive: true}).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		sc := &ShopController{}
+		r := gin.New()
+		r.GET("/shops", sc.GetShops)
+
+		for q, want := range map[string]string{
+			"p_a":   "[Kasap_Ali]",
+			"%":     "[Manav %100]",
+			"kasap": "[Kasap_Ali KasapXAli]",
+		} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/shops?sort=id&q="+url.QueryEscape(q), nil))
+			var resp struct {
+				Shops []struct {
+					Name string `json:"name"`
+				} `json:"shops"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("q=%s: yanıt kodu %d: %s", q, w.Code, w.Body)
+			}
+			var got []string
+			for _, s := range resp.Shops {
+				got = append(got, s.Name)
+			}
+			if fmt.Sprint(got) != want {
+				t.Errorf("q=%s: %v, beklenen %s", q, got, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"tradesman-api/config"
+	"tradesman-api/events"
+	"tradesman-api/middleware"
+	"tradesman-api/models"
+	"tradesman-api/notify"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationController struct{}
+
+// Verilmeyen alanlar değişmez
+type NotificationPreferenceRequest struct {
+	Language *string `json:"language"` // tr, en
+	Email    *bool   `json:"email"`
+	SMS      *bool   `json:"sms"`
+	Push     *bool   `json:"push"`
+}
+
+type PushDeviceRequest struct {
+	Token    string              `json:"token" binding:"required,max=512"`
+	Platform models.PushPlatform `json:"platform" binding:"required"` // ios, android, web
+}
+
+// @Summary Bildirim Tercihleri
+// @Description Bildirim dilini ve açık kanalları (email, sms, push) getirir. Tercih kaydedilmemişse varsayılanlar döner: Türkçe, e-posta ve push açık, SMS kapalı.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /notifications/preferences [get]
+func (nc *NotificationController) GetPreferences(c *gin.Context) {
+	pref, err := notify.Preference(config.DB, middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Bildirim tercihleri getirilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"preferences": pref,
+		"languages":   models.NotificationLanguages,
+	})
+}
+
+// @Summary Bildirim Tercihlerini Güncelle
+// @Description Bildirim dilini ve kanalları günceller; verilmeyen alanlar değişmez. SMS için profilde telefon, push için kayıtlı cihaz gerekir.
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param preferences body NotificationPreferenceRequest true "Dil ve kanallar"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /notifications/preferences [put]
+func (nc *NotificationController) UpdatePreferences(c *gin.Context) {
+	var req NotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pref, err := notify.Preference(config.DB, middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Bildirim tercihleri getirilemedi"})
+		return
+	}
+
+	if req.Language != nil {
+		lang := strings.ToLower(strings.TrimSpace(*req.Language))
+		if !models.IsNotificationLanguage(lang) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz dil: " + *req.Language + " (" + strings.Join(models.NotificationLanguages, ", ") + ")"})
+			return
+		}
+		pref.Language = lang
+	}
+	if req.Email != nil {
+		pref.Email = *req.Email
+	}
+	if req.SMS != nil {
+		pref.SMS = *req.SMS
+	}
+	if req.Push != nil {
+		pref.Push = *req.Push
+	}
+
+	if err := config.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(&pref).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Bildirim tercihleri kaydedilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Bildirim tercihleri güncellendi",
+		"preferences": pref,
+	})
+}
+
+// @Summary Push Cihazları
+// @Description Kullanıcının push bildirimi alan cihazlarını listeler
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /notifications/devices [get]
+func (nc *NotificationController) GetDevices(c *gin.Context) {
+	var devices []models.PushDevice
+	if err := config.DB.Where("user_id = ?", middleware.GetUserID(c)).Order("id").Find(&devices).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Cihazlar getirilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"devices": devices,
+	})
+}
+
+// @Summary Push Cihazı Kaydet
+// @Description Mobil uygulamanın push token'ını kaydeder. Token başka bir kullanıcıya kayıtlıysa bu kullanıcıya geçer (aynı cihazda hesap değişikliği).
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param device body PushDeviceRequest true "Push token ve platform"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /notifications/devices [post]
+func (nc *NotificationController) RegisterDevice(c *gin.Context) {
+	var req PushDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	token := strings.TrimSpace(req.Token)
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token boş olamaz"})
+		return
+	}
+	if !req.Platform.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz platform: " + string(req.Platform) + " (ios, android, web)"})
+		return
+	}
+
+	device := models.PushDevice{
+		UserID:   middleware.GetUserID(c),
+		Token:    token,
+		Platform: req.Platform,
+	}
+	err := config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "updated_at"}),
+	}).Create(&device).Error
+	if err == nil {
+		err = config.DB.Where("token = ?", token).First(&device).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Cihaz kaydedilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Cihaz kaydedildi",
+		"device":  device,
+	})
+}
+
+// @Summary Push Cihazını Sil
+// @Description Cihaza push bildirimi gönderilmesini durdurur (ör. çıkış yaparken)
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Cihaz ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /notifications/devices/{id} [delete]
+func (nc *NotificationController) DeleteDevice(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz cihaz ID"})
+		return
+	}
+
+	result := config.DB.Where("user_id = ?", middleware.GetUserID(c)).Delete(&models.PushDevice{}, id)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Cihaz silinemedi"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cihaz bulunamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Cihaz silindi"})
+}
+
+// notifyOrder sipariş olayı için ilgili kullanıcılara bildirim gönderir: yeni
+// siparişte esnafa ve müşteriye, sipariş hazır olduğunda müşteriye. order
+// Shop ve OrderItems ile yüklenmiş olmalıdır.
+func notifyOrder(eventType string, order models.Order) {
+	data := notify.OrderData{
+		OrderID:      order.ID,
+		ShopName:     order.Shop.Name,
+		ShopAddress:  order.Shop.Address,
+		ShopPhone:    order.Shop.Phone,
+		CustomerName: order.User.Name,
+		Total:        order.TotalAmount.String() + " " + order.Currency,
+		ItemCount:    len(order.OrderItems),
+		Note:         order.Note,
+	}
+
+	switch {
+	case eventType == events.OrderCreated:
+		notify.Notify(order.Shop.UserID, notify.OrderReceived, data)
+		notify.Notify(order.UserID, notify.OrderPlaced, data)
+	case eventType == events.OrderStatusChanged && order.Status == models.OrderStatusReady:
+		notify.Notify(order.UserID, notify.OrderReady, data)
+	}
+}
+
+// notifyStockAlert stok uyarısını dükkan sahibine bildirir.
+func notifyStockAlert(ownerID uint, e StockAlertEvent) {
+	data := notify.StockAlertData{
+		ProductID:   e.Alert.ProductID,
+		ProductName: e.ProductName,
+		Stock:       models.FormatQuantity(e.Alert.Stock) + " " + string(e.Unit),
+		Threshold:   models.FormatQuantity(e.Alert.Threshold) + " " + string(e.Unit),
+		OutOfStock:  e.Alert.Kind == models.StockAlertOutOfStock,
+	}
+	if e.VariantName != "" {
+		data.ProductName += " (" + e.VariantName + ")"
+	}
+	notify.Notify(ownerID, notify.StockAlert, data)
+}
@@ -0,0 +1,237 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"tradesman-api/config"
+	"tradesman-api/events"
+	"tradesman-api/models"
+	"tradesman-api/notify"
+)
+
+// startNotifier Default bildirim servisini tüm kanalları giden kutusuna yazacak
+// şekilde kurar ve arka planda çalıştırır; test bitince servisi durdurur.
+func startNotifier(t *testing.T) {
+	t.Helper()
+	outbox := notify.Outbox{DB: config.DB}
+	service := notify.NewService(config.DB, map[models.NotificationChannel]notify.Channel{
+		models.ChannelEmail: outbox,
+		models.ChannelSMS:   outbox,
+		models.ChannelPush:  outbox,
+	})
+	previous := notify.Default
+	notify.Default = service
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		notify.Default = previous
+	})
+}
+
+// waitOutbox giden kutusunda want kadar mesaj birikene kadar bekler ve
+// mesajları kanal ve alıcıya göre sıralı döner.
+func waitOutbox(t *testing.T, want int) []models.OutboxMessage {
+	t.Helper()
+	var messages []models.OutboxMessage
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if err := config.DB.Order("channel, recipient").Find(&messages).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(messages) >= want {
+			break
+		}
+	}
+	if len(messages) != want {
+		t.Fatalf("giden kutusunda %d mesaj, beklenen %d: %+v", len(messages), want, messages)
+	}
+	return messages
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		customer, shop, _ := testCatalog(t, 10)
+
+		nc := &NotificationController{}
+		r := asUser(customer)
+		r.GET("/notifications/preferences", nc.GetPreferences)
+		r.PUT("/notifications/preferences", nc.UpdatePreferences)
+		r.GET("/notifications/devices", nc.GetDevices)
+		r.POST("/notifications/devices", nc.RegisterDevice)
+		r.DELETE("/notifications/devices/:id", nc.DeleteDevice)
+
+		var resp struct {
+			Preferences models.NotificationPreference `json:"preferences"`
+		}
+		get := func() models.NotificationPreference {
+			t.Helper()
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/notifications/preferences", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("yanıt kodu %d: %s", w.Code, w.Body)
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			return resp.Preferences
+		}
+		put := func(body string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/notifications/preferences", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+			return w
+		}
+
+		// Kayıt yoksa varsayılanlar döner
+		if pref := get(); pref.Language != "tr" || !pref.Email || pref.SMS || !pref.Push {
+			t.Errorf("varsayılan tercihler beklenen gibi değil: %+v", pref)
+		}
+
+		// Verilmeyen alanlar değişmez
+		if w := put(`{"language":" EN ","sms":true}`); w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d: %s", w.Code, w.Body)
+		}
+		if w := put(`{"push":false}`); w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d: %s", w.Code, w.Body)
+		}
+		if pref := get(); pref.Language != "en" || !pref.Email || !pref.SMS || pref.Push {
+			t.Errorf("güncellenen tercihler beklenen gibi değil: %+v", pref)
+		}
+		if w := put(`{"language":"de"}`); w.Code != http.StatusBadRequest {
+			t.Errorf("geçersiz dil: yanıt kodu %d, beklenen 400", w.Code)
+		}
+		if pref, _ := notify.Preference(config.DB, customer.ID); pref.Language != "en" {
+			t.Errorf("reddedilen istekten sonra dil %q", pref.Language)
+		}
+
+		// Aynı token başka kullanıcıya kayıtlıysa bu kullanıcıya geçer
+		config.DB.Create(&models.PushDevice{UserID: shop.UserID, Token: "cihaz-1", Platform: models.PlatformIOS})
+		w := postJSON(r, "/notifications/devices", PushDeviceRequest{Token: " cihaz-1 ", Platform: models.PlatformAndroid})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("yanıt kodu %d, beklenen 201: %s", w.Code, w.Body)
+		}
+		var device models.PushDevice
+		config.DB.Where("token = ?", "cihaz-1").First(&device)
+		if device.UserID != customer.ID || device.Platform != models.PlatformAndroid {
+			t.Errorf("cihaz kullanıcıya geçmedi: %+v", device)
+		}
+		if w := postJSON(r, "/notifications/devices", PushDeviceRequest{Token: "cihaz-2", Platform: "symbian"}); w.Code != http.StatusBadRequest {
+			t.Errorf("geçersiz platform: yanıt kodu %d, beklenen 400", w.Code)
+		}
+
+		other := models.PushDevice{UserID: shop.UserID, Token: "cihaz-3", Platform: models.PlatformWeb}
+		config.DB.Create(&other)
+		for id, want := range map[uint]int{other.ID: http.StatusNotFound, device.ID: http.StatusOK} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/notifications/devices/%d", id), nil))
+			if w.Code != want {
+				t.Errorf("cihaz %d silme: yanıt kodu %d, beklenen %d", id, w.Code, want)
+			}
+		}
+	})
+}
+
+func TestNotificationDelivery(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		customer, shop, _ := testCatalog(t, 10)
+		startNotifier(t)
+
+		now := time.Now()
+		phone := "+905321112233"
+		config.DB.Model(&models.User{}).Where("id IN ?", []uint{customer.ID, shop.UserID}).Update("email_verified_at", now)
+		config.DB.Model(&customer).Update("phone_e164", phone)
+		config.DB.Create(&models.NotificationPreference{UserID: customer.ID, Language: "en", Email: true, SMS: true, Push: true})
+		config.DB.Create(&models.PushDevice{UserID: customer.ID, Token: "cihaz-1", Platform: models.PlatformIOS})
+
+		order := models.Order{
+			ID:          42,
+			UserID:      customer.ID,
+			User:        customer,
+			Shop:        shop,
+			TotalAmount: 2550,
+			Currency:    "TRY",
+			Note:        "Kapıya bırakın",
+			OrderItems:  make([]models.OrderItem, 2),
+		}
+
+		// Bildirim kuyruğa alınır ve arka planda gönderilir
+		notifyOrder(events.OrderCreated, order)
+		messages := waitOutbox(t, 4)
+
+		type sent struct {
+			channel  models.NotificationChannel
+			to, lang string
+			subject  string
+			body     []string
+		}
+		want := []sent{
+			// Esnafın tercihi yok: Türkçe, e-posta açık; telefonu ve cihazı yok
+			{models.ChannelEmail, "esnaf@example.com", "tr", "Yeni sipariş #42", []string{"Müşteri tarafından yeni bir sipariş", "Ürün sayısı: 2", "Toplam: 25.50 TRY", "Not: Kapıya bırakın"}},
+			{models.ChannelEmail, "musteri@example.com", "en", "Your order has been received (#42)", []string{"Hello Müşteri,", "Items: 2"}},
+			{models.ChannelPush, "cihaz-1", "en", "Your order has been received (#42)", []string{"Fırın received your order (#42, 25.50 TRY)"}},
+			{models.ChannelSMS, phone, "en", "", []string{"Fırın received your order (#42, 25.50 TRY)"}},
+		}
+		for i, m := range messages {
+			w := want[i]
+			if m.Channel != w.channel || m.Recipient != w.to || m.Language != w.lang || m.Subject != w.subject {
+				t.Errorf("mesaj %d: %s → %s [%s] %q, beklenen %s → %s [%s] %q", i, m.Channel, m.Recipient, m.Language, m.Subject, w.channel, w.to, w.lang, w.subject)
+			}
+			for _, part := range w.body {
+				if !strings.Contains(m.Body, part) {
+					t.Errorf("mesaj %d gövdesinde %q yok:\n%s", i, part, m.Body)
+				}
+			}
+			if m.UserID == nil {
+				t.Errorf("mesaj %d kullanıcıya bağlanmamış", i)
+			}
+		}
+
+		// Kapalı kanallardan gönderilmez; hesap e-postaları tercihlere bakmaz
+		config.DB.Where("1 = 1").Delete(&models.OutboxMessage{})
+		config.DB.Model(&models.NotificationPreference{}).Where("user_id = ?", customer.ID).
+			Updates(map[string]interface{}{"email": false, "push": false})
+		notify.Notify(customer.ID, notify.OrderReady, notify.OrderData{OrderID: 42, ShopName: "Fırın", CustomerName: "Müşteri", Total: "25.50 TRY"})
+		notify.Transactional(customer.ID, notify.PasswordReset, notify.AccountData{Name: "Müşteri", Link: "https://example.com/sifirla", Token: "abc", ValidFor: time.Hour})
+		messages = waitOutbox(t, 2)
+		if m := messages[0]; m.Channel != models.ChannelEmail || m.Kind != string(notify.PasswordReset) ||
+			!strings.Contains(m.Body, "https://example.com/sifirla") || !strings.Contains(m.Body, "valid for 1 hour(s)") {
+			t.Errorf("şifre sıfırlama e-postası beklenen gibi değil: %+v", m)
+		}
+		if m := messages[1]; m.Channel != models.ChannelSMS || m.Body != "Your order is ready: Fırın #42" {
+			t.Errorf("sipariş hazır SMS'i beklenen gibi değil: %+v", m)
+		}
+	})
+}
+
+func TestNotificationUnverifiedEmail(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		customer, _, _ := testCatalog(t, 10)
+		startNotifier(t)
+		config.DB.Create(&models.PushDevice{UserID: customer.ID, Token: "cihaz-1", Platform: models.PlatformIOS})
+
+		// Doğrulanmamış adrese sipariş e-postası gitmez; push yine gider
+		notify.Notify(customer.ID, notify.OrderReady, notify.OrderData{OrderID: 42, ShopName: "Fırın", CustomerName: "Müşteri", Total: "25.50 TRY"})
+		if m := waitOutbox(t, 1)[0]; m.Channel != models.ChannelPush {
+			t.Errorf("doğrulanmamış adrese %s gönderildi: %+v", m.Channel, m)
+		}
+
+		// Doğrulama e-postası doğrulanmamış adrese gönderilir
+		notify.Transactional(customer.ID, notify.EmailVerification, notify.AccountData{Name: "Müşteri", Link: "https://example.com/dogrula", Token: "abc", ValidFor: 24 * time.Hour})
+		if m := waitOutbox(t, 2)[0]; m.Channel != models.ChannelEmail || m.Recipient != "musteri@example.com" || m.Kind != string(notify.EmailVerification) {
+			t.Errorf("doğrulama e-postası beklenen gibi değil: %+v", m)
+		}
+	})
+}
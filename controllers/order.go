@@ -1,17 +1,27 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 	"tradesman-api/config"
+	"tradesman-api/events"
+	"tradesman-api/inventory"
 	"tradesman-api/middleware"
 	"tradesman-api/models"
+	"tradesman-api/money"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderController struct{}
 
+var errOrderStatusChanged = errors.New("sipariş durumu değişmiş")
+
 type CreateOrderRequest struct {
 	ShopID uint        `json:"shop_id" binding:"required"`
 	Items  []OrderItem `json:"items" binding:"required,min=1"`
@@ -20,19 +30,36 @@ type CreateOrderRequest struct {
 
 type OrderItem struct {
 	ProductID uint `json:"product_id" binding:"required"`
-	Quantity  int  `json:"quantity" binding:"required,gt=0"`
+	// Ürünün birimi cinsinden; en az miktar ve artışa uymalı (ör. 0.5 kg)
+	Quantity float64 `json:"quantity" binding:"required,gt=0"`
+	// Ürünün aktif çeşidi varsa zorunlu
+	VariantID *uint `json:"variant_id"`
+	// Seçenek gruplarından seçilen seçenekler (ör. ekstra peynir)
+	OptionIDs []uint `json:"option_ids"`
+}
+
+type UpdateOrderStatusRequest struct {
+	Status models.OrderStatus `json:"status" binding:"required"`
+	Note   string             `json:"note"`
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
 }
 
 // @Summary Sipariş Oluştur
-// @Description Yeni sipariş oluşturur (sadece müşteriler)
+// @Description Yeni sipariş oluşturur (sadece müşteriler). Dükkan require_verified_customers ayarını açtıysa müşterinin e-posta adresi veya telefon numarası doğrulanmış olmalıdır.
 // @Tags Orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param Idempotency-Key header string false "Tekrar denemelerde aynı siparişin iki kez oluşturulmasını önleyen benzersiz anahtar"
 // @Param order body CreateOrderRequest true "Sipariş bilgileri"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
 // @Router /orders [post]
 func (oc *OrderController) CreateOrder(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -62,16 +89,30 @@ func (oc *OrderController) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	if shop.RequireVerifiedCustomers {
+		var customer models.User
+		if err := config.DB.Select("id", "email_verified_at", "phone_e164").First(&customer, userID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcı bulunamadı"})
+			return
+		}
+		if !customer.Verified() {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Bu dükkan yalnızca e-posta adresi veya telefon numarası doğrulanmış müşterilerden sipariş kabul ediyor"})
+			return
+		}
+	}
+
 	// Transaction başlat
 	tx := config.DB.Begin()
 
-	var totalAmount float64 = 0
+	var totalAmount money.Amount
+	var currency string
 	var orderItems []models.OrderItem
+	var movements []models.StockMovement
 
 	// Her ürün için kontrol yap
 	for _, item := range req.Items {
 		var product models.Product
-		if err := tx.First(&product, item.ProductID).Error; err != nil {
+		if err := lockForUpdate(tx).First(&product, item.ProductID).Error; err != nil {
 			tx.Rollback()
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Ürün bulunamadı: " + strconv.Itoa(int(item.ProductID))})
 			return
@@ -89,29 +130,71 @@ func (oc *OrderController) CreateOrder(c *gin.Context) {
 			return
 		}
 
-		if product.Stock < item.Quantity {
+		// Bir siparişin toplamı tek para biriminde olmalı
+		if currency == "" {
+			currency = product.Currency
+		} else if product.Currency != currency {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Siparişteki ürünler farklı para birimlerinde"})
+			return
+		}
+
+		if err := product.ValidateQuantity(item.Quantity); err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		quantity := models.RoundQuantity(item.Quantity)
+
+		selection, status, err := selectOrderItem(tx, product, item)
+		if err != nil {
 			tx.Rollback()
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Yetersiz stok. Mevcut: " + strconv.Itoa(product.Stock) + ", İstenen: " + strconv.Itoa(item.Quantity),
-			})
+			c.JSON(status, gin.H{"error": err.Error()})
 			return
 		}
 
-		// Stok güncelle
-		product.Stock -= item.Quantity
-		if err := tx.Save(&product).Error; err != nil {
+		// Stoğu tek sorguda, yalnızca yeterliyse düşür; eşzamanlı siparişler fazla satış yapamaz.
+		// Çeşidi olan ürünlerde stok çeşitten düşülür.
+		movement := models.StockMovement{
+			ShopID:    product.ShopID,
+			ProductID: product.ID,
+			Type:      models.StockMovementSale,
+			Quantity:  -quantity,
+			ActorID:   &userID,
+		}
+		if selection.Variant != nil {
+			movement.VariantID = &selection.Variant.ID
+		}
+		if err := inventory.Move(tx, &movement); err != nil {
+			if errors.Is(err, inventory.ErrInsufficientStock) {
+				available, _ := inventory.Balance(tx, product.ID, movement.VariantID)
+				tx.Rollback()
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "Yetersiz stok. Mevcut: " + models.FormatQuantity(available) + " " + string(product.Unit) +
+						", İstenen: " + models.FormatQuantity(quantity) + " " + string(product.Unit),
+				})
+				return
+			}
 			tx.Rollback()
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Stok güncellenemedi"})
 			return
 		}
+		movements = append(movements, movement)
 
 		orderItem := models.OrderItem{
 			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     product.Price, // Sipariş anındaki fiyat
+			Quantity:  quantity,
+			Unit:      product.Unit,
+			Price:     selection.Price, // Sipariş anındaki fiyat, seçenekler dahil
+			Options:   selection.Options,
+		}
+		if selection.Variant != nil {
+			orderItem.VariantID = &selection.Variant.ID
+			orderItem.VariantName = selection.Variant.Name
+			orderItem.SKU = selection.Variant.SKU
 		}
 		orderItems = append(orderItems, orderItem)
-		totalAmount += product.Price * float64(item.Quantity)
+		totalAmount += orderItem.Total()
 	}
 
 	// Order oluştur
@@ -119,6 +202,7 @@ func (oc *OrderController) CreateOrder(c *gin.Context) {
 		UserID:      userID,
 		ShopID:      req.ShopID,
 		TotalAmount: totalAmount,
+		Currency:    currency,
 		Status:      models.OrderStatusPending,
 		Note:        req.Note,
 	}
@@ -139,11 +223,40 @@ func (oc *OrderController) CreateOrder(c *gin.Context) {
 		}
 	}
 
+	// Stok hareketleri sipariş numarasıyla deftere yazılır
+	for i := range movements {
+		movements[i].OrderID = &order.ID
+	}
+	if err := tx.Create(&movements).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Stok hareketleri kaydedilemedi"})
+		return
+	}
+
+	// Durum geçmişine ilk kayıt
+	history := models.OrderStatusHistory{
+		OrderID:     order.ID,
+		ToStatus:    models.OrderStatusPending,
+		ChangedByID: userID,
+	}
+	if err := tx.Create(&history).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Sipariş geçmişi oluşturulamadı"})
+		return
+	}
+
 	// Transaction commit
-	tx.Commit()
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Sipariş kaydedilemedi, lütfen tekrar deneyin"})
+		return
+	}
 
 	// Order'ı ilişkilerle birlikte getir
-	config.DB.Preload("Shop").Preload("OrderItems.Product").First(&order, order.ID)
+	config.DB.Preload("Shop").Preload("OrderItems.Product").Preload("OrderItems.Options").First(&order, order.ID)
+	publishOrderEvent(events.OrderCreated, order.ID, "")
+	for _, m := range movements {
+		publishStockAlerts(m.Alert)
+	}
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Sipariş başarıyla oluşturuldu",
@@ -151,22 +264,49 @@ func (oc *OrderController) CreateOrder(c *gin.Context) {
 	})
 }
 
+var orderListOptions = listOptions{
+	table: "orders",
+	sorts: map[string]string{
+		"id":           "id",
+		"created_at":   "created_at",
+		"total_amount": "total_amount",
+		"status":       "status",
+	},
+	defaultSort: "-id",
+}
+
 // @Summary Kullanıcının Siparişlerini Listele
-// @Description Mevcut kullanıcının siparişlerini listeler
+// @Description Mevcut kullanıcının siparişlerini sayfalı olarak listeler
 // @Tags Orders
 // @Produce json
 // @Security BearerAuth
+// @Param page query int false "Sayfa numarası (varsayılan 1)"
+// @Param per_page query int false "Sayfa başına kayıt (varsayılan 20, en fazla 100)"
+// @Param sort query string false "Sıralama: id, created_at, total_amount, status (azalan için başına -, varsayılan -id)"
+// @Param status query string false "Durum filtresi, virgülle birden fazla verilebilir (ör. pending,confirmed)"
+// @Param shop_id query int false "Esnaf filtresi (müşteri ve admin)"
+// @Param from query string false "Bu tarihten sonra oluşturulanlar (2006-01-02 veya RFC3339)"
+// @Param to query string false "Bu tarihe kadar oluşturulanlar (2006-01-02 veya RFC3339)"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Router /orders [get]
 func (oc *OrderController) GetMyOrders(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	userRole := middleware.GetUserRole(c)
 
-	var orders []models.Order
+	lq, err := parseListQuery(c, orderListOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := config.DB.Model(&models.Order{})
+	var preloads []string
 
 	if userRole == models.RoleCustomer {
 		// Müşteriler sadece kendi siparişlerini görebilir
-		config.DB.Preload("Shop").Preload("OrderItems.Product").Where("user_id = ?", userID).Find(&orders)
+		query = query.Where("user_id = ?", userID)
+		preloads = []string{"Shop", "OrderItems.Product", "OrderItems.Options"}
 	} else if userRole == models.RoleShop {
 		// Esnaflar sadece kendi dükkanlarına gelen siparişleri görebilir
 		var shop models.Shop
@@ -174,14 +314,53 @@ func (oc *OrderController) GetMyOrders(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Dükkan bulunamadı"})
 			return
 		}
-		config.DB.Preload("User").Preload("OrderItems.Product").Where("shop_id = ?", shop.ID).Find(&orders)
+		query = query.Where("shop_id = ?", shop.ID)
+		preloads = []string{"User", "OrderItems.Product", "OrderItems.Options"}
 	} else {
 		// Admin tüm siparişleri görebilir
-		config.DB.Preload("User").Preload("Shop").Preload("OrderItems.Product").Find(&orders)
+		preloads = []string{"User", "Shop", "OrderItems.Product", "OrderItems.Options"}
+	}
+
+	if status := c.Query("status"); status != "" {
+		var statuses []models.OrderStatus
+		for _, s := range strings.Split(status, ",") {
+			st := models.OrderStatus(strings.TrimSpace(s))
+			if !st.IsValid() {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz sipariş durumu: " + string(st)})
+				return
+			}
+			statuses = append(statuses, st)
+		}
+		query = query.Where("status IN ?", statuses)
+	}
+
+	if userRole != models.RoleShop {
+		shopID, err := queryUint(c, "shop_id")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if shopID != nil {
+			query = query.Where("shop_id = ?", *shopID)
+		}
+	}
+
+	query, err = filterDateRange(c, query, "created_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var orders []models.Order
+	pagination, err := findPage(c, query, lq, &orders, preloads...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Siparişler getirilemedi"})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"orders": orders,
+		"orders":     orders,
+		"pagination": pagination,
 	})
 }
 
@@ -192,62 +371,119 @@ func (oc *OrderController) GetMyOrders(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path int true "Sipariş ID"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /orders/{id} [get]
 func (oc *OrderController) GetOrder(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	userRole := middleware.GetUserRole(c)
-	orderID := c.Param("id")
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz sipariş ID"})
+		return
+	}
 
 	var order models.Order
-	if err := config.DB.Preload("User").Preload("Shop").Preload("OrderItems.Product").First(&order, orderID).Error; err != nil {
+	if err := config.DB.Preload("User").Preload("Shop").Preload("OrderItems.Product").Preload("OrderItems.Options").First(&order, orderID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Sipariş bulunamadı"})
 		return
 	}
 
 	// Yetki kontrolü
-	canAccess := false
-	if userRole == models.RoleAdmin {
-		canAccess = true
-	} else if userRole == models.RoleCustomer && order.UserID == userID {
-		canAccess = true
-	} else if userRole == models.RoleShop {
-		var shop models.Shop
-		if err := config.DB.Where("user_id = ?", userID).First(&shop).Error; err == nil {
-			if order.ShopID == shop.ID {
-				canAccess = true
-			}
-		}
+	if !oc.canAccessOrder(userID, userRole, order) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu siparişi görme yetkiniz yok"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order":         order,
+		"next_statuses": order.Status.NextStatuses(),
+	})
+}
+
+// @Summary Sipariş Durum Geçmişi
+// @Description Siparişin durum değişikliklerini kim tarafından ve ne zaman yapıldığıyla birlikte kronolojik sırada listeler
+// @Tags Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Sipariş ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /orders/{id}/history [get]
+func (oc *OrderController) GetOrderHistory(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz sipariş ID"})
+		return
+	}
+
+	var order models.Order
+	if err := config.DB.First(&order, orderID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sipariş bulunamadı"})
+		return
 	}
 
-	if !canAccess {
+	if !oc.canAccessOrder(userID, userRole, order) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Bu siparişi görme yetkiniz yok"})
 		return
 	}
 
+	var entries []models.OrderStatusHistory
+	if err := config.DB.Preload("ChangedBy").Where("order_id = ?", order.ID).Order("created_at, id").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Sipariş geçmişi getirilemedi"})
+		return
+	}
+
+	history := make([]gin.H, 0, len(entries))
+	for _, entry := range entries {
+		history = append(history, gin.H{
+			"id":          entry.ID,
+			"from_status": entry.FromStatus,
+			"to_status":   entry.ToStatus,
+			"note":        entry.Note,
+			"changed_by": gin.H{
+				"id":   entry.ChangedBy.ID,
+				"name": entry.ChangedBy.Name,
+				"role": entry.ChangedBy.Role,
+			},
+			"created_at": entry.CreatedAt,
+		})
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"order": order,
+		"order_id": order.ID,
+		"status":   order.Status,
+		"history":  history,
 	})
 }
 
 // @Summary Sipariş Durumu Güncelle
-// @Description Sipariş durumunu günceller (sadece esnaflar)
+// @Description Sipariş durumunu günceller (sadece esnaflar). İzin verilen geçişler: pending→confirmed→preparing→ready→delivered; teslim edilmemiş siparişler iptal edilebilir. İptalde note (neden) zorunludur ve stoklar iade edilir.
 // @Tags Orders
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Sipariş ID"
-// @Param status body map[string]string true "Yeni durum"
+// @Param status body UpdateOrderStatusRequest true "Yeni durum ve isteğe bağlı not"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Router /orders/{id}/status [put]
 func (oc *OrderController) UpdateOrderStatus(c *gin.Context) {
 	userID := middleware.GetUserID(c)
 	userRole := middleware.GetUserRole(c)
-	orderID := c.Param("id")
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz sipariş ID"})
+		return
+	}
 
 	// Sadece esnaflar sipariş durumu güncelleyebilir
 	if userRole != models.RoleShop {
@@ -267,45 +503,216 @@ func (oc *OrderController) UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Status string `json:"status" binding:"required"`
-	}
+	var req UpdateOrderStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Geçerli durum kontrolü
-	validStatuses := []models.OrderStatus{
-		models.OrderStatusPending,
-		models.OrderStatusConfirmed,
-		models.OrderStatusPreparing,
-		models.OrderStatusReady,
-		models.OrderStatusDelivered,
-		models.OrderStatusCancelled,
-	}
-
-	isValidStatus := false
-	for _, status := range validStatuses {
-		if string(status) == req.Status {
-			isValidStatus = true
-			break
-		}
+	if !req.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz sipariş durumu"})
+		return
 	}
 
-	if !isValidStatus {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz sipariş durumu"})
+	// Durum geçişi kontrolü
+	if !order.Status.CanTransitionTo(req.Status) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":         "Sipariş " + string(order.Status) + " durumundan " + string(req.Status) + " durumuna geçemez",
+			"next_statuses": order.Status.NextStatuses(),
+		})
 		return
 	}
 
-	order.Status = models.OrderStatus(req.Status)
-	if err := config.DB.Save(&order).Error; err != nil {
+	// İptalde neden zorunlu, stoklar iade edilir
+	if req.Status == models.OrderStatusCancelled && strings.TrimSpace(req.Note) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "İptal için neden (note) zorunlu"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		return oc.changeOrderStatus(tx, order, req.Status, userID, strings.TrimSpace(req.Note))
+	})
+	if errors.Is(err, errOrderStatusChanged) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Sipariş durumu başka bir işlem tarafından değiştirildi, tekrar deneyin"})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Sipariş durumu güncellenemedi"})
 		return
 	}
+	publishOrderEvent(events.OrderStatusChanged, order.ID, order.Status)
+
+	config.DB.Preload("Shop").First(&order, order.ID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Sipariş durumu güncellendi",
 		"order":   order,
 	})
 }
+
+// @Summary Siparişi İptal Et
+// @Description Müşteri, esnaf hazırlamaya başlamadan (pending/confirmed) siparişini iptal eder. Sipariş kalemlerinin stokları iade edilir.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Sipariş ID"
+// @Param cancel body CancelOrderRequest true "İptal nedeni"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /orders/{id}/cancel [post]
+func (oc *OrderController) CancelOrder(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz sipariş ID"})
+		return
+	}
+
+	var req CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "İptal nedeni zorunlu"})
+		return
+	}
+
+	var order models.Order
+	if err := config.DB.First(&order, orderID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sipariş bulunamadı"})
+		return
+	}
+
+	// Sadece siparişin sahibi iptal edebilir
+	if order.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu siparişi iptal etme yetkiniz yok"})
+		return
+	}
+
+	if order.Status == models.OrderStatusCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "Sipariş zaten iptal edilmiş"})
+		return
+	}
+
+	if !order.Status.CustomerCancellable() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Sipariş hazırlanmaya başladığı için artık iptal edilemez"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		return oc.changeOrderStatus(tx, order, models.OrderStatusCancelled, userID, reason)
+	})
+	if errors.Is(err, errOrderStatusChanged) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Sipariş durumu başka bir işlem tarafından değiştirildi, tekrar deneyin"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Sipariş iptal edilemedi"})
+		return
+	}
+	publishOrderEvent(events.OrderStatusChanged, order.ID, order.Status)
+
+	config.DB.Preload("Shop").Preload("OrderItems.Product").Preload("OrderItems.Options").First(&order, order.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Sipariş iptal edildi",
+		"order":   order,
+	})
+}
+
+// lockForUpdate PostgreSQL'de okunan satırları transaction sonuna kadar kilitler.
+// SQLite'ta yazma transaction'ları zaten sıralı çalıştığı için (BEGIN IMMEDIATE) gerekmez.
+func lockForUpdate(tx *gorm.DB) *gorm.DB {
+	if tx.Dialector.Name() == "postgres" {
+		return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	return tx
+}
+
+// changeOrderStatus siparişi, durumu hâlâ order.Status ise to durumuna geçirir ve
+// geçmişe yazar. İptalde iptal bilgilerini doldurur ve tüm kalemlerin stoklarını
+// stok defterine iade hareketi yazarak geri ekler.
+// tx içinde çağrılmalıdır.
+func (oc *OrderController) changeOrderStatus(tx *gorm.DB, order models.Order, to models.OrderStatus, actorID uint, note string) error {
+	updates := map[string]interface{}{"status": to}
+	if to == models.OrderStatusCancelled {
+		updates["cancellation_reason"] = note
+		updates["cancelled_at"] = time.Now()
+		updates["cancelled_by_id"] = actorID
+	}
+
+	// Eşzamanlı güncellemelere karşı yalnızca durum değişmediyse güncelle
+	result := tx.Model(&models.Order{}).
+		Where("id = ? AND status = ?", order.ID, order.Status).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errOrderStatusChanged
+	}
+
+	history := models.OrderStatusHistory{
+		OrderID:     order.ID,
+		FromStatus:  order.Status,
+		ToStatus:    to,
+		ChangedByID: actorID,
+		Note:        note,
+	}
+	if err := tx.Create(&history).Error; err != nil {
+		return err
+	}
+
+	if to != models.OrderStatusCancelled {
+		return nil
+	}
+
+	// Stok iadesi (silinmiş ürünler dahil)
+	var items []models.OrderItem
+	if err := tx.Where("order_id = ?", order.ID).Find(&items).Error; err != nil {
+		return err
+	}
+	for _, item := range items {
+		movement := models.StockMovement{
+			ShopID:    order.ShopID,
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Type:      models.StockMovementCancellation,
+			Quantity:  item.Quantity,
+			ActorID:   &actorID,
+			OrderID:   &order.ID,
+			Reason:    note,
+		}
+		// Çeşit silinmişse iade edilecek stok kalmamıştır
+		if err := inventory.Record(tx, &movement); err != nil && !errors.Is(err, inventory.ErrItemNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// canAccessOrder admin, siparişin sahibi müşteri veya siparişin geldiği dükkanın sahibi için true döner.
+func (oc *OrderController) canAccessOrder(userID uint, userRole models.UserRole, order models.Order) bool {
+	switch userRole {
+	case models.RoleAdmin:
+		return true
+	case models.RoleCustomer:
+		return order.UserID == userID
+	case models.RoleShop:
+		var shop models.Shop
+		if err := config.DB.Where("user_id = ?", userID).First(&shop).Error; err != nil {
+			return false
+		}
+		return order.ShopID == shop.ID
+	}
+	return false
+}
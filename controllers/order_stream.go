@@ -0,0 +1,287 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"tradesman-api/config"
+	"tradesman-api/events"
+	"tradesman-api/middleware"
+	"tradesman-api/models"
+	"tradesman-api/webhooks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	streamHeartbeat  = 25 * time.Second
+	streamRetry      = 3000 // Yeniden bağlanma beklemesi (ms), EventSource retry alanı
+	streamWriteLimit = 10 * time.Second
+	// Replay eksikse gönderilen olay; istemci siparişleri GET /orders ile yeniden yüklemeli
+	streamReset = "stream.reset"
+)
+
+// WebSocket bağlantıları CORS kapsamında olmadığından tarayıcılardan gelenler
+// CORS_ORIGINS listesine göre süzülür. Origin başlığı göndermeyen tarayıcı dışı
+// istemciler kabul edilir; listede "*" varsa tüm originlere izin verilir.
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: allowedStreamOrigin,
+}
+
+func allowedStreamOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, allowed := range config.App.CORSOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderEvent akışta gönderilen sipariş olayının verisi.
+type OrderEvent struct {
+	Order      models.Order       `json:"order"`
+	FromStatus models.OrderStatus `json:"from_status,omitempty"` // Durum değişikliğinde önceki durum
+}
+
+// publishOrderEvent siparişi ilişkileriyle yükleyip olay veri yoluna yayınlar,
+// dükkanın webhook'ları için kuyruğa yazar ve ilgili kullanıcılara bildirim
+// gönderir. Transaction commit edildikten sonra çağrılmalıdır.
+func publishOrderEvent(eventType string, orderID uint, from models.OrderStatus) {
+	var order models.Order
+	err := config.DB.Preload("User").Preload("Shop").
+		Preload("OrderItems.Product").Preload("OrderItems.Options").
+		First(&order, orderID).Error
+	if err != nil {
+		log.Printf("sipariş olayı yayınlanamadı (sipariş %d): %v", orderID, err)
+		return
+	}
+	e := events.Default.Publish(events.Event{
+		Type:   eventType,
+		ShopID: order.ShopID,
+		UserID: order.UserID,
+		Data:   OrderEvent{Order: order, FromStatus: from},
+	})
+	if _, err := webhooks.Enqueue(config.DB, e); err != nil {
+		log.Printf("sipariş olayı webhook kuyruğuna yazılamadı (sipariş %d): %v", orderID, err)
+	}
+	notifyOrder(eventType, order)
+}
+
+// orderEventFilter kullanıcının göreceği olayları seçer: esnaf dükkanının
+// tüm sipariş olaylarını, müşteri kendi siparişlerinin durum değişikliklerini,
+// admin tüm olayları görür.
+func orderEventFilter(c *gin.Context) (events.Filter, error) {
+	userID := middleware.GetUserID(c)
+	switch middleware.GetUserRole(c) {
+	case models.RoleAdmin:
+		return func(events.Event) bool { return true }, nil
+	case models.RoleShop:
+		var shop models.Shop
+		if err := config.DB.Select("id").Where("user_id = ?", userID).First(&shop).Error; err != nil {
+			return nil, err
+		}
+		return func(e events.Event) bool { return e.ShopID == shop.ID }, nil
+	default:
+		return func(e events.Event) bool {
+			return e.UserID == userID && e.Type == events.OrderStatusChanged
+		}, nil
+	}
+}
+
+// lastEventID yeniden bağlanan istemcinin aldığı son olay ID'sini okur.
+// EventSource bunu Last-Event-ID başlığında gönderir; WebSocket istemcileri
+// last_event_id sorgu parametresini kullanır.
+func lastEventID(c *gin.Context) uint64 {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	id, _ := strconv.ParseUint(value, 10, 64)
+	return id
+}
+
+// subscribeOrders akış uçlarının ortak başlangıcı: filtreyi kurar ve abone olur.
+// Hata durumunda yanıtı yazar ve nil döner.
+func subscribeOrders(c *gin.Context) (*events.Subscription, events.Replay) {
+	filter, err := orderEventFilter(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dükkanınız bulunamadı"})
+		return nil, events.Replay{}
+	}
+	sub, replay := events.Default.Subscribe(filter, lastEventID(c))
+	return sub, replay
+}
+
+// tokenExpiry akışın kapanacağı zamanı döner; token süresi dolunca istemci
+// yenilenmiş tokenla yeniden bağlanır.
+func tokenExpiry(c *gin.Context) <-chan time.Time {
+	claims := middleware.GetClaims(c)
+	if claims.ExpiresAt == nil {
+		return nil
+	}
+	return time.After(time.Until(claims.ExpiresAt.Time))
+}
+
+// @Summary Sipariş Akışı (SSE)
+// @Description Server-Sent Events ile sipariş olaylarını canlı gönderir. Esnaflar dükkanlarına gelen yeni siparişleri (order.created), durum değişikliklerini (order.status_changed) ve stok uyarılarını (stock.alert), müşteriler kendi siparişlerinin durum değişikliklerini alır. Yeniden bağlanırken Last-Event-ID başlığı (veya last_event_id parametresi) ile kaçırılan olaylar tekrar gönderilir; kaçırılanlar artık bellekte değilse önce stream.reset olayı gelir ve siparişler yeniden yüklenmelidir. EventSource başlık gönderemediği için token access_token parametresiyle de verilebilir. Bağlantı token süresi dolunca kapanır.
+// @Tags Orders
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param access_token query string false "Authorization başlığı yerine JWT"
+// @Param Last-Event-ID header string false "Alınan son olay ID'si"
+// @Param last_event_id query string false "Alınan son olay ID'si (başlık yerine)"
+// @Success 200 {string} string "text/event-stream"
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /orders/stream [get]
+func (oc *OrderController) StreamOrders(c *gin.Context) {
+	sub, replay := subscribeOrders(c)
+	if sub == nil {
+		return
+	}
+	defer sub.Close()
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // nginx tamponlamasın
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+	if !replay.Complete {
+		writeSSE(w, events.Event{ID: replay.LastID, Type: streamReset, Data: gin.H{}})
+	}
+	for _, e := range replay.Events {
+		writeSSE(w, e)
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	expired := tokenExpiry(c)
+
+	for {
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				// Olaylara yetişilemedi; istemci Last-Event-ID ile yeniden bağlanır
+				return
+			}
+			writeSSE(w, e)
+		case <-heartbeat.C:
+			io.WriteString(w, ": ping\n\n")
+		case <-expired:
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+		w.Flush()
+	}
+}
+
+func writeSSE(w io.Writer, e events.Event) {
+	data, err := json.Marshal(e.Data)
+	if err != nil {
+		log.Printf("olay kodlanamadı (%d): %v", e.ID, err)
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+}
+
+// @Summary Sipariş Akışı (WebSocket)
+// @Description Sipariş olaylarını WebSocket üzerinden JSON mesajlar ({id, type, data, created_at}) olarak gönderir. Olaylar ve yetki SSE akışıyla aynıdır. Token access_token parametresiyle, kaçırılan olaylar için son olay ID'si last_event_id parametresiyle verilir. Tarayıcı bağlantıları yalnızca CORS_ORIGINS listesindeki originlerden kabul edilir. Bağlantı token süresi dolunca kapanır.
+// @Tags Orders
+// @Security BearerAuth
+// @Param access_token query string false "Authorization başlığı yerine JWT"
+// @Param last_event_id query string false "Alınan son olay ID'si"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /orders/ws [get]
+func (oc *OrderController) OrdersWebSocket(c *gin.Context) {
+	sub, replay := subscribeOrders(c)
+	if sub == nil {
+		return
+	}
+	defer sub.Close()
+
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrader hata yanıtını kendisi yazar
+		return
+	}
+	defer conn.Close()
+
+	// İstemci mesajları kullanılmaz; okuma kapanışı ve pong'ları işlemek için gerekir
+	closed := make(chan struct{})
+	conn.SetReadDeadline(time.Now().Add(2 * streamHeartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * streamHeartbeat))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(e events.Event) error {
+		conn.SetWriteDeadline(time.Now().Add(streamWriteLimit))
+		return conn.WriteJSON(e)
+	}
+
+	if !replay.Complete {
+		if err := send(events.Event{ID: replay.LastID, Type: streamReset, Data: gin.H{}, CreatedAt: time.Now()}); err != nil {
+			return
+		}
+	}
+	for _, e := range replay.Events {
+		if err := send(e); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	expired := tokenExpiry(c)
+
+	closeWith := func(code int, text string) {
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(time.Second))
+	}
+
+	for {
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				closeWith(websocket.CloseTryAgainLater, "olaylara yetişilemedi, yeniden bağlanın")
+				return
+			}
+			if err := send(e); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteLimit)); err != nil {
+				return
+			}
+		case <-expired:
+			closeWith(websocket.ClosePolicyViolation, "token süresi doldu")
+			return
+		case <-closed:
+			return
+		}
+	}
+}
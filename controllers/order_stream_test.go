@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"tradesman-api/config"
+	"tradesman-api/models"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestOrdersWebSocketOrigin(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		config.App.CORSOrigins = []string{"https://panel.example.com"}
+
+		oc := &OrderController{}
+		r := asUser(models.User{ID: 1, Role: models.RoleCustomer})
+		r.GET("/orders/ws", oc.OrdersWebSocket)
+		server := httptest.NewServer(r)
+		defer server.Close()
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/orders/ws"
+
+		cases := []struct {
+			origin string
+			status int
+		}{
+			{"https://panel.example.com", http.StatusSwitchingProtocols},
+			{"https://saldirgan.example.com", http.StatusForbidden},
+			{"", http.StatusSwitchingProtocols}, // Tarayıcı dışı istemci
+		}
+		for _, tc := range cases {
+			header := http.Header{}
+			if tc.origin != "" {
+				header.Set("Origin", tc.origin)
+			}
+			conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+			if conn != nil {
+				conn.Close()
+			}
+			if resp == nil {
+				t.Fatalf("%q: bağlantı kurulamadı: %v", tc.origin, err)
+			}
+			if resp.StatusCode != tc.status {
+				t.Errorf("%q: yanıt kodu %d, beklenen %d", tc.origin, resp.StatusCode, tc.status)
+			}
+		}
+	})
+}
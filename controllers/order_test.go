@@ -0,0 +1,435 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"tradesman-api/config"
+	"tradesman-api/inventory"
+	"tradesman-api/models"
+	"tradesman-api/money"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testCatalog bir esnaf, dükkanı, müşteri ve açılış stoğu defterde kayıtlı bir
+// ürün oluşturur.
+func testCatalog(t *testing.T, stock float64) (models.User, models.Shop, models.Product) {
+	t.Helper()
+
+	owner := models.User{Name: "Esnaf", Email: "esnaf@example.com", Password: "x", Role: models.RoleShop}
+	customer := models.User{Name: "Müşteri", Email: "musteri@example.com", Password: "x", Role: models.RoleCustomer}
+	for _, u := range []*models.User{&owner, &customer} {
+		if err := config.DB.Create(u).Error; err != nil {
+			t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+		}
+	}
+
+	shop := models.Shop{UserID: owner.ID, Name: "Fırın", IsActive: true}
+	if err := config.DB.Create(&shop).Error; err != nil {
+		t.Fatalf("dükkan oluşturulamadı: %v", err)
+	}
+
+	product := models.Product{
+		ShopID:   shop.ID,
+		Name:     "Ekmek",
+		Price:    money.Amount(1000),
+		Currency: money.DefaultCurrency,
+		Unit:     models.UnitPiece,
+		IsActive: true,
+	}
+	product.ApplyUnitDefaults()
+	if err := config.DB.Create(&product).Error; err != nil {
+		t.Fatalf("ürün oluşturulamadı: %v", err)
+	}
+
+	opening := models.StockMovement{
+		ShopID:    shop.ID,
+		ProductID: product.ID,
+		Type:      models.StockMovementAdjustment,
+		Quantity:  stock,
+		ActorID:   &owner.ID,
+		Reason:    "Açılış stoğu",
+	}
+	if err := inventory.Record(config.DB, &opening); err != nil {
+		t.Fatalf("açılış stoğu yazılamadı: %v", err)
+	}
+
+	return customer, shop, product
+}
+
+// asUser istekleri JWT olmadan verilen kullanıcı adına işleyen router kurar.
+func asUser(user models.User) *gin.Engine {
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("user_id", user.ID)
+		c.Set("user_email", user.Email)
+		c.Set("user_role", user.Role)
+		c.Next()
+	})
+	return r
+}
+
+func TestCreateOrderConcurrentStock(t *testing.T) {
+	forEachDatabase(t, testCreateOrderConcurrentStock)
+}
+
+func testCreateOrderConcurrentStock(t *testing.T) {
+	const (
+		initialStock = 80
+		orders       = 300
+	)
+	customer, shop, product := testCatalog(t, initialStock)
+
+	oc := &OrderController{}
+	r := asUser(customer)
+	r.POST("/orders", oc.CreateOrder)
+
+	body, _ := json.Marshal(CreateOrderRequest{
+		ShopID: shop.ID,
+		Items:  []OrderItem{{ProductID: product.ID, Quantity: 1}},
+	})
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		statuses = map[int]int{}
+	)
+	start := make(chan struct{})
+	for i := 0; i < orders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			mu.Lock()
+			statuses[w.Code]++
+			mu.Unlock()
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if got := statuses[http.StatusCreated]; got != initialStock {
+		t.Errorf("başarılı sipariş sayısı %d, beklenen %d (durumlar: %v)", got, initialStock, statuses)
+	}
+	for code, n := range statuses {
+		// Stok bitince sipariş "Yetersiz stok" ile reddedilir; başka hata olmamalı
+		if code != http.StatusCreated && code != http.StatusBadRequest {
+			t.Errorf("beklenmeyen yanıt kodu %d (%d kez)", code, n)
+		}
+	}
+
+	var final models.Product
+	if err := config.DB.First(&final, product.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if final.Stock != 0 {
+		t.Errorf("son stok %v, beklenen 0", final.Stock)
+	}
+
+	var orderCount int64
+	config.DB.Model(&models.Order{}).Count(&orderCount)
+	if orderCount != initialStock {
+		t.Errorf("sipariş kaydı sayısı %d, beklenen %d", orderCount, initialStock)
+	}
+
+	discrepancies, err := inventory.Check(config.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(discrepancies) > 0 {
+		t.Errorf("stok defterle tutmuyor: %s", fmt.Sprint(discrepancies))
+	}
+}
+
+// placeOrder müşteri adına sipariş oluşturur ve siparişi verilen duruma getirir.
+func placeOrder(t *testing.T, customer models.User, shop models.Shop, product models.Product, quantity float64, status models.OrderStatus) models.Order {
+	t.Helper()
+
+	oc := &OrderController{}
+	r := asUser(customer)
+	r.POST("/orders", oc.CreateOrder)
+	w := postJSON(r, "/orders", CreateOrderRequest{
+		ShopID: shop.ID,
+		Items:  []OrderItem{{ProductID: product.ID, Quantity: quantity}},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("sipariş yanıt kodu %d, beklenen 201: %s", w.Code, w.Body)
+	}
+	var resp struct {
+		Order models.Order `json:"order"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if status != models.OrderStatusPending {
+		if err := config.DB.Model(&resp.Order).Update("status", status).Error; err != nil {
+			t.Fatal(err)
+		}
+		resp.Order.Status = status
+	}
+	return resp.Order
+}
+
+// checkLedger stok kolonlarının stok defteriyle tuttuğunu doğrular.
+func checkLedger(t *testing.T) {
+	t.Helper()
+	discrepancies, err := inventory.Check(config.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(discrepancies) > 0 {
+		t.Errorf("stok defterle tutmuyor: %s", fmt.Sprint(discrepancies))
+	}
+}
+
+func TestUpdateOrderStatusTransitions(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		customer, shop, product := testCatalog(t, 100)
+		owner := models.User{ID: shop.UserID, Role: models.RoleShop}
+
+		oc := &OrderController{}
+		r := asUser(owner)
+		r.PUT("/orders/:id/status", oc.UpdateOrderStatus)
+
+		tests := []struct {
+			from models.OrderStatus
+			to   models.OrderStatus
+			note string
+			want int
+		}{
+			{models.OrderStatusPending, models.OrderStatusConfirmed, "", http.StatusOK},
+			{models.OrderStatusConfirmed, models.OrderStatusPreparing, "", http.StatusOK},
+			{models.OrderStatusPreparing, models.OrderStatusReady, "", http.StatusOK},
+			{models.OrderStatusReady, models.OrderStatusDelivered, "", http.StatusOK},
+			{models.OrderStatusReady, models.OrderStatusCancelled, "Müşteri gelmedi", http.StatusOK},
+			{models.OrderStatusPending, models.OrderStatusCancelled, "", http.StatusBadRequest},
+			{models.OrderStatusPending, models.OrderStatusDelivered, "", http.StatusConflict},
+			{models.OrderStatusConfirmed, models.OrderStatusPending, "", http.StatusConflict},
+			{models.OrderStatusDelivered, models.OrderStatusPending, "", http.StatusConflict},
+			{models.OrderStatusDelivered, models.OrderStatusCancelled, "Geç kaldı", http.StatusConflict},
+			{models.OrderStatusCancelled, models.OrderStatusConfirmed, "", http.StatusConflict},
+			{models.OrderStatusPending, "shipped", "", http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			order := placeOrder(t, customer, shop, product, 1, tt.from)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, jsonRequest(http.MethodPut, fmt.Sprintf("/orders/%d/status", order.ID), UpdateOrderStatusRequest{Status: tt.to, Note: tt.note}))
+			if w.Code != tt.want {
+				t.Errorf("%s→%s yanıt kodu %d, beklenen %d: %s", tt.from, tt.to, w.Code, tt.want, w.Body)
+				continue
+			}
+
+			var stored models.Order
+			config.DB.First(&stored, order.ID)
+			var history int64
+			config.DB.Model(&models.OrderStatusHistory{}).Where("order_id = ? AND from_status = ? AND to_status = ?", order.ID, tt.from, tt.to).Count(&history)
+			if tt.want == http.StatusOK && (stored.Status != tt.to || history != 1) {
+				t.Errorf("%s→%s: durum %s, geçmiş kaydı %d", tt.from, tt.to, stored.Status, history)
+			}
+			if tt.want != http.StatusOK && (stored.Status != tt.from || history != 0) {
+				t.Errorf("%s→%s reddedildi ama durum %s, geçmiş kaydı %d", tt.from, tt.to, stored.Status, history)
+			}
+		}
+
+		checkLedger(t)
+	})
+}
+
+func TestCancelOrder(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		customer, shop, product := testCatalog(t, 100)
+		other := models.User{Name: "Başkası", Email: "baskasi@example.com", Password: "x", Role: models.RoleCustomer}
+		if err := config.DB.Create(&other).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		oc := &OrderController{}
+		tests := []struct {
+			status  models.OrderStatus
+			user    models.User
+			want    int
+			restock bool
+		}{
+			{models.OrderStatusPending, customer, http.StatusOK, true},
+			{models.OrderStatusConfirmed, customer, http.StatusOK, true},
+			{models.OrderStatusPreparing, customer, http.StatusConflict, false},
+			{models.OrderStatusDelivered, customer, http.StatusConflict, false},
+			{models.OrderStatusCancelled, customer, http.StatusConflict, false},
+			{models.OrderStatusPending, other, http.StatusForbidden, false},
+		}
+		for _, tt := range tests {
+			order := placeOrder(t, customer, shop, product, 3, tt.status)
+			var before models.Product
+			config.DB.First(&before, product.ID)
+
+			r := asUser(tt.user)
+			r.POST("/orders/:id/cancel", oc.CancelOrder)
+			w := postJSON(r, fmt.Sprintf("/orders/%d/cancel", order.ID), CancelOrderRequest{Reason: "Vazgeçtim"})
+			if w.Code != tt.want {
+				t.Errorf("%s siparişin iptali yanıt kodu %d, beklenen %d: %s", tt.status, w.Code, tt.want, w.Body)
+				continue
+			}
+
+			var after models.Product
+			config.DB.First(&after, product.ID)
+			want := before.Stock
+			if tt.restock {
+				want += 3
+			}
+			if after.Stock != want {
+				t.Errorf("%s siparişin iptalinden sonra stok %v, beklenen %v", tt.status, after.Stock, want)
+			}
+			var restocks int64
+			config.DB.Model(&models.StockMovement{}).Where("order_id = ? AND type = ?", order.ID, models.StockMovementCancellation).Count(&restocks)
+			if tt.restock != (restocks == 1) {
+				t.Errorf("%s siparişin iptalinde %d iade hareketi yazıldı", tt.status, restocks)
+			}
+		}
+
+		checkLedger(t)
+	})
+}
+
+func TestCancelOrderRacesStatusChange(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		customer, shop, product := testCatalog(t, 100)
+		owner := models.User{ID: shop.UserID, Role: models.RoleShop}
+
+		oc := &OrderController{}
+		cancel := asUser(customer)
+		cancel.POST("/orders/:id/cancel", oc.CancelOrder)
+		prepare := asUser(owner)
+		prepare.PUT("/orders/:id/status", oc.UpdateOrderStatus)
+
+		const rounds = 20
+		cancelled := 0
+		for i := 0; i < rounds; i++ {
+			order := placeOrder(t, customer, shop, product, 2, models.OrderStatusConfirmed)
+			path := fmt.Sprintf("/orders/%d", order.ID)
+
+			var wg sync.WaitGroup
+			var cancelCode, prepareCode int
+			start := make(chan struct{})
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				<-start
+				w := httptest.NewRecorder()
+				cancel.ServeHTTP(w, jsonRequest(http.MethodPost, path+"/cancel", CancelOrderRequest{Reason: "Vazgeçtim"}))
+				cancelCode = w.Code
+			}()
+			go func() {
+				defer wg.Done()
+				<-start
+				w := httptest.NewRecorder()
+				prepare.ServeHTTP(w, jsonRequest(http.MethodPut, path+"/status", UpdateOrderStatusRequest{Status: models.OrderStatusPreparing}))
+				prepareCode = w.Code
+			}()
+			close(start)
+			wg.Wait()
+
+			// Yalnızca biri kazanır; kaybeden 409 alır
+			if (cancelCode == http.StatusOK) == (prepareCode == http.StatusOK) {
+				t.Fatalf("iptal %d, hazırlama %d: tam olarak biri başarılı olmalı", cancelCode, prepareCode)
+			}
+			if (cancelCode != http.StatusOK && cancelCode != http.StatusConflict) || (prepareCode != http.StatusOK && prepareCode != http.StatusConflict) {
+				t.Fatalf("beklenmeyen yanıt kodları: iptal %d, hazırlama %d", cancelCode, prepareCode)
+			}
+
+			var stored models.Order
+			config.DB.First(&stored, order.ID)
+			var restocks int64
+			config.DB.Model(&models.StockMovement{}).Where("order_id = ? AND type = ?", order.ID, models.StockMovementCancellation).Count(&restocks)
+			if cancelCode == http.StatusOK {
+				cancelled++
+				if stored.Status != models.OrderStatusCancelled || restocks != 1 {
+					t.Errorf("iptal kazandı ama durum %s, iade hareketi %d", stored.Status, restocks)
+				}
+			} else if stored.Status != models.OrderStatusPreparing || restocks != 0 {
+				t.Errorf("hazırlama kazandı ama durum %s, iade hareketi %d", stored.Status, restocks)
+			}
+		}
+
+		var final models.Product
+		config.DB.First(&final, product.ID)
+		if want := float64(100 - 2*(rounds-cancelled)); final.Stock != want {
+			t.Errorf("son stok %v, beklenen %v", final.Stock, want)
+		}
+		checkLedger(t)
+	})
+}
+
+func TestCreateOrderDecimalQuantity(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		customer, shop, product := testCatalog(t, 10)
+		piece := product
+		product.Unit, product.MinQuantity, product.QuantityStep = models.UnitKg, 0.25, 0.25
+		product.Price = 45000
+		if err := config.DB.Select("unit", "min_quantity", "quantity_step", "price").Updates(&product).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		oc := &OrderController{}
+		r := asUser(customer)
+		r.POST("/orders", oc.CreateOrder)
+		order := func(p models.Product, quantity float64) *httptest.ResponseRecorder {
+			return postJSON(r, "/orders", CreateOrderRequest{
+				ShopID: shop.ID,
+				Items:  []OrderItem{{ProductID: p.ID, Quantity: quantity}},
+			})
+		}
+
+		for _, quantity := range []float64{0.1, 0.3, 10.25} {
+			if w := order(product, quantity); w.Code != http.StatusBadRequest {
+				t.Errorf("%v kg: yanıt kodu %d, beklenen 400: %s", quantity, w.Code, w.Body)
+			}
+		}
+
+		w := order(product, 0.75)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("yanıt kodu %d, beklenen 201: %s", w.Code, w.Body)
+		}
+		var resp struct {
+			Order models.Order `json:"order"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		// 0.75 kg × 450.00 = 337.50
+		if resp.Order.TotalAmount != 33750 {
+			t.Errorf("sipariş tutarı %s, beklenen 337.50", resp.Order.TotalAmount)
+		}
+		var item models.OrderItem
+		config.DB.Where("order_id = ?", resp.Order.ID).First(&item)
+		if item.Quantity != 0.75 || item.Unit != models.UnitKg || item.Price != 45000 {
+			t.Errorf("sipariş kalemi beklenen gibi değil: %+v", item)
+		}
+
+		var stored models.Product
+		config.DB.First(&stored, product.ID)
+		if stored.Stock != 9.25 {
+			t.Errorf("stok %v, beklenen 9.25", stored.Stock)
+		}
+		checkLedger(t)
+
+		// Adetle satılan ürün kesirli sipariş edilemez
+		piece.ID = 0
+		piece.Name = "Simit"
+		if err := config.DB.Create(&piece).Error; err != nil {
+			t.Fatal(err)
+		}
+		if w := order(piece, 1.5); w.Code != http.StatusBadRequest || !bytes.Contains(w.Body.Bytes(), []byte("artışlarla")) {
+			t.Errorf("1.5 adet: yanıt %d %s, beklenen artış hatasıyla 400", w.Code, w.Body)
+		}
+	})
+}
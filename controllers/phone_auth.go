@@ -0,0 +1,530 @@
+package controllers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+	"tradesman-api/config"
+	"tradesman-api/middleware"
+	"tradesman-api/models"
+	"tradesman-api/notify"
+	"tradesman-api/phone"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Bir SMS koduyla yapılabilecek en fazla deneme; aşılırsa yeni kod istenmelidir.
+const otpMaxAttempts = 5
+
+// Kod amacına göre gönderilen SMS şablonu
+var phoneCodeKinds = map[models.PhoneOTPPurpose]notify.Kind{
+	models.PhoneOTPLogin:  notify.PhoneCode,
+	models.PhoneOTPVerify: notify.PhoneVerification,
+}
+
+var errPhoneCodeUsed = errors.New("Kod daha önce kullanılmış, lütfen yeni kod isteyin")
+
+type PhoneCodeRequest struct {
+	Phone    string `json:"phone" binding:"required"`
+	Language string `json:"language"` // SMS dili: tr (varsayılan), en
+}
+
+type PhoneLoginRequest struct {
+	Phone string `json:"phone" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+	Name  string `json:"name"` // Numara kayıtlı değilse yeni hesap için gerekli
+}
+
+type PhoneVerifyRequest struct {
+	Phone    string `json:"phone" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+	Password string `json:"password" binding:"required"` // Hesabın mevcut şifresi
+}
+
+// @Summary Telefonla Giriş Kodu İste
+// @Description Numaraya tek kullanımlık 6 haneli giriş kodu gönderir. Türkiye cep numaraları her biçimde kabul edilir ve +90 ile E.164 biçimine çevrilir. Aynı numaraya dakikada bir, saatte en fazla beş kod gönderilir; ayrıca bir IP adresinden (OTP_IP_HOURLY_LIMIT) ve tüm istemcilerden (OTP_HOURLY_LIMIT) saatte istenebilecek kod sayısı sınırlıdır. Sınır aşılırsa 429 ve Retry-After döner. Yeni kod önceki kodları geçersiz kılar.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param phone body PhoneCodeRequest true "Telefon numarası"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /auth/phone/code [post]
+func (ac *AuthController) RequestPhoneCode(c *gin.Context) {
+	var req PhoneCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	number, err := phone.Normalize(req.Phone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !sendPhoneCode(c, number, models.PhoneOTPLogin, req.Language) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Giriş kodu " + phone.Mask(number) + " numarasına gönderildi",
+		"phone":        number,
+		"expires_in":   int(config.App.OTPTTL.Seconds()),
+		"resend_after": int(sendCooldown.Seconds()),
+	})
+}
+
+// @Summary Telefonla Giriş
+// @Description SMS ile gelen kodla giriş yapar ve /auth/login ile aynı token'ları döner. Numara bir müşteri hesabında doğrulanmışsa (telefonla kayıt veya /auth/phone/verify) o hesapla giriş yapılır; profildeki doğrulanmamış phone alanı eşleştirmede kullanılmaz. Esnaf ve yönetici hesapları telefonla giriş yapamaz (403). Numara kayıtlı değilse name ile SMS bildirimleri açık yeni müşteri hesabı oluşturulur (201); name verilmezse kod harcanmadan name_required=true döner. Yalnızca /auth/phone/code ile istenen kodlar kabul edilir, numara doğrulama kodları girişte kullanılamaz. Her kod en fazla beş kez denenebilir ve tek kullanımlıktır.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param login body PhoneLoginRequest true "Telefon numarası, kod ve yeni hesap için ad"
+// @Success 200 {object} map[string]interface{}
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /auth/phone/login [post]
+func (ac *AuthController) PhoneLogin(c *gin.Context) {
+	var req PhoneLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	number, err := phone.Normalize(req.Phone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	otp, ok := checkPhoneCode(c, number, models.PhoneOTPLogin, req.Code)
+	if !ok {
+		return
+	}
+
+	// Silinmiş hesaplar da benzersiz indekste yer aldığından arama Unscoped yapılır
+	var user models.User
+	err = config.DB.Unscoped().Where("phone_e164 = ?", number).First(&user).Error
+	registered := err == nil
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kod doğrulanamadı"})
+		return
+	}
+	if registered && user.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Bu numara silinmiş bir hesaba kayıtlı"})
+		return
+	}
+	// Esnaf ve yönetici hesapları yalnızca SMS koduyla ele geçirilemesin
+	if registered && user.Role != models.RoleCustomer {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Telefonla giriş yalnızca müşteri hesaplarında kullanılabilir"})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if !registered && name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":         "Bu numarayla kayıtlı hesap yok, yeni hesap için name gerekli",
+			"name_required": true,
+		})
+		return
+	}
+	if registered && user.IsSuspended {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Hesabınız askıya alınmış"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := useOTP(tx, otp); err != nil {
+			return err
+		}
+		if registered {
+			return nil
+		}
+		// Telefonla kayıtlı hesabın e-posta adresi ve şifresi yoktur
+		user = models.User{
+			Name:      name,
+			Phone:     number,
+			PhoneE164: &number,
+			Role:      models.RoleCustomer,
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+
+		// E-posta olmadığından sipariş bildirimleri SMS ile gelir
+		pref := models.DefaultNotificationPreference(user.ID)
+		pref.SMS = true
+		return tx.Create(&pref).Error
+	})
+	if errors.Is(err, errPhoneCodeUsed) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Hesap oluşturulamadı"})
+		return
+	}
+
+	tokens, err := ac.issueTokens(user, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Token oluşturulamadı"})
+		return
+	}
+
+	status, message := http.StatusOK, "Giriş başarılı"
+	if !registered {
+		status, message = http.StatusCreated, "Kullanıcı başarıyla oluşturuldu"
+	}
+	c.JSON(status, gin.H{
+		"message": message,
+		"user": gin.H{
+			"id":             user.ID,
+			"name":           user.Name,
+			"email":          user.Email,
+			"phone":          user.PhoneE164,
+			"role":           user.Role,
+			"email_verified": user.EmailVerified(),
+		},
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    int(config.App.AccessTokenTTL.Seconds()),
+	})
+}
+
+// @Summary Telefon Numarası Doğrulama Kodu İste
+// @Description Giriş yapmış müşterinin profiline bağlamak istediği numaraya 6 haneli doğrulama kodu gönderir. Başka bir hesaba bağlı numaralar için 409 döner. Kod yalnızca /auth/phone/verify ile kullanılabilir; gönderim sınırları /auth/phone/code ile aynıdır ancak ayrı sayılır.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param phone body PhoneCodeRequest true "Telefon numarası"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /auth/phone/verify/code [post]
+func (ac *AuthController) RequestPhoneVerification(c *gin.Context) {
+	var req PhoneCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	number, err := phone.Normalize(req.Phone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	taken, err := phoneTaken(number, middleware.GetUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kod gönderilemedi"})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "Bu numara başka bir hesaba kayıtlı"})
+		return
+	}
+
+	if !sendPhoneCode(c, number, models.PhoneOTPVerify, req.Language) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Doğrulama kodu " + phone.Mask(number) + " numarasına gönderildi",
+		"phone":        number,
+		"expires_in":   int(config.App.OTPTTL.Seconds()),
+		"resend_after": int(sendCooldown.Seconds()),
+	})
+}
+
+// @Summary Telefon Numarasını Doğrula
+// @Description Giriş yapmış müşterinin numarasını SMS kodu ve hesabın şifresiyle doğrular; numara profile doğrulanmış olarak (phone_e164) kaydedilir ve sonraki telefonla girişler bu hesaba yapılır. Profildeki phone alanı doğrulanmadan hiçbir hesaba bağlanmaz.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param verify body PhoneVerifyRequest true "Telefon numarası, kod ve şifre"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /auth/phone/verify [post]
+func (ac *AuthController) VerifyPhone(c *gin.Context) {
+	var req PhoneVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	number, err := phone.Normalize(req.Phone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, middleware.GetUserID(c)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kullanıcı bulunamadı"})
+		return
+	}
+
+	// Telefonla açılmış hesapların şifresi yoktur; numaraları değiştirilemez
+	if user.Password == "" || bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Şifre hatalı"})
+		return
+	}
+
+	taken, err := phoneTaken(number, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Numara doğrulanamadı"})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "Bu numara başka bir hesaba kayıtlı"})
+		return
+	}
+
+	otp, ok := checkPhoneCode(c, number, models.PhoneOTPVerify, req.Code)
+	if !ok {
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := useOTP(tx, otp); err != nil {
+			return err
+		}
+		return tx.Model(&user).Updates(map[string]interface{}{
+			"phone":      number,
+			"phone_e164": number,
+		}).Error
+	})
+	if errors.Is(err, errPhoneCodeUsed) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Numara doğrulanamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Telefon numaranız doğrulandı",
+		"phone":   number,
+	})
+}
+
+// sendPhoneCode gönderim sınırlarını uygulayıp numaraya verilen amaçla yeni bir
+// SMS kodu gönderir; başarısızlıkta yanıtı yazar ve false döner. Yeni kod aynı
+// amaçla gönderilmiş önceki kodları geçersiz kılar.
+func sendPhoneCode(c *gin.Context, number string, purpose models.PhoneOTPPurpose, language string) bool {
+	lang := strings.ToLower(strings.TrimSpace(language))
+	if lang == "" {
+		lang = models.NotificationLanguages[0]
+	}
+	if !models.IsNotificationLanguage(lang) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz dil: " + language + " (" + strings.Join(models.NotificationLanguages, ", ") + ")"})
+		return false
+	}
+
+	now := time.Now()
+
+	var sent []time.Time
+	err := config.DB.Model(&models.PhoneOTP{}).
+		Where("phone = ? AND purpose = ? AND created_at > ?", number, purpose, now.Add(-time.Hour)).
+		Order("created_at DESC").
+		Pluck("created_at", &sent).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kod gönderilemedi"})
+		return false
+	}
+	if wait := sendLimitWait(sent, now); wait > 0 {
+		tooManyRequests(c, wait, "Bu numaraya çok fazla kod istendi, lütfen daha sonra tekrar deneyin")
+		return false
+	}
+
+	// Farklı numaralara kod isteyerek SMS gönderimi kötüye kullanılamasın
+	ip := c.ClientIP()
+	limits := []struct {
+		query   *gorm.DB
+		limit   int
+		message string
+	}{
+		{config.DB.Where("ip = ?", ip), config.App.OTPIPHourlyLimit, "Bu adresten çok fazla kod istendi, lütfen daha sonra tekrar deneyin"},
+		{config.DB, config.App.OTPHourlyLimit, "Şu anda çok fazla kod isteniyor, lütfen daha sonra tekrar deneyin"},
+	}
+	for _, l := range limits {
+		wait, err := hourlyLimitWait(l.query, l.limit, now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Kod gönderilemedi"})
+			return false
+		}
+		if wait > 0 {
+			tooManyRequests(c, wait, l.message)
+			return false
+		}
+	}
+
+	code, err := randomCode()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kod oluşturulamadı"})
+		return false
+	}
+
+	otp := models.PhoneOTP{
+		Phone:     number,
+		Purpose:   purpose,
+		IP:        ip,
+		CodeHash:  hashPhoneCode(number, code),
+		ExpiresAt: now.Add(config.App.OTPTTL),
+	}
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Saatlik sınırların dışında kalan kayıtlar
+		if err := tx.Where("created_at < ?", now.Add(-time.Hour)).Delete(&models.PhoneOTP{}).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&models.PhoneOTP{}).
+			Where("phone = ? AND purpose = ? AND used_at IS NULL", number, purpose).
+			Update("used_at", now).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&otp).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kod oluşturulamadı"})
+		return false
+	}
+
+	err = notify.SendNow(c.Request.Context(), models.ChannelSMS, number, phoneCodeKinds[purpose], lang, notify.AccountData{
+		Token:    code,
+		ValidFor: config.App.OTPTTL,
+	})
+	if err != nil {
+		// Gönderilemeyen kod sınıra sayılmaz, hemen yeniden istenebilir
+		config.DB.Delete(&otp)
+		log.Printf("SMS kodu gönderilemedi (%s): %v", phone.Mask(number), err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "SMS gönderilemedi, lütfen tekrar deneyin"})
+		return false
+	}
+	return true
+}
+
+// hourlyLimitWait sorgunun son bir saatteki kod sayısı limite ulaştıysa, en eski
+// sayılan kod pencereden düşene kadar beklenmesi gereken süreyi döner.
+func hourlyLimitWait(query *gorm.DB, limit int, now time.Time) (time.Duration, error) {
+	var sent []time.Time
+	err := query.Model(&models.PhoneOTP{}).
+		Where("created_at > ?", now.Add(-time.Hour)).
+		Order("created_at DESC").
+		Offset(limit-1).
+		Limit(1).
+		Pluck("created_at", &sent).Error
+	if err != nil || len(sent) == 0 {
+		return 0, err
+	}
+	return max(sent[0].Add(time.Hour).Sub(now), time.Second), nil
+}
+
+// checkPhoneCode numaraya verilen amaçla gönderilmiş geçerli son kodla gönderilen
+// kodu karşılaştırır; kod hatalıysa yanıtı yazar ve false döner. Kodu
+// kullanılmış saymak çağıranın işidir (useOTP).
+func checkPhoneCode(c *gin.Context, number string, purpose models.PhoneOTPPurpose, code string) (models.PhoneOTP, bool) {
+	var otp models.PhoneOTP
+	err := config.DB.Where("phone = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", number, purpose, time.Now()).
+		Order("id DESC").
+		First(&otp).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçerli bir kod yok, lütfen yeni kod isteyin"})
+		return otp, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kod doğrulanamadı"})
+		return otp, false
+	}
+
+	// Denemeyi karşılaştırmadan önce say; eş zamanlı istekler sınırı aşamaz
+	result := config.DB.Model(&models.PhoneOTP{}).
+		Where("id = ? AND attempts < ?", otp.ID, otpMaxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kod doğrulanamadı"})
+		return otp, false
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Çok fazla hatalı deneme, lütfen yeni kod isteyin"})
+		return otp, false
+	}
+
+	if !hmac.Equal([]byte(hashPhoneCode(number, strings.TrimSpace(code))), []byte(otp.CodeHash)) {
+		left := otpMaxAttempts - otp.Attempts - 1
+		if left <= 0 {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Çok fazla hatalı deneme, lütfen yeni kod isteyin"})
+			return otp, false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":         "Kod hatalı",
+			"attempts_left": left,
+		})
+		return otp, false
+	}
+	return otp, true
+}
+
+// useOTP kodu kullanılmış olarak işaretler; eş zamanlı ikinci kullanımda
+// errPhoneCodeUsed döner.
+func useOTP(tx *gorm.DB, otp models.PhoneOTP) error {
+	result := tx.Model(&models.PhoneOTP{}).
+		Where("id = ? AND used_at IS NULL", otp.ID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errPhoneCodeUsed
+	}
+	return nil
+}
+
+// phoneTaken numaranın başka bir hesapta doğrulanmış olup olmadığını döner.
+// Silinmiş hesaplar da benzersiz indekste yer aldığından sayılır.
+func phoneTaken(number string, userID uint) (bool, error) {
+	var count int64
+	err := config.DB.Unscoped().Model(&models.User{}).
+		Where("phone_e164 = ? AND id <> ?", number, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// randomCode 6 haneli rastgele sayısal kod üretir.
+func randomCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// hashPhoneCode kodu numarayla birlikte JWT anahtarıyla imzalar; 6 haneli kodlar
+// anahtar olmadan veritabanından geri bulunamaz.
+func hashPhoneCode(number, code string) string {
+	mac := hmac.New(sha256.New, []byte(config.App.JWTSecret))
+	mac.Write([]byte(number + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
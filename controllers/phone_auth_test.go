@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"tradesman-api/config"
+	"tradesman-api/models"
+	"tradesman-api/notify"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPhoneCode = "123456"
+
+// issuePhoneCode SMS göndermeden numaraya verilen amaçla bilinen bir kod kaydeder.
+func issuePhoneCode(t *testing.T, number string, purpose models.PhoneOTPPurpose) {
+	t.Helper()
+	otp := models.PhoneOTP{
+		Phone:     number,
+		Purpose:   purpose,
+		CodeHash:  hashPhoneCode(number, testPhoneCode),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	if err := config.DB.Create(&otp).Error; err != nil {
+		t.Fatalf("kod kaydedilemedi: %v", err)
+	}
+}
+
+func TestPhoneLogin(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		const number = "+905321112233"
+
+		// Profilde numarayı yazan hesap doğrulanmadıkça eşleştirilmez
+		other := models.User{Name: "Başkası", Email: "baskasi@example.com", Password: "x", Phone: "0532 111 22 33", Role: models.RoleCustomer}
+		verified := "+905329998877"
+		shop := models.User{Name: "Esnaf", Email: "esnaf@example.com", Password: "x", PhoneE164: &verified, Role: models.RoleShop}
+		for _, u := range []*models.User{&other, &shop} {
+			if err := config.DB.Create(u).Error; err != nil {
+				t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+			}
+		}
+
+		ac := &AuthController{}
+		r := gin.New()
+		r.POST("/auth/phone/login", ac.PhoneLogin)
+
+		issuePhoneCode(t, number, models.PhoneOTPLogin)
+		w := postJSON(r, "/auth/phone/login", PhoneLoginRequest{Phone: number, Code: testPhoneCode})
+		if w.Code != http.StatusBadRequest || !bytes.Contains(w.Body.Bytes(), []byte("name_required")) {
+			t.Fatalf("yanıt %d %s, beklenen 400 name_required", w.Code, w.Body)
+		}
+		w = postJSON(r, "/auth/phone/login", PhoneLoginRequest{Phone: number, Code: testPhoneCode, Name: "Ayşe"})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("yanıt kodu %d, beklenen 201: %s", w.Code, w.Body)
+		}
+		var stored models.User
+		config.DB.First(&stored, other.ID)
+		if stored.PhoneE164 != nil {
+			t.Errorf("profil numarası hesaba bağlandı: %s", *stored.PhoneE164)
+		}
+
+		// Esnaf hesabına SMS koduyla girilemez
+		issuePhoneCode(t, verified, models.PhoneOTPLogin)
+		w = postJSON(r, "/auth/phone/login", PhoneLoginRequest{Phone: verified, Code: testPhoneCode})
+		if w.Code != http.StatusForbidden {
+			t.Errorf("yanıt kodu %d, beklenen 403: %s", w.Code, w.Body)
+		}
+	})
+}
+
+func TestVerifyPhone(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		const number = "+905321112233"
+
+		hash, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.MinCost)
+		customer := models.User{Name: "Müşteri", Email: "musteri@example.com", Password: string(hash), Role: models.RoleCustomer}
+		if err := config.DB.Create(&customer).Error; err != nil {
+			t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+		}
+
+		ac := &AuthController{}
+		r := asUser(customer)
+		r.POST("/auth/phone/verify", ac.VerifyPhone)
+		login := gin.New()
+		login.POST("/auth/phone/login", ac.PhoneLogin)
+
+		issuePhoneCode(t, number, models.PhoneOTPVerify)
+		w := postJSON(r, "/auth/phone/verify", PhoneVerifyRequest{Phone: number, Code: testPhoneCode, Password: "yanlis"})
+		if w.Code != http.StatusUnauthorized {
+			t.Fatalf("yanıt kodu %d, beklenen 401: %s", w.Code, w.Body)
+		}
+		w = postJSON(r, "/auth/phone/verify", PhoneVerifyRequest{Phone: "0532 111 22 33", Code: testPhoneCode, Password: "123456"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+
+		// Doğrulanan numarayla giriş aynı hesaba yapılır
+		issuePhoneCode(t, number, models.PhoneOTPLogin)
+		w = postJSON(login, "/auth/phone/login", PhoneLoginRequest{Phone: number, Code: testPhoneCode})
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		var resp struct {
+			User struct {
+				ID uint `json:"id"`
+			} `json:"user"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.User.ID != customer.ID {
+			t.Errorf("giriş yapılan hesap %d, beklenen %d", resp.User.ID, customer.ID)
+		}
+	})
+}
+
+func TestPhoneCodePurpose(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		const number = "+905321112233"
+
+		hash, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.MinCost)
+		customer := models.User{Name: "Müşteri", Email: "musteri@example.com", Password: string(hash), Role: models.RoleCustomer}
+		if err := config.DB.Create(&customer).Error; err != nil {
+			t.Fatalf("kullanıcı oluşturulamadı: %v", err)
+		}
+
+		ac := &AuthController{}
+		r := asUser(customer)
+		r.POST("/auth/phone/verify", ac.VerifyPhone)
+		login := gin.New()
+		login.POST("/auth/phone/login", ac.PhoneLogin)
+
+		// Numara doğrulama kodu girişte kullanılamaz
+		issuePhoneCode(t, number, models.PhoneOTPVerify)
+		w := postJSON(login, "/auth/phone/login", PhoneLoginRequest{Phone: number, Code: testPhoneCode, Name: "Ayşe"})
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("yanıt kodu %d, beklenen 400: %s", w.Code, w.Body)
+		}
+		var users int64
+		config.DB.Model(&models.User{}).Where("phone_e164 = ?", number).Count(&users)
+		if users != 0 {
+			t.Error("doğrulama koduyla hesap açıldı")
+		}
+
+		// Giriş kodu da numara doğrulamada kullanılamaz
+		config.DB.Where("phone = ?", number).Delete(&models.PhoneOTP{})
+		issuePhoneCode(t, number, models.PhoneOTPLogin)
+		w = postJSON(r, "/auth/phone/verify", PhoneVerifyRequest{Phone: number, Code: testPhoneCode, Password: "123456"})
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("yanıt kodu %d, beklenen 400: %s", w.Code, w.Body)
+		}
+	})
+}
+
+func TestRequestPhoneCodeLimits(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		previous := notify.Default
+		notify.Default = notify.NewService(config.DB, map[models.NotificationChannel]notify.Channel{
+			models.ChannelSMS: notify.Outbox{DB: config.DB},
+		})
+		t.Cleanup(func() { notify.Default = previous })
+		config.App.OTPIPHourlyLimit = 2
+		config.App.OTPHourlyLimit = 3
+
+		ac := &AuthController{}
+		r := gin.New()
+		r.POST("/auth/phone/code", ac.RequestPhoneCode)
+		request := func(ip, number string) *httptest.ResponseRecorder {
+			data, _ := json.Marshal(PhoneCodeRequest{Phone: number})
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/auth/phone/code", bytes.NewReader(data))
+			req.Header.Set("Content-Type", "application/json")
+			req.RemoteAddr = ip + ":40000"
+			r.ServeHTTP(w, req)
+			return w
+		}
+
+		// Aynı adresten farklı numaralara istenen kodlar da sınıra sayılır
+		for _, number := range []string{"+905321110001", "+905321110002"} {
+			if w := request("192.0.2.1", number); w.Code != http.StatusOK {
+				t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+			}
+		}
+		w := request("192.0.2.1", "+905321110003")
+		if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+			t.Fatalf("yanıt kodu %d, beklenen 429 ve Retry-After: %s", w.Code, w.Body)
+		}
+
+		// Tüm istemcilerin toplam gönderimi de sınırlıdır
+		if w := request("192.0.2.2", "+905321110004"); w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		if w := request("192.0.2.3", "+905321110005"); w.Code != http.StatusTooManyRequests {
+			t.Fatalf("yanıt kodu %d, beklenen 429: %s", w.Code, w.Body)
+		}
+
+		var sent int64
+		config.DB.Model(&models.OutboxMessage{}).Count(&sent)
+		if sent != 3 {
+			t.Errorf("%d SMS gönderildi, beklenen 3", sent)
+		}
+	})
+}
@@ -1,62 +1,327 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"tradesman-api/config"
 	"tradesman-api/middleware"
 	"tradesman-api/models"
+	"tradesman-api/money"
+	"tradesman-api/search"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type ProductController struct{}
 
 type CreateProductRequest struct {
-	Name        string  `json:"name" binding:"required"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
-	Stock       int     `json:"stock" binding:"gte=0"`
-	ImageURL    string  `json:"image_url"`
+	Name             string       `json:"name" binding:"required"`
+	SKU              string       `json:"sku" binding:"max=64"` // İsteğe bağlı, dükkan içinde benzersiz; toplu içe aktarmada eşleştirme anahtarı
+	CategoryID       *uint        `json:"category_id"`
+	Description      string       `json:"description"`
+	Price            money.Amount `json:"price" binding:"required,gt=0" swaggertype:"number" example:"12.50"` // Birim başına fiyat, en fazla 2 ondalık
+	Currency         string       `json:"currency"`                                                           // ISO 4217 kodu (varsayılan TRY)
+	Stock            float64      `json:"stock" binding:"gte=0"`                                              // Birim cinsinden
+	Unit             models.Unit  `json:"unit"`                                                               // piece, kg, g, litre, bunch (varsayılan piece)
+	MinQuantity      float64      `json:"min_quantity" binding:"gte=0"`                                       // Verilmezse birimin varsayılanı
+	QuantityStep     float64      `json:"quantity_step" binding:"gte=0"`                                      // Verilmezse birimin varsayılanı
+	ReorderThreshold float64      `json:"reorder_threshold" binding:"gte=0"`                                  // Stok bu değere düşünce uyarı oluşur; 0 ise yalnızca tükenince
+	ImageURL         string       `json:"image_url"`
+}
+
+// Ürün detayında dönen ürün; dükkan sahibinin yalnızca herkese açık bilgileri gösterilir
+type PublicProduct struct {
+	models.Product
+	Shop PublicShop `json:"shop"`
+}
+
+var productListOptions = listOptions{
+	table: "products",
+	sorts: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"price":      "price",
+		"stock":      "stock",
+		"created_at": "created_at",
+	},
+	defaultSort: "id",
 }
 
 // @Summary Tüm Ürünleri Listele
-// @Description Aktif olan tüm ürünleri listeler
+// @Description Aktif olan ürünleri sayfalı olarak listeler
 // @Tags Products
 // @Produce json
+// @Param page query int false "Sayfa numarası (varsayılan 1)"
+// @Param per_page query int false "Sayfa başına kayıt (varsayılan 20, en fazla 100)"
+// @Param sort query string false "Sıralama: id, name, price, stock, created_at (azalan için başına -)"
+// @Param shop_id query int false "Esnaf filtresi"
+// @Param category_id query int false "Kategori filtresi (alt kategoriler dahil)"
+// @Param min_price query number false "En düşük fiyat"
+// @Param max_price query number false "En yüksek fiyat"
+// @Param in_stock query bool false "Sadece stokta olanlar (true) veya tükenenler (false)"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Router /products [get]
 func (pc *ProductController) GetProducts(c *gin.Context) {
+	lq, err := parseListQuery(c, productListOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := visibleProducts(config.DB.Model(&models.Product{}))
+
+	shopID, err := queryUint(c, "shop_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if shopID != nil {
+		query = query.Where("shop_id = ?", *shopID)
+	}
+
+	categoryID, err := queryUint(c, "category_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if categoryID != nil {
+		categories, err := loadCategories()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Kategoriler getirilemedi"})
+			return
+		}
+		query = query.Where("category_id IN ?", categoryDescendantIDs(categories, *categoryID))
+	}
+
+	query, err = filterProducts(c, query)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var products []models.Product
-	if err := config.DB.Preload("Shop").Where("is_active = ?", true).Find(&products).Error; err != nil {
+	pagination, err := findPage(c, query, lq, &products, "Shop", "Category")
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ürünler getirilemedi"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"products": products,
+		"products":   products,
+		"pagination": pagination,
+	})
+}
+
+func optionalSKU(sku string) *string {
+	sku = strings.TrimSpace(sku)
+	if sku == "" {
+		return nil
+	}
+	return &sku
+}
+
+// checkProductSKU SKU'nun dükkanın başka bir ürününde kullanılmadığını kontrol eder.
+func checkProductSKU(product models.Product) error {
+	if product.SKU == nil {
+		return nil
+	}
+	var count int64
+	config.DB.Model(&models.Product{}).
+		Where("shop_id = ? AND sku = ? AND id <> ?", product.ShopID, *product.SKU, product.ID).
+		Count(&count)
+	if count > 0 {
+		return errors.New("Bu SKU dükkanınızda başka bir üründe kullanılıyor")
+	}
+	return nil
+}
+
+var errUnitInUse = errors.New("Stok hareketi veya siparişi olan ürünün birimi değiştirilemez; yeni birim için ayrı ürün oluşturun")
+
+// checkUnitChange ürünün stok hareketi veya sipariş kalemi olmadığını kontrol
+// eder. Defterdeki ve siparişlerdeki miktarlar eski birimle yazıldığından birim
+// değişince anlamları değişirdi.
+func checkUnitChange(db *gorm.DB, productID uint) error {
+	var movements, items int64
+	if err := db.Model(&models.StockMovement{}).Where("product_id = ?", productID).Count(&movements).Error; err != nil {
+		return err
+	}
+	if err := db.Model(&models.OrderItem{}).Where("product_id = ?", productID).Count(&items).Error; err != nil {
+		return err
+	}
+	if movements > 0 || items > 0 {
+		return errUnitInUse
+	}
+	return nil
+}
+
+// Aktif çeşidi olan ürünlerde stok çeşitlerden, diğerlerinde üründen gelir.
+const productInStock = "((stock > 0 AND NOT EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND pv.is_active = ?))" +
+	" OR EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND pv.is_active = ? AND pv.stock > 0))"
+
+// Stoğu biten ürünleri gizleyen dükkanların tükenen ürünleri listelenmez.
+const productVisible = "(NOT EXISTS (SELECT 1 FROM shops s WHERE s.id = products.shop_id AND s.hide_out_of_stock = ?) OR " + productInStock + ")"
+
+// visibleProducts müşteri listelerinde gösterilecek ürünleri seçer: aktif olan
+// ve dükkanı gizlemiyorsa stoğu biten ürünler.
+func visibleProducts(db *gorm.DB) *gorm.DB {
+	return db.Where("products.is_active = ?", true).Where(productVisible, true, true, true)
+}
+
+// filterProducts fiyat aralığı ve stok durumu filtrelerini uygular.
+func filterProducts(c *gin.Context, query *gorm.DB) (*gorm.DB, error) {
+	minPrice, err := queryAmount(c, "min_price")
+	if err != nil {
+		return nil, err
+	}
+	maxPrice, err := queryAmount(c, "max_price")
+	if err != nil {
+		return nil, err
+	}
+	if minPrice != nil && maxPrice != nil && *minPrice > *maxPrice {
+		return nil, errors.New("min_price, max_price değerinden büyük olamaz")
+	}
+	inStock, err := queryBool(c, "in_stock")
+	if err != nil {
+		return nil, err
+	}
+
+	if minPrice != nil {
+		query = query.Where("price >= ?", *minPrice)
+	}
+	if maxPrice != nil {
+		query = query.Where("price <= ?", *maxPrice)
+	}
+	if inStock != nil {
+		if *inStock {
+			query = query.Where(productInStock, true, true)
+		} else {
+			query = query.Where("NOT "+productInStock, true, true)
+		}
+	}
+	return query, nil
+}
+
+// Arama sonucunda ürünle birlikte dönen işaretlenmiş metinler
+type ProductSearchResult struct {
+	models.Product
+	Highlight ProductHighlight `json:"highlight"`
+}
+
+type ProductHighlight struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+const searchSnippetWords = 12
+
+var searchListOptions = listOptions{
+	sorts:       map[string]string{"relevance": "rank"},
+	defaultSort: "relevance",
+}
+
+// @Summary Ürün Ara
+// @Description Aktif ürünlerde isim ve açıklamada tam metin arama yapar; stoğu biten ürünleri gizleyen dükkanların tükenen ürünleri listede olduğu gibi sonuçlarda da yer almaz. Türkçe karakterlerden ve büyük/küçük harften bağımsızdır, kelime başlarıyla eşleşir (ör. "sim" → "Simit"). Sonuçlar alaka sırasıyla döner; eşleşen kelimeler highlight alanında <mark> ile işaretlenir.
+// @Tags Products
+// @Produce json
+// @Param q query string true "Arama ifadesi"
+// @Param page query int false "Sayfa numarası (varsayılan 1)"
+// @Param per_page query int false "Sayfa başına kayıt (varsayılan 20, en fazla 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /products/search [get]
+func (pc *ProductController) SearchProducts(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	terms := search.Terms(q)
+	if len(terms) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Arama ifadesi (q) gerekli"})
+		return
+	}
+
+	lq, err := parseListQuery(c, searchListOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ids, total, err := search.Products(config.DB, terms, lq.PerPage, lq.Offset(), productVisible, true, true, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Arama yapılamadı"})
+		return
+	}
+
+	var products []models.Product
+	if len(ids) > 0 {
+		if err := config.DB.Preload("Shop").Where("id IN ?", ids).Find(&products).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Ürünler getirilemedi"})
+			return
+		}
+	}
+
+	// Sonuçlar arama motorunun alaka sırasına göre dizilir
+	byID := make(map[uint]models.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+	results := make([]ProductSearchResult, 0, len(ids))
+	for _, id := range ids {
+		p, ok := byID[id]
+		if !ok {
+			continue
+		}
+		results = append(results, ProductSearchResult{
+			Product: p,
+			Highlight: ProductHighlight{
+				Name:        search.Highlight(p.Name, terms),
+				Description: search.Snippet(p.Description, terms, searchSnippetWords),
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":      q,
+		"products":   results,
+		"pagination": paginate(c, lq, total),
 	})
 }
 
 // @Summary Ürün Detayı
-// @Description Belirli bir ürünün detaylarını getirir
+// @Description Belirli bir ürünün detaylarını görselleri, aktif çeşitleri ve seçenek gruplarıyla birlikte getirir
 // @Tags Products
 // @Produce json
 // @Param id path int true "Ürün ID"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /products/{id} [get]
 func (pc *ProductController) GetProduct(c *gin.Context) {
-	id := c.Param("id")
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz ürün ID"})
+		return
+	}
 
 	var product models.Product
-	if err := config.DB.Preload("Shop.User").First(&product, id).Error; err != nil {
+	err = config.DB.Preload("Shop.User").Preload("Category").
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ?", true).Order("sort_order, id")
+		}).
+		Preload("OptionGroups", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order, id") }).
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order, id") }).
+		Preload("OptionGroups.Options", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ?", true).Order("sort_order, id")
+		}).
+		First(&product, productID).Error
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Ürün bulunamadı"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"product": product,
+		"product": PublicProduct{Product: product, Shop: publicShop(product.Shop)},
 	})
 }
 
@@ -70,6 +335,7 @@ func (pc *ProductController) GetProduct(c *gin.Context) {
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Router /products [post]
 func (pc *ProductController) CreateProduct(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -94,23 +360,65 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 		return
 	}
 
+	if err := validateCategoryID(req.CategoryID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	product := models.Product{
-		ShopID:      shop.ID,
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
-		Stock:       req.Stock,
-		ImageURL:    req.ImageURL,
-		IsActive:    true,
+		ShopID:           shop.ID,
+		SKU:              optionalSKU(req.SKU),
+		CategoryID:       req.CategoryID,
+		Name:             req.Name,
+		Description:      req.Description,
+		Price:            req.Price,
+		Currency:         req.Currency,
+		Stock:            req.Stock,
+		Unit:             req.Unit,
+		MinQuantity:      req.MinQuantity,
+		QuantityStep:     req.QuantityStep,
+		ReorderThreshold: req.ReorderThreshold,
+		ImageURL:         req.ImageURL,
+		IsActive:         true,
 	}
+	product.ApplyUnitDefaults()
 
-	if err := config.DB.Create(&product).Error; err != nil {
+	if err := product.ValidateUnit(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if product.Currency == "" {
+		product.Currency = money.DefaultCurrency
+	}
+	if !money.ValidCurrency(product.Currency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz para birimi, ISO 4217 kodu olmalı (ör. TRY)"})
+		return
+	}
+
+	if err := checkProductSKU(product); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Ürün sıfır stokla oluşturulur; girilen stok deftere açılış hareketi olarak yazılır
+	stock := product.Stock
+	product.Stock = 0
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&product).Error; err != nil {
+			return err
+		}
+		// Açılış stoğu giriş hareketidir, uyarı oluşturmaz
+		_, err := recordStockCount(tx, product, nil, stock, userID, "Açılış stoğu")
+		return err
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ürün oluşturulamadı"})
 		return
 	}
 
-	// Product'ı shop ile birlikte getir
-	config.DB.Preload("Shop").First(&product, product.ID)
+	// Product'ı shop ve kategori ile birlikte getir
+	config.DB.Preload("Shop").Preload("Category").First(&product, product.ID)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Ürün başarıyla oluşturuldu",
@@ -119,7 +427,7 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 }
 
 // @Summary Ürün Güncelle
-// @Description Ürün bilgilerini günceller (sadece ürün sahibi)
+// @Description Ürün bilgilerini günceller (sadece ürün sahibi). Stok doğrudan değiştirilmez; girilen stok mevcut stoktan farklıysa fark stok defterine düzeltme hareketi olarak yazılır. Aktif çeşidi olan ürünlerde stok çeşitlerde tutulur; farklı bir stok girilirse 400 döner. Stok hareketi veya siparişi olan ürünün birimi değiştirilemez (409).
 // @Tags Products
 // @Accept json
 // @Produce json
@@ -130,6 +438,7 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 // @Failure 400 {object} map[string]interface{}
 // @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Router /products/{id} [put]
 func (pc *ProductController) UpdateProduct(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -157,18 +466,83 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 		return
 	}
 
+	if err := validateCategoryID(req.CategoryID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Aktif çeşidi olan ürünün stoğu çeşitlerde tutulur; ürün stoğu değiştirilemez
+	if models.RoundQuantity(req.Stock) != models.RoundQuantity(product.Stock) {
+		if status, err := validateStockTarget(product, nil); err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	unit := product.Unit
+	product.SKU = optionalSKU(req.SKU)
+	product.CategoryID = req.CategoryID
+	product.Category = nil
 	product.Name = req.Name
 	product.Description = req.Description
 	product.Price = req.Price
+	product.Currency = req.Currency
 	product.Stock = req.Stock
+	product.Unit = req.Unit
+	product.MinQuantity = req.MinQuantity
+	product.QuantityStep = req.QuantityStep
+	product.ReorderThreshold = req.ReorderThreshold
 	product.ImageURL = req.ImageURL
+	product.ApplyUnitDefaults()
+
+	if err := product.ValidateUnit(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if product.Currency == "" {
+		product.Currency = money.DefaultCurrency
+	}
+	if !money.ValidCurrency(product.Currency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz para birimi, ISO 4217 kodu olmalı (ör. TRY)"})
+		return
+	}
 
-	if err := config.DB.Save(&product).Error; err != nil {
+	if err := checkProductSKU(product); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Stok doğrudan yazılmaz; girilen stokla fark varsa deftere düzeltme hareketi yazılır
+	var alert *models.StockAlert
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Birim değişiyorsa aynı anda sipariş verilemesin diye ürün kilitlenir
+		if product.Unit != unit {
+			if err := lockForUpdate(tx).Select("id").First(&models.Product{}, product.ID).Error; err != nil {
+				return err
+			}
+			if err := checkUnitChange(tx, product.ID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Omit("stock").Save(&product).Error; err != nil {
+			return err
+		}
+		var err error
+		alert, err = recordStockCount(tx, product, nil, product.Stock, userID, "Ürün güncellemesinde girilen stok")
+		return err
+	})
+	if errors.Is(err, errUnitInUse) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ürün güncellenemedi"})
 		return
 	}
+	publishStockAlerts(alert)
 
-	config.DB.Preload("Shop").First(&product, product.ID)
+	config.DB.Preload("Shop").Preload("Category").First(&product, product.ID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Ürün başarıyla güncellendi",
@@ -177,7 +551,7 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 }
 
 // @Summary Ürün Sil
-// @Description Ürünü siler (sadece ürün sahibi)
+// @Description Ürünü siler (sadece ürün sahibi). Ürünün görselleri depodan da silinir.
 // @Tags Products
 // @Security BearerAuth
 // @Param id path int true "Ürün ID"
@@ -205,12 +579,31 @@ func (pc *ProductController) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	// Soft delete
-	if err := config.DB.Delete(&product).Error; err != nil {
+	images, err := loadProductImages(product.ID)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ürün silinemedi"})
 		return
 	}
 
+	// Ürün soft delete edilir; görseller ise dosyalarıyla birlikte tamamen silinir.
+	// SKU, aynı kodla yeni ürün eklenebilsin diye boşaltılır.
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", product.ID).Delete(&models.ProductImage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&product).Update("sku", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&product).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ürün silinemedi"})
+		return
+	}
+	for _, image := range images {
+		removeStoredFiles(image.Keys())
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Ürün başarıyla silindi",
 	})
@@ -0,0 +1,224 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"tradesman-api/config"
+	"tradesman-api/inventory"
+	"tradesman-api/models"
+	"tradesman-api/money"
+)
+
+// addVariant ürüne aktif bir çeşit ekler; stok artık çeşitte tutulur.
+func addVariant(t *testing.T, product models.Product) models.ProductVariant {
+	t.Helper()
+	variant := models.ProductVariant{
+		ProductID: product.ID,
+		ShopID:    product.ShopID,
+		Name:      "Büyük",
+		SKU:       fmt.Sprintf("V-%d", product.ID),
+		Price:     money.Amount(1500),
+		IsActive:  true,
+	}
+	if err := config.DB.Create(&variant).Error; err != nil {
+		t.Fatalf("çeşit oluşturulamadı: %v", err)
+	}
+	return variant
+}
+
+func TestUpdateProductVariantStock(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		_, shop, product := testCatalog(t, 10)
+		owner := models.User{ID: shop.UserID, Role: models.RoleShop}
+		addVariant(t, product)
+
+		pc := &ProductController{}
+		r := asUser(owner)
+		r.PUT("/products/:id", pc.UpdateProduct)
+		put := func(stock float64) *httptest.ResponseRecorder {
+			body := fmt.Sprintf(`{"name":"Ekmek","price":"10.00","stock":%v}`, stock)
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/products/%d", product.ID), strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+			return w
+		}
+
+		if w := put(25); w.Code != http.StatusBadRequest {
+			t.Fatalf("yanıt kodu %d, beklenen 400: %s", w.Code, w.Body)
+		}
+		// Stoğu değiştirmeyen güncelleme kabul edilir
+		if w := put(10); w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+
+		var stored models.Product
+		if err := config.DB.First(&stored, product.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if stored.Stock != 10 {
+			t.Errorf("ürün stoğu %v, beklenen 10", stored.Stock)
+		}
+		var movements int64
+		config.DB.Model(&models.StockMovement{}).Where("product_id = ? AND variant_id IS NULL", product.ID).Count(&movements)
+		if movements != 1 {
+			t.Errorf("ürün düzeyinde %d hareket, beklenen yalnızca açılış stoğu", movements)
+		}
+
+		discrepancies, err := inventory.Check(config.DB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(discrepancies) > 0 {
+			t.Errorf("stok defterle tutmuyor: %s", fmt.Sprint(discrepancies))
+		}
+	})
+}
+
+func TestGetLowStockOrder(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		_, shop, _ := testCatalog(t, 10)
+		owner := models.User{ID: shop.UserID, Role: models.RoleShop}
+
+		// Eşiği sıfır olan tükenmiş ürün oran hesabını bozmamalı
+		products := []models.Product{
+			{Name: "Simit", Stock: 4, ReorderThreshold: 5},
+			{Name: "Poğaça", Stock: 0, ReorderThreshold: 0},
+			{Name: "Açma", Stock: 2, ReorderThreshold: 10},
+			{Name: "Börek", Stock: 0, ReorderThreshold: 5},
+		}
+		for i := range products {
+			products[i].ShopID = shop.ID
+			products[i].Price = money.Amount(500)
+			products[i].Currency = money.DefaultCurrency
+			products[i].Unit = models.UnitPiece
+			products[i].IsActive = true
+			products[i].ApplyUnitDefaults()
+			if err := config.DB.Create(&products[i]).Error; err != nil {
+				t.Fatalf("ürün oluşturulamadı: %v", err)
+			}
+		}
+
+		pc := &ProductController{}
+		r := asUser(owner)
+		r.GET("/products/low-stock", pc.GetLowStock)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/products/low-stock", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		var resp struct {
+			Items []LowStockItem `json:"items"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, item := range resp.Items {
+			names = append(names, item.Name)
+		}
+		if got, want := strings.Join(names, ","), "Poğaça,Börek,Açma,Simit"; got != want {
+			t.Errorf("sıra %s, beklenen %s", got, want)
+		}
+	})
+}
+
+func TestCreateProductUnits(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		_, shop, _ := testCatalog(t, 10)
+		owner := models.User{ID: shop.UserID, Role: models.RoleShop}
+
+		pc := &ProductController{}
+		r := asUser(owner)
+		r.POST("/products", pc.CreateProduct)
+
+		// Verilmeyen en az miktar ve artış birimin varsayılanlarıdır
+		w := postJSON(r, "/products", CreateProductRequest{Name: "Kaşar", Price: 45000, Unit: models.UnitKg, Stock: 2.5})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("yanıt kodu %d, beklenen 201: %s", w.Code, w.Body)
+		}
+		var resp struct {
+			Product models.Product `json:"product"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Product.MinQuantity != 0.1 || resp.Product.QuantityStep != 0.1 || resp.Product.Stock != 2.5 {
+			t.Errorf("ürün beklenen gibi değil: %+v", resp.Product)
+		}
+
+		invalid := []CreateProductRequest{
+			{Name: "Simit", Price: 1000, Unit: models.UnitPiece, Stock: 2.5},                        // Adette kesirli stok
+			{Name: "Simit", Price: 1000, Unit: models.UnitPiece, QuantityStep: 0.5},                 // Adette kesirli artış
+			{Name: "Kaşar", Price: 1000, Unit: models.UnitKg, MinQuantity: 0.25, QuantityStep: 0.1}, // En az miktar artışın katı değil
+			{Name: "Kaşar", Price: 1000, Unit: models.UnitKg, Stock: 1.2345},                        // 3'ten fazla ondalık
+			{Name: "Kaşar", Price: 1000, Unit: models.UnitKg, QuantityStep: 0.0005},                 // 3'ten fazla ondalık
+			{Name: "Demet", Price: 1000, Unit: models.UnitBunch, ReorderThreshold: 1.5},             // Bağda kesirli eşik
+			{Name: "Kömür", Price: 1000, Unit: "ton"},                                               // Geçersiz birim
+		}
+		for _, req := range invalid {
+			if w := postJSON(r, "/products", req); w.Code != http.StatusBadRequest {
+				t.Errorf("%+v: yanıt kodu %d, beklenen 400: %s", req, w.Code, w.Body)
+			}
+		}
+		checkLedger(t)
+	})
+}
+
+func TestUpdateProductUnit(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		_, shop, product := testCatalog(t, 10)
+		owner := models.User{ID: shop.UserID, Role: models.RoleShop}
+
+		pc := &ProductController{}
+		r := asUser(owner)
+		r.PUT("/products/:id", pc.UpdateProduct)
+		r.POST("/products/import", pc.ImportProducts)
+		put := func(id uint, body string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/products/%d", id), strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+			return w
+		}
+
+		// Açılış stoğu deftere adet olarak yazıldı; birim değişirse anlamı değişirdi
+		if w := put(product.ID, `{"name":"Ekmek","price":"10.00","stock":10,"unit":"kg"}`); w.Code != http.StatusConflict {
+			t.Errorf("hareketi olan ürün: yanıt kodu %d, beklenen 409: %s", w.Code, w.Body)
+		}
+		w := postImport(r, fmt.Sprintf("id,sku,name,price,unit\n%d,,,,kg\n", product.ID))
+		if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), "birimi değiştirilemez") {
+			t.Errorf("içe aktarma: yanıt kodu %d, beklenen 422: %s", w.Code, w.Body)
+		}
+		var stored models.Product
+		config.DB.First(&stored, product.ID)
+		if stored.Unit != models.UnitPiece {
+			t.Errorf("ürün birimi %s olarak değişti", stored.Unit)
+		}
+
+		// Henüz hareketi ve siparişi olmayan ürünün birimi değişebilir
+		fresh := models.Product{ShopID: shop.ID, Name: "Peynir", Price: 45000, Currency: money.DefaultCurrency, Unit: models.UnitPiece, IsActive: true}
+		fresh.ApplyUnitDefaults()
+		if err := config.DB.Create(&fresh).Error; err != nil {
+			t.Fatal(err)
+		}
+		if w := put(fresh.ID, `{"name":"Peynir","price":"450.00","stock":2.5,"unit":"kg"}`); w.Code != http.StatusOK {
+			t.Fatalf("yeni ürün: yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		var updated models.Product
+		config.DB.First(&updated, fresh.ID)
+		if updated.Unit != models.UnitKg || updated.Stock != 2.5 || updated.QuantityStep != 0.1 {
+			t.Errorf("ürün beklenen gibi değil: %+v", updated)
+		}
+		// Girilen stok deftere yazıldıktan sonra birim artık değişemez
+		if w := put(fresh.ID, `{"name":"Peynir","price":"450.00","stock":2.5,"unit":"g"}`); w.Code != http.StatusConflict {
+			t.Errorf("ikinci birim değişikliği: yanıt kodu %d, beklenen 409: %s", w.Code, w.Body)
+		}
+		checkLedger(t)
+	})
+}
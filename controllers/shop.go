@@ -3,6 +3,7 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"tradesman-api/config"
 	"tradesman-api/middleware"
 	"tradesman-api/models"
@@ -17,23 +18,82 @@ type CreateShopRequest struct {
 	Description string `json:"description"`
 	Address     string `json:"address"`
 	Phone       string `json:"phone"`
+	// Stoğu biten ürünleri müşteri listelerinden gizle. Oluştururken verilmezse
+	// false, güncellerken değişmez.
+	HideOutOfStock *bool `json:"hide_out_of_stock"`
+	// Yalnızca e-postası veya telefonu doğrulanmış müşterilerden sipariş kabul et. Oluştururken
+	// verilmezse false, güncellerken değişmez.
+	RequireVerifiedCustomers *bool `json:"require_verified_customers"`
+}
+
+// Dükkan sahibinin herkese açık bilgileri; e-posta, telefon ve hesap durumu
+// dükkan yanıtlarında gösterilmez.
+type ShopOwner struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// Yanıtlarda dönen dükkan: models.Shop'un user alanı ShopOwner ile gölgelenir
+type PublicShop struct {
+	models.Shop
+	User ShopOwner `json:"user"`
+}
+
+func publicShop(shop models.Shop) PublicShop {
+	return PublicShop{Shop: shop, User: ShopOwner{ID: shop.User.ID, Name: shop.User.Name}}
+}
+
+func publicShops(shops []models.Shop) []PublicShop {
+	result := make([]PublicShop, len(shops))
+	for i, shop := range shops {
+		result[i] = publicShop(shop)
+	}
+	return result
+}
+
+var shopListOptions = listOptions{
+	table: "shops",
+	sorts: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"created_at": "created_at",
+	},
+	defaultSort: "id",
 }
 
 // @Summary Tüm Esnafları Listele
-// @Description Aktif olan tüm esnafları listeler
+// @Description Aktif olan esnafları sayfalı olarak listeler
 // @Tags Shops
 // @Produce json
+// @Param page query int false "Sayfa numarası (varsayılan 1)"
+// @Param per_page query int false "Sayfa başına kayıt (varsayılan 20, en fazla 100)"
+// @Param sort query string false "Sıralama: id, name, created_at (azalan için başına -)"
+// @Param q query string false "Esnaf adında arama"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Router /shops [get]
 func (sc *ShopController) GetShops(c *gin.Context) {
+	lq, err := parseListQuery(c, shopListOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := config.DB.Model(&models.Shop{}).Where("is_active = ?", true)
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		query = query.Where(`LOWER(name) LIKE ? ESCAPE '\'`, containsPattern(q))
+	}
+
 	var shops []models.Shop
-	if err := config.DB.Preload("User").Where("is_active = ?", true).Find(&shops).Error; err != nil {
+	pagination, err := findPage(c, query, lq, &shops, "User")
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Esnaflar getirilemedi"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"shops": shops,
+		"shops":      publicShops(shops),
+		"pagination": pagination,
 	})
 }
 
@@ -43,19 +103,24 @@ func (sc *ShopController) GetShops(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Esnaf ID"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /shops/{id} [get]
 func (sc *ShopController) GetShop(c *gin.Context) {
-	id := c.Param("id")
+	shopID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz dükkan ID"})
+		return
+	}
 
 	var shop models.Shop
-	if err := config.DB.Preload("User").Preload("Products", "is_active = ?", true).First(&shop, id).Error; err != nil {
+	if err := config.DB.Preload("User").Preload("Products", visibleProducts).First(&shop, shopID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Esnaf bulunamadı"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"shop": shop,
+		"shop": publicShop(shop),
 	})
 }
 
@@ -101,6 +166,12 @@ func (sc *ShopController) CreateShop(c *gin.Context) {
 		Phone:       req.Phone,
 		IsActive:    true,
 	}
+	if req.HideOutOfStock != nil {
+		shop.HideOutOfStock = *req.HideOutOfStock
+	}
+	if req.RequireVerifiedCustomers != nil {
+		shop.RequireVerifiedCustomers = *req.RequireVerifiedCustomers
+	}
 
 	if err := config.DB.Create(&shop).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Dükkan oluşturulamadı"})
@@ -112,7 +183,7 @@ func (sc *ShopController) CreateShop(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Dükkan başarıyla oluşturuldu",
-		"shop":    shop,
+		"shop":    publicShop(shop),
 	})
 }
 
@@ -159,6 +230,12 @@ func (sc *ShopController) UpdateShop(c *gin.Context) {
 	shop.Description = req.Description
 	shop.Address = req.Address
 	shop.Phone = req.Phone
+	if req.HideOutOfStock != nil {
+		shop.HideOutOfStock = *req.HideOutOfStock
+	}
+	if req.RequireVerifiedCustomers != nil {
+		shop.RequireVerifiedCustomers = *req.RequireVerifiedCustomers
+	}
 
 	if err := config.DB.Save(&shop).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Dükkan güncellenemedi"})
@@ -169,20 +246,31 @@ func (sc *ShopController) UpdateShop(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Dükkan başarıyla güncellendi",
-		"shop":    shop,
+		"shop":    publicShop(shop),
 	})
 }
 
 // @Summary Esnafın Ürünlerini Listele
-// @Description Belirli bir esnafın ürünlerini listeler
+// @Description Belirli bir esnafın ürünlerini sayfalı olarak listeler
 // @Tags Shops
 // @Produce json
 // @Param id path int true "Esnaf ID"
+// @Param page query int false "Sayfa numarası (varsayılan 1)"
+// @Param per_page query int false "Sayfa başına kayıt (varsayılan 20, en fazla 100)"
+// @Param sort query string false "Sıralama: id, name, price, stock, created_at (azalan için başına -)"
+// @Param min_price query number false "En düşük fiyat"
+// @Param max_price query number false "En yüksek fiyat"
+// @Param in_stock query bool false "Sadece stokta olanlar (true) veya tükenenler (false)"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Router /shops/{id}/products [get]
 func (sc *ShopController) GetShopProducts(c *gin.Context) {
-	shopID := c.Param("id")
+	shopID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz dükkan ID"})
+		return
+	}
 
 	var shop models.Shop
 	if err := config.DB.First(&shop, shopID).Error; err != nil {
@@ -190,15 +278,29 @@ func (sc *ShopController) GetShopProducts(c *gin.Context) {
 		return
 	}
 
+	lq, err := parseListQuery(c, productListOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query, err := filterProducts(c, visibleProducts(config.DB.Model(&models.Product{})).Where("shop_id = ?", shop.ID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var products []models.Product
-	if err := config.DB.Where("shop_id = ? AND is_active = ?", shopID, true).Find(&products).Error; err != nil {
+	pagination, err := findPage(c, query, lq, &products)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Ürünler getirilemedi"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"shop_id":   shop.ID,
-		"shop_name": shop.Name,
-		"products":  products,
+		"shop_id":    shop.ID,
+		"shop_name":  shop.Name,
+		"products":   products,
+		"pagination": pagination,
 	})
 }
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"tradesman-api/config"
+	"tradesman-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetShopHidesOwnerAccount(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		_, shop, _ := testCatalog(t, 10)
+
+		number, now := "+905321112233", time.Now()
+		err := config.DB.Model(&models.User{}).Where("id = ?", shop.UserID).Updates(map[string]interface{}{
+			"phone_e164":        number,
+			"email_verified_at": now,
+			"is_suspended":      true,
+			"suspended_at":      now,
+		}).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sc := &ShopController{}
+		r := gin.New()
+		r.GET("/shops/:id", sc.GetShop)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/shops/%d", shop.ID), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+
+		var resp struct {
+			Shop struct {
+				User map[string]interface{} `json:"user"`
+			} `json:"shop"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		for _, field := range []string{"email", "phone", "phone_e164", "email_verified_at", "is_suspended", "suspended_at", "role"} {
+			if _, ok := resp.Shop.User[field]; ok {
+				t.Errorf("dükkan sahibinin %s alanı yanıtta: %s", field, w.Body)
+			}
+		}
+		if resp.Shop.User["name"] != "Esnaf" {
+			t.Errorf("dükkan sahibinin adı %v, beklenen Esnaf", resp.Shop.User["name"])
+		}
+	})
+}
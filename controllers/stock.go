@@ -0,0 +1,587 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"tradesman-api/config"
+	"tradesman-api/events"
+	"tradesman-api/inventory"
+	"tradesman-api/middleware"
+	"tradesman-api/models"
+	"tradesman-api/webhooks"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ReceiveStockRequest struct {
+	VariantID *uint   `json:"variant_id"`                                         // Aktif çeşidi olan ürünlerde zorunlu
+	Quantity  float64 `json:"quantity" binding:"required,gt=0"`                   // Gelen miktar, ürünün birimi cinsinden
+	Reason    string  `json:"reason" binding:"max=500" example:"Sabah teslimatı"` // Ör. tedarikçi, irsaliye no
+}
+
+type AdjustStockRequest struct {
+	VariantID *uint                    `json:"variant_id"`                                   // Aktif çeşidi olan ürünlerde zorunlu
+	Type      models.StockMovementType `json:"type" binding:"required" example:"adjustment"` // adjustment (sayım) veya spoilage (fire)
+	Count     *float64                 `json:"count" binding:"omitempty,gte=0"`              // adjustment: rafta sayılan miktar
+	Quantity  float64                  `json:"quantity" binding:"gte=0"`                     // spoilage: bozulan / atılan miktar
+	Reason    string                   `json:"reason" binding:"required,max=500" example:"Ay sonu sayımı"`
+}
+
+var stockMovementListOptions = listOptions{
+	table: "stock_movements",
+	sorts: map[string]string{
+		"id":         "id",
+		"created_at": "created_at",
+	},
+	defaultSort: "-id",
+}
+
+// @Summary Mal Kabul
+// @Description Gelen malı stoğa ekler ve stok defterine restock hareketi yazar (sadece ürün sahibi)
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Param receipt body ReceiveStockRequest true "Gelen mal"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /products/{id}/stock/receive [post]
+func (pc *ProductController) ReceiveStock(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	var req ReceiveStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	movement := newStockMovement(c, product, req.VariantID, models.StockMovementRestock, req.Reason)
+	movement.Quantity = req.Quantity
+	if status, err := recordStockMovement(product, &movement); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Mal kabul stoğa işlendi",
+		"movement": movement,
+	})
+}
+
+// @Summary Stok Düzelt
+// @Description Sayım sonucunu (adjustment: count verilir, farkı kadar hareket yazılır) veya fireyi (spoilage: quantity kadar düşülür) stok defterine işler (sadece ürün sahibi). Sebep zorunludur.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Param adjustment body AdjustStockRequest true "Sayım veya fire"
+// @Success 201 {object} map[string]interface{}
+// @Success 200 {object} map[string]interface{} "Sayım stokla aynı, hareket yazılmadı"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /products/{id}/stock/adjust [post]
+func (pc *ProductController) AdjustStock(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	var req AdjustStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	movement := newStockMovement(c, product, req.VariantID, req.Type, req.Reason)
+	switch req.Type {
+	case models.StockMovementAdjustment:
+		if req.Count == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Sayım için count verilmeli"})
+			return
+		}
+	case models.StockMovementSpoilage:
+		if req.Quantity <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Fire için sıfırdan büyük quantity verilmeli"})
+			return
+		}
+		movement.Quantity = -req.Quantity
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz hareket tipi: " + string(req.Type) + " (adjustment, spoilage)"})
+		return
+	}
+
+	if req.Type == models.StockMovementSpoilage {
+		if status, err := recordStockMovement(product, &movement); err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{
+			"message":  "Fire stoktan düşüldü",
+			"movement": movement,
+		})
+		return
+	}
+
+	if status, err := validateStockTarget(product, movement.VariantID); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	count := models.RoundQuantity(*req.Count)
+	if err := product.Unit.ValidateStock(count); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var recorded bool
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		recorded, err = inventory.SetCount(tx, &movement, count)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Stok güncellenemedi"})
+		return
+	}
+	publishStockAlerts(movement.Alert)
+
+	if !recorded {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Sayım stokla aynı, düzeltme gerekmedi",
+			"stock":   count,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Sayım stoğa işlendi",
+		"movement": movement,
+	})
+}
+
+// @Summary Stok Hareketleri
+// @Description Ürünün ve çeşitlerinin stok defterini sayfalı olarak listeler (sadece ürün sahibi). Yanıttaki balances, her kalemin stok bakiyesini ve defter toplamını içerir.
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Param page query int false "Sayfa numarası (varsayılan 1)"
+// @Param per_page query int false "Sayfa başına kayıt (varsayılan 20, en fazla 100)"
+// @Param sort query string false "Sıralama: id, created_at (azalan için başına -, varsayılan -id)"
+// @Param type query string false "Hareket tipi, virgülle birden fazla verilebilir (sale, restock, adjustment, cancellation, spoilage)"
+// @Param variant_id query int false "Çeşit filtresi"
+// @Param from query string false "Bu tarihten sonraki hareketler (2006-01-02 veya RFC3339)"
+// @Param to query string false "Bu tarihe kadarki hareketler (2006-01-02 veya RFC3339)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /products/{id}/stock/movements [get]
+func (pc *ProductController) GetStockMovements(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	lq, err := parseListQuery(c, stockMovementListOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := config.DB.Model(&models.StockMovement{}).Where("product_id = ?", product.ID)
+
+	if types := c.Query("type"); types != "" {
+		var movementTypes []models.StockMovementType
+		for _, t := range strings.Split(types, ",") {
+			mt := models.StockMovementType(strings.TrimSpace(t))
+			if !mt.IsValid() {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz hareket tipi: " + string(mt)})
+				return
+			}
+			movementTypes = append(movementTypes, mt)
+		}
+		query = query.Where("type IN ?", movementTypes)
+	}
+
+	variantID, err := queryUint(c, "variant_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if variantID != nil {
+		query = query.Where("variant_id = ?", *variantID)
+	}
+
+	query, err = filterDateRange(c, query, "created_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var movements []models.StockMovement
+	pagination, err := findPage(c, query, lq, &movements)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Stok hareketleri getirilemedi"})
+		return
+	}
+
+	balances, err := stockBalances(product)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Stok bakiyeleri getirilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"movements":  movements,
+		"balances":   balances,
+		"pagination": pagination,
+	})
+}
+
+// Bir stok kaleminin (ürün veya çeşit) bakiyesi ve defter toplamı
+type stockBalance struct {
+	VariantID  *uint   `json:"variant_id"`
+	Stock      float64 `json:"stock"`
+	Ledger     float64 `json:"ledger"`
+	Consistent bool    `json:"consistent"`
+}
+
+// stockBalances ürünün ve çeşitlerinin stok kolonlarını defter toplamıyla
+// birlikte döner.
+func stockBalances(product models.Product) ([]stockBalance, error) {
+	var variants []models.ProductVariant
+	if err := config.DB.Where("product_id = ?", product.ID).Order("sort_order, id").Find(&variants).Error; err != nil {
+		return nil, err
+	}
+
+	var sums []struct {
+		VariantID *uint
+		Total     float64
+	}
+	err := config.DB.Model(&models.StockMovement{}).
+		Select("variant_id, SUM(quantity) AS total").
+		Where("product_id = ?", product.ID).
+		Group("variant_id").
+		Scan(&sums).Error
+	if err != nil {
+		return nil, err
+	}
+	ledger := make(map[uint]float64, len(sums))
+	for _, s := range sums {
+		var id uint
+		if s.VariantID != nil {
+			id = *s.VariantID
+		}
+		ledger[id] = models.RoundQuantity(s.Total)
+	}
+
+	balances := []stockBalance{{Stock: product.Stock, Ledger: ledger[0]}}
+	for i := range variants {
+		balances = append(balances, stockBalance{
+			VariantID: &variants[i].ID,
+			Stock:     variants[i].Stock,
+			Ledger:    ledger[variants[i].ID],
+		})
+	}
+	for i := range balances {
+		balances[i].Stock = models.RoundQuantity(balances[i].Stock)
+		balances[i].Consistent = balances[i].Stock == balances[i].Ledger
+	}
+	return balances, nil
+}
+
+// newStockMovement isteği yapan esnaf adına ürün için bir hareket hazırlar.
+func newStockMovement(c *gin.Context, product models.Product, variantID *uint, movementType models.StockMovementType, reason string) models.StockMovement {
+	actorID := middleware.GetUserID(c)
+	return models.StockMovement{
+		ShopID:    product.ShopID,
+		ProductID: product.ID,
+		VariantID: variantID,
+		Type:      movementType,
+		ActorID:   &actorID,
+		Reason:    strings.TrimSpace(reason),
+	}
+}
+
+// validateStockTarget hareketin çeşidini doğrular. Aktif çeşidi olan ürünlerde
+// stok çeşitlerde tutulduğundan çeşit verilmelidir.
+func validateStockTarget(product models.Product, variantID *uint) (int, error) {
+	if variantID != nil {
+		var count int64
+		config.DB.Model(&models.ProductVariant{}).Where("id = ? AND product_id = ?", *variantID, product.ID).Count(&count)
+		if count == 0 {
+			return http.StatusBadRequest, errors.New("Geçersiz çeşit: " + strconv.Itoa(int(*variantID)))
+		}
+		return 0, nil
+	}
+
+	var count int64
+	config.DB.Model(&models.ProductVariant{}).Where("product_id = ? AND is_active = ?", product.ID, true).Count(&count)
+	if count > 0 {
+		return http.StatusBadRequest, errors.New("Bu ürünün stoğu çeşitlerde tutuluyor, variant_id verilmeli")
+	}
+	return 0, nil
+}
+
+// recordStockMovement hareketin miktarını ve çeşidini doğrular ve deftere yazar.
+func recordStockMovement(product models.Product, movement *models.StockMovement) (int, error) {
+	if status, err := validateStockTarget(product, movement.VariantID); err != nil {
+		return status, err
+	}
+	if err := movement.ValidateQuantity(product.Unit); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return inventory.Record(tx, movement)
+	})
+	if errors.Is(err, inventory.ErrInsufficientStock) {
+		available, _ := inventory.Balance(config.DB, product.ID, movement.VariantID)
+		return http.StatusBadRequest, errors.New("Yetersiz stok. Mevcut: " + models.FormatQuantity(available) + " " + string(product.Unit))
+	}
+	if err != nil {
+		return http.StatusInternalServerError, errors.New("Stok güncellenemedi")
+	}
+	publishStockAlerts(movement.Alert)
+	return 0, nil
+}
+
+// recordStockCount ürünün veya çeşidin stoğunu girilen miktara eşitler ve farkı
+// deftere düzeltme hareketi olarak yazar. Oluşan stok uyarısı commit sonrası
+// publishStockAlerts ile yayınlanmak üzere döner. tx içinde çağrılmalıdır.
+func recordStockCount(tx *gorm.DB, product models.Product, variantID *uint, count float64, actorID uint, reason string) (*models.StockAlert, error) {
+	movement := models.StockMovement{
+		ShopID:    product.ShopID,
+		ProductID: product.ID,
+		VariantID: variantID,
+		Type:      models.StockMovementAdjustment,
+		ActorID:   &actorID,
+		Reason:    reason,
+	}
+	_, err := inventory.SetCount(tx, &movement, count)
+	return movement.Alert, err
+}
+
+// Düşük stok raporundaki kalem: çeşidi olmayan ürün veya aktif çeşit
+type LowStockItem struct {
+	ProductID        uint        `json:"product_id"`
+	VariantID        *uint       `json:"variant_id"`
+	Name             string      `json:"name"`
+	VariantName      string      `json:"variant_name,omitempty"`
+	SKU              *string     `json:"sku"`
+	Unit             models.Unit `json:"unit"`
+	Stock            float64     `json:"stock"`
+	ReorderThreshold float64     `json:"reorder_threshold"`
+	OutOfStock       bool        `json:"out_of_stock"`
+}
+
+// stockRatio stoğun yeniden sipariş eşiğine oranını döner. Eşik sıfırsa kalem
+// rapora yalnızca tükenince girer; oran 0 sayılır.
+func (item LowStockItem) stockRatio() float64 {
+	if item.ReorderThreshold <= 0 || item.Stock <= 0 {
+		return 0
+	}
+	return item.Stock / item.ReorderThreshold
+}
+
+const (
+	lowStockProductsQuery = `SELECT p.id AS product_id, NULL AS variant_id, p.name AS name, '' AS variant_name, p.sku AS sku,
+			p.unit AS unit, p.stock AS stock, p.reorder_threshold AS reorder_threshold
+		FROM products p
+		WHERE p.shop_id = ? AND p.is_active = ? AND p.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = p.id AND pv.is_active = ?)
+			AND (p.stock <= 0 OR p.stock <= p.reorder_threshold)`
+
+	lowStockVariantsQuery = `SELECT p.id AS product_id, v.id AS variant_id, p.name AS name, v.name AS variant_name, v.sku AS sku,
+			p.unit AS unit, v.stock AS stock, p.reorder_threshold AS reorder_threshold
+		FROM product_variants v JOIN products p ON p.id = v.product_id
+		WHERE p.shop_id = ? AND p.is_active = ? AND p.deleted_at IS NULL AND v.is_active = ?
+			AND (v.stock <= 0 OR v.stock <= p.reorder_threshold)`
+)
+
+var stockAlertListOptions = listOptions{
+	table: "stock_alerts",
+	sorts: map[string]string{
+		"id":         "id",
+		"created_at": "created_at",
+	},
+	defaultSort: "-id",
+}
+
+// @Summary Düşük Stok Raporu
+// @Description Dükkanın aktif ürün ve çeşitlerinden stoğu biten veya yeniden sipariş eşiğine (reorder_threshold) düşenleri listeler (sadece esnaflar). Tükenenler önce, sonra stoğun eşiğe oranına göre sıralanır; eşitlikte ürün ve çeşit ID sırası kullanılır.
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /products/low-stock [get]
+func (pc *ProductController) GetLowStock(c *gin.Context) {
+	shop, ok := loadOwnShop(c)
+	if !ok {
+		return
+	}
+
+	var items []LowStockItem
+	for _, query := range []string{lowStockProductsQuery, lowStockVariantsQuery} {
+		var found []LowStockItem
+		if err := config.DB.Raw(query, shop.ID, true, true).Scan(&found).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Düşük stok raporu oluşturulamadı"})
+			return
+		}
+		items = append(items, found...)
+	}
+
+	for i := range items {
+		items[i].Stock = models.RoundQuantity(items[i].Stock)
+		items[i].ReorderThreshold = models.RoundQuantity(items[i].ReorderThreshold)
+		items[i].OutOfStock = items[i].Stock <= 0
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.OutOfStock != b.OutOfStock {
+			return a.OutOfStock
+		}
+		if ra, rb := a.stockRatio(), b.stockRatio(); ra != rb {
+			return ra < rb
+		}
+		if a.ProductID != b.ProductID {
+			return a.ProductID < b.ProductID
+		}
+		return b.VariantID != nil && (a.VariantID == nil || *a.VariantID < *b.VariantID)
+	})
+	if items == nil {
+		items = []LowStockItem{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items": items,
+		"count": len(items),
+	})
+}
+
+// @Summary Stok Uyarıları
+// @Description Dükkanın stok uyarılarını sayfalı olarak listeler (sadece esnaflar). Bir stok çıkışı (satış, fire, sayım) kalemi yeniden sipariş eşiğinin üstünden eşiğe düşürdüğünde low_stock, sıfıra düşürdüğünde out_of_stock uyarısı oluşur. Her uyarı stock.alert olayı olarak canlı akışa ve webhook'lara gönderilir, esnafa stock_alert bildirimi gider.
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Sayfa numarası (varsayılan 1)"
+// @Param per_page query int false "Sayfa başına kayıt (varsayılan 20, en fazla 100)"
+// @Param sort query string false "Sıralama: id, created_at (azalan için başına -, varsayılan -id)"
+// @Param kind query string false "Uyarı tipi: low_stock veya out_of_stock"
+// @Param product_id query int false "Ürün filtresi"
+// @Param from query string false "Bu tarihten sonraki uyarılar (2006-01-02 veya RFC3339)"
+// @Param to query string false "Bu tarihe kadarki uyarılar (2006-01-02 veya RFC3339)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /products/stock-alerts [get]
+func (pc *ProductController) GetStockAlerts(c *gin.Context) {
+	shop, ok := loadOwnShop(c)
+	if !ok {
+		return
+	}
+
+	lq, err := parseListQuery(c, stockAlertListOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := config.DB.Model(&models.StockAlert{}).Where("shop_id = ?", shop.ID)
+
+	if kind := models.StockAlertKind(c.Query("kind")); kind != "" {
+		if !kind.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz uyarı tipi: " + string(kind) + " (low_stock, out_of_stock)"})
+			return
+		}
+		query = query.Where("kind = ?", kind)
+	}
+
+	productID, err := queryUint(c, "product_id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if productID != nil {
+		query = query.Where("product_id = ?", *productID)
+	}
+
+	query, err = filterDateRange(c, query, "created_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var alerts []models.StockAlert
+	pagination, err := findPage(c, query, lq, &alerts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Stok uyarıları getirilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alerts":     alerts,
+		"pagination": pagination,
+	})
+}
+
+// StockAlertEvent akışta ve webhook'larda gönderilen stok uyarısı olayının verisi.
+type StockAlertEvent struct {
+	Alert       models.StockAlert `json:"alert"`
+	ProductName string            `json:"product_name"`
+	VariantName string            `json:"variant_name,omitempty"`
+	Unit        models.Unit       `json:"unit"`
+}
+
+// publishStockAlerts stok hareketlerinin oluşturduğu uyarıları olay veri yoluna
+// yayınlar, dükkanın webhook'ları için kuyruğa yazar ve dükkan sahibine
+// bildirir. nil uyarılar atlanır. Transaction commit edildikten sonra
+// çağrılmalıdır.
+func publishStockAlerts(alerts ...*models.StockAlert) {
+	for _, alert := range alerts {
+		if alert != nil {
+			publishStockAlert(*alert)
+		}
+	}
+}
+
+func publishStockAlert(alert models.StockAlert) {
+	var product models.Product
+	err := config.DB.Unscoped().Preload("Shop").First(&product, alert.ProductID).Error
+	if err != nil {
+		log.Printf("stok uyarısı yayınlanamadı (uyarı %d): %v", alert.ID, err)
+		return
+	}
+	data := StockAlertEvent{Alert: alert, ProductName: product.Name, Unit: product.Unit}
+	if alert.VariantID != nil {
+		var names []string
+		config.DB.Unscoped().Model(&models.ProductVariant{}).Where("id = ?", *alert.VariantID).Pluck("name", &names)
+		if len(names) > 0 {
+			data.VariantName = names[0]
+		}
+	}
+
+	e := events.Default.Publish(events.Event{
+		Type:   events.StockAlert,
+		ShopID: alert.ShopID,
+		Data:   data,
+	})
+	if _, err := webhooks.Enqueue(config.DB, e); err != nil {
+		log.Printf("stok uyarısı webhook kuyruğuna yazılamadı (uyarı %d): %v", alert.ID, err)
+	}
+	notifyStockAlert(product.Shop.UserID, data)
+}
@@ -0,0 +1,272 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"tradesman-api/config"
+	"tradesman-api/middleware"
+	"tradesman-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserController struct{}
+
+type UpdateUserRoleRequest struct {
+	Role models.UserRole `json:"role" binding:"required,oneof=admin shop customer"`
+}
+
+var userListOptions = listOptions{
+	table: "users",
+	sorts: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"email":      "email",
+		"created_at": "created_at",
+	},
+	defaultSort: "id",
+}
+
+// @Summary Kullanıcıları Listele
+// @Description Kullanıcıları listeler ve arar (sadece admin)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "İsim, email veya telefonda arama"
+// @Param role query string false "Rol filtresi (admin, shop, customer)"
+// @Param suspended query bool false "Askı durumu filtresi"
+// @Param page query int false "Sayfa numarası (varsayılan 1)"
+// @Param per_page query int false "Sayfa başına kayıt (varsayılan 20, en fazla 100)"
+// @Param sort query string false "Sıralama: id, name, email, created_at (azalan için başına -)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/users [get]
+func (uc *UserController) GetUsers(c *gin.Context) {
+	lq, err := parseListQuery(c, userListOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := config.DB.Model(&models.User{})
+
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		like := containsPattern(q)
+		query = query.Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\' OR phone LIKE ? ESCAPE '\'`, like, like, like)
+	}
+
+	if role := c.Query("role"); role != "" {
+		if !models.UserRole(role).IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz rol"})
+			return
+		}
+		query = query.Where("role = ?", role)
+	}
+
+	if suspended := c.Query("suspended"); suspended != "" {
+		isSuspended, err := strconv.ParseBool(suspended)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz suspended değeri"})
+			return
+		}
+		query = query.Where("is_suspended = ?", isSuspended)
+	}
+
+	var users []models.User
+	pagination, err := findPage(c, query, lq, &users)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcılar getirilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":      users,
+		"pagination": pagination,
+	})
+}
+
+// @Summary Kullanıcı Detayı
+// @Description Belirli bir kullanıcının detaylarını getirir (sadece admin)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Kullanıcı ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/users/{id} [get]
+func (uc *UserController) GetUser(c *gin.Context) {
+	user, ok := uc.findUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": user,
+	})
+}
+
+// @Summary Kullanıcı Rolünü Değiştir
+// @Description Kullanıcıyı admin yapar veya rolünü düşürür (sadece admin)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Kullanıcı ID"
+// @Param role body UpdateUserRoleRequest true "Yeni rol"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/users/{id}/role [put]
+func (uc *UserController) UpdateUserRole(c *gin.Context) {
+	user, ok := uc.findUser(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if user.ID == middleware.GetUserID(c) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kendi rolünüzü değiştiremezsiniz"})
+		return
+	}
+
+	user.Role = req.Role
+	if err := config.DB.Model(&user).Update("role", user.Role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcı rolü güncellenemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Kullanıcı rolü güncellendi",
+		"user":    user,
+	})
+}
+
+// @Summary Kullanıcıyı Askıya Al
+// @Description Kullanıcıyı askıya alır ve tüm oturumlarını sonlandırır (sadece admin)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Kullanıcı ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/users/{id}/suspend [post]
+func (uc *UserController) SuspendUser(c *gin.Context) {
+	user, ok := uc.findUser(c)
+	if !ok {
+		return
+	}
+
+	if user.ID == middleware.GetUserID(c) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kendi hesabınızı askıya alamazsınız"})
+		return
+	}
+
+	now := time.Now()
+	user.IsSuspended = true
+	user.SuspendedAt = &now
+	if err := config.DB.Model(&user).Updates(map[string]interface{}{"is_suspended": true, "suspended_at": now}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcı askıya alınamadı"})
+		return
+	}
+
+	if err := revokeUserRefreshTokens(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcının oturumları sonlandırılamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Kullanıcı askıya alındı",
+		"user":    user,
+	})
+}
+
+// @Summary Kullanıcı Askısını Kaldır
+// @Description Askıya alınmış kullanıcıyı yeniden aktif eder (sadece admin)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Kullanıcı ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/users/{id}/unsuspend [post]
+func (uc *UserController) UnsuspendUser(c *gin.Context) {
+	user, ok := uc.findUser(c)
+	if !ok {
+		return
+	}
+
+	user.IsSuspended = false
+	user.SuspendedAt = nil
+	if err := config.DB.Model(&user).Updates(map[string]interface{}{"is_suspended": false, "suspended_at": nil}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcı askısı kaldırılamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Kullanıcı askısı kaldırıldı",
+		"user":    user,
+	})
+}
+
+// @Summary Kullanıcı Sil
+// @Description Kullanıcıyı siler (soft delete) ve tüm oturumlarını sonlandırır (sadece admin)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Kullanıcı ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/users/{id} [delete]
+func (uc *UserController) DeleteUser(c *gin.Context) {
+	user, ok := uc.findUser(c)
+	if !ok {
+		return
+	}
+
+	if user.ID == middleware.GetUserID(c) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kendi hesabınızı silemezsiniz"})
+		return
+	}
+
+	// Soft delete
+	if err := config.DB.Delete(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcı silinemedi"})
+		return
+	}
+
+	if err := revokeUserRefreshTokens(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Kullanıcının oturumları sonlandırılamadı"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Kullanıcı başarıyla silindi",
+	})
+}
+
+func (uc *UserController) findUser(c *gin.Context) (models.User, bool) {
+	var user models.User
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz kullanıcı ID"})
+		return user, false
+	}
+
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Kullanıcı bulunamadı"})
+		return user, false
+	}
+
+	return user, true
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"tradesman-api/config"
+	"tradesman-api/middleware"
+	"tradesman-api/models"
+)
+
+func TestUpdateUserRoleRequiresAdmin(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		admin := testUser(t, "admin@example.com", models.RoleAdmin)
+		shop := testUser(t, "esnaf@example.com", models.RoleShop)
+		customer := testUser(t, "musteri@example.com", models.RoleCustomer)
+
+		uc := &UserController{}
+		r := authRouter()
+		r.PUT("/admin/users/:id/role", middleware.AuthMiddleware(), middleware.RequireRole(models.RoleAdmin), uc.UpdateUserRole)
+		path := fmt.Sprintf("/admin/users/%d/role", customer.ID)
+		body := UpdateUserRoleRequest{Role: models.RoleAdmin}
+
+		for _, user := range []models.User{customer, shop} {
+			tokens := login(t, r, user.Email)
+			if w := withBearer(r, http.MethodPut, path, tokens.Token, body); w.Code != http.StatusForbidden {
+				t.Errorf("%s rolüyle yanıt kodu %d, beklenen 403: %s", user.Role, w.Code, w.Body)
+			}
+		}
+		var stored models.User
+		config.DB.First(&stored, customer.ID)
+		if stored.Role != models.RoleCustomer {
+			t.Fatalf("rol %q olarak değişti", stored.Role)
+		}
+
+		tokens := login(t, r, admin.Email)
+		body.Role = models.RoleShop
+		if w := withBearer(r, http.MethodPut, path, tokens.Token, body); w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+		config.DB.First(&stored, customer.ID)
+		if stored.Role != models.RoleShop {
+			t.Errorf("rol %q, beklenen %q", stored.Role, models.RoleShop)
+		}
+	})
+}
+
+func TestSuspendedUserTokenRejected(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		admin := testUser(t, "admin@example.com", models.RoleAdmin)
+		customer := testUser(t, "musteri@example.com", models.RoleCustomer)
+
+		uc := &UserController{}
+		r := authRouter()
+		r.POST("/admin/users/:id/suspend", middleware.AuthMiddleware(), middleware.RequireRole(models.RoleAdmin), uc.SuspendUser)
+
+		session := login(t, r, customer.Email)
+		if w := withBearer(r, http.MethodGet, "/auth/me", session.Token, nil); w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+
+		tokens := login(t, r, admin.Email)
+		w := withBearer(r, http.MethodPost, fmt.Sprintf("/admin/users/%d/suspend", customer.ID), tokens.Token, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("yanıt kodu %d, beklenen 200: %s", w.Code, w.Body)
+		}
+
+		// Süresi dolmamış access token da askıya alınan hesapta kullanılamaz
+		if w := withBearer(r, http.MethodGet, "/auth/me", session.Token, nil); w.Code != http.StatusForbidden {
+			t.Errorf("askıdaki hesabın access token yanıt kodu %d, beklenen 403: %s", w.Code, w.Body)
+		}
+		if _, code := refresh(t, r, session.RefreshToken); code != http.StatusUnauthorized {
+			t.Errorf("askıdaki hesabın yenileme yanıt kodu %d, beklenen 401", code)
+		}
+	})
+}
+
+func TestGetUsersSearchEscapesWildcards(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		admin := testUser(t, "admin@example.com", models.RoleAdmin)
+		for _, email := range []string{"ali_veli@example.com", "alixveli@example.com", `yuzde%100\indirim@example.com`} {
+			testUser(t, email, models.RoleCustomer)
+		}
+
+		uc := &UserController{}
+		r := asUser(admin)
+		r.GET("/admin/users", uc.GetUsers)
+
+		for q, want := range map[string][]string{
+			"_veli":  {"ali_veli@example.com"},
+			"%":      {`yuzde%100\indirim@example.com`},
+			`%100\`:  {`yuzde%100\indirim@example.com`},
+			"ALI":    {"ali_veli@example.com", "alixveli@example.com"},
+			"x%veli": nil,
+		} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/users?sort=email&q="+url.QueryEscape(q), nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("q=%s: yanıt kodu %d: %s", q, w.Code, w.Body)
+			}
+			var resp struct {
+				Users []models.User `json:"users"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, u := range resp.Users {
+				got = append(got, u.Email)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("q=%s: %v, beklenen %v", q, got, want)
+			}
+		}
+	})
+}
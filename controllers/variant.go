@@ -0,0 +1,572 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"tradesman-api/config"
+	"tradesman-api/middleware"
+	"tradesman-api/models"
+	"tradesman-api/money"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type VariantRequest struct {
+	Name      string       `json:"name" binding:"required,max=100"`
+	SKU       string       `json:"sku" binding:"required,max=64"`                                      // Dükkan içinde benzersiz
+	Price     money.Amount `json:"price" binding:"required,gt=0" swaggertype:"number" example:"15.00"` // Çeşidin birim fiyatı
+	Stock     float64      `json:"stock" binding:"gte=0"`                                              // Ürünün birimi cinsinden
+	IsActive  *bool        `json:"is_active"`                                                          // Oluştururken verilmezse true, güncellerken değişmez
+	SortOrder int          `json:"sort_order"`
+}
+
+type OptionGroupRequest struct {
+	Name      string          `json:"name" binding:"required,max=100"`
+	MinSelect int             `json:"min_select" binding:"gte=0"`
+	MaxSelect *int            `json:"max_select" binding:"omitempty,gte=0"` // Verilmezse 1, 0 sınırsız
+	SortOrder int             `json:"sort_order"`
+	Options   []OptionRequest `json:"options" binding:"dive"`
+}
+
+type OptionRequest struct {
+	ID            uint         `json:"id"` // Güncellemede mevcut seçeneği korumak için; listede olmayan seçenekler silinir
+	Name          string       `json:"name" binding:"required,max=100"`
+	PriceModifier money.Amount `json:"price_modifier" swaggertype:"number" example:"10.00"` // Birim fiyata eklenir, negatif olabilir
+	IsActive      *bool        `json:"is_active"`                                           // Verilmezse true
+	SortOrder     int          `json:"sort_order"`
+}
+
+// @Summary Ürün Çeşitleri ve Seçenekleri
+// @Description Ürünün pasifler dahil tüm çeşitlerini ve seçenek gruplarını getirir (sadece ürün sahibi)
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /products/{id}/variants [get]
+func (pc *ProductController) GetProductVariants(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	var variants []models.ProductVariant
+	if err := config.DB.Where("product_id = ?", product.ID).Order("sort_order, id").Find(&variants).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Çeşitler getirilemedi"})
+		return
+	}
+
+	groups, err := loadOptionGroups(config.DB, product.ID, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Seçenek grupları getirilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"variants":      variants,
+		"option_groups": groups,
+	})
+}
+
+// @Summary Çeşit Ekle
+// @Description Ürüne kendi SKU, fiyat ve stoğu olan bir çeşit ekler (sadece ürün sahibi). Aktif çeşidi olan ürünler çeşit seçilerek sipariş edilir.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Param variant body VariantRequest true "Çeşit bilgileri"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /products/{id}/variants [post]
+func (pc *ProductController) CreateVariant(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	var req VariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	variant := models.ProductVariant{ProductID: product.ID, ShopID: product.ShopID, IsActive: true}
+	if status, err := applyVariantRequest(&variant, product, req); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Çeşit sıfır stokla oluşturulur; girilen stok deftere açılış hareketi olarak yazılır
+	stock := variant.Stock
+	variant.Stock = 0
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := createWithActive(tx, &variant, variant.IsActive); err != nil {
+			return err
+		}
+		// Açılış stoğu giriş hareketidir, uyarı oluşturmaz
+		_, err := recordStockCount(tx, product, &variant.ID, stock, middleware.GetUserID(c), "Açılış stoğu")
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Çeşit oluşturulamadı"})
+		return
+	}
+	variant.Stock = stock
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Çeşit başarıyla oluşturuldu",
+		"variant": variant,
+	})
+}
+
+// @Summary Çeşit Güncelle
+// @Description Ürün çeşidini günceller (sadece ürün sahibi)
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Param variantId path int true "Çeşit ID"
+// @Param variant body VariantRequest true "Çeşit bilgileri"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /products/{id}/variants/{variantId} [put]
+func (pc *ProductController) UpdateVariant(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	variant, ok := findVariant(c, product)
+	if !ok {
+		return
+	}
+
+	var req VariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if status, err := applyVariantRequest(&variant, product, req); err != nil {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	var alert *models.StockAlert
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("stock").Save(&variant).Error; err != nil {
+			return err
+		}
+		var err error
+		alert, err = recordStockCount(tx, product, &variant.ID, variant.Stock, middleware.GetUserID(c), "Çeşit güncellemesinde girilen stok")
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Çeşit güncellenemedi"})
+		return
+	}
+	publishStockAlerts(alert)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Çeşit başarıyla güncellendi",
+		"variant": variant,
+	})
+}
+
+// @Summary Çeşit Sil
+// @Description Ürün çeşidini siler (sadece ürün sahibi). Geçmiş siparişler çeşidin adını ve SKU'sunu korur.
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Param variantId path int true "Çeşit ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /products/{id}/variants/{variantId} [delete]
+func (pc *ProductController) DeleteVariant(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	variant, ok := findVariant(c, product)
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Delete(&variant).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Çeşit silinemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Çeşit başarıyla silindi",
+	})
+}
+
+// @Summary Seçenek Grubu Ekle
+// @Description Ürüne seçenekleriyle birlikte bir seçenek grubu ekler (ör. "Ekstralar": Ekstra peynir +10.00). Siparişte gruptan en az min_select, en fazla max_select seçenek seçilir (sadece ürün sahibi).
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Param group body OptionGroupRequest true "Seçenek grubu bilgileri"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /products/{id}/option-groups [post]
+func (pc *ProductController) CreateOptionGroup(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	var req OptionGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group := models.OptionGroup{ProductID: product.ID}
+	if err := applyOptionGroupRequest(&group, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := config.DB.Transaction(func(tx *gorm.DB) error { return saveOptionGroup(tx, &group) }); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Seçenek grubu oluşturulamadı"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Seçenek grubu başarıyla oluşturuldu",
+		"option_group": group,
+	})
+}
+
+// @Summary Seçenek Grubu Güncelle
+// @Description Seçenek grubunu ve seçeneklerini günceller (sadece ürün sahibi). id'si verilen seçenekler güncellenir, id'siz olanlar eklenir, listede olmayanlar silinir.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Param groupId path int true "Seçenek grubu ID"
+// @Param group body OptionGroupRequest true "Seçenek grubu bilgileri"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /products/{id}/option-groups/{groupId} [put]
+func (pc *ProductController) UpdateOptionGroup(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	group, ok := findOptionGroup(c, product)
+	if !ok {
+		return
+	}
+
+	var req OptionGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := applyOptionGroupRequest(&group, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := config.DB.Transaction(func(tx *gorm.DB) error { return saveOptionGroup(tx, &group) }); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Seçenek grubu güncellenemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Seçenek grubu başarıyla güncellendi",
+		"option_group": group,
+	})
+}
+
+// @Summary Seçenek Grubu Sil
+// @Description Seçenek grubunu seçenekleriyle birlikte siler (sadece ürün sahibi). Geçmiş siparişler seçilen seçeneklerin kopyasını korur.
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Ürün ID"
+// @Param groupId path int true "Seçenek grubu ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /products/{id}/option-groups/{groupId} [delete]
+func (pc *ProductController) DeleteOptionGroup(c *gin.Context) {
+	product, ok := loadOwnedProduct(c)
+	if !ok {
+		return
+	}
+
+	group, ok := findOptionGroup(c, product)
+	if !ok {
+		return
+	}
+
+	// SQLite'ta yabancı anahtarlar kapalı olabileceğinden seçenekler ayrıca silinir
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("option_group_id = ?", group.ID).Delete(&models.ProductOption{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&group).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Seçenek grubu silinemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Seçenek grubu başarıyla silindi",
+	})
+}
+
+// loadOwnedProduct yoldaki ürünü getirir ve isteği yapan esnafın olup olmadığını
+// kontrol eder; hata durumunda yanıtı yazar.
+func loadOwnedProduct(c *gin.Context) (models.Product, bool) {
+	var product models.Product
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz ürün ID"})
+		return product, false
+	}
+
+	if err := config.DB.Preload("Shop").First(&product, productID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ürün bulunamadı"})
+		return product, false
+	}
+
+	if product.Shop.UserID != middleware.GetUserID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bu ürünü güncelleme yetkiniz yok"})
+		return product, false
+	}
+	return product, true
+}
+
+func findVariant(c *gin.Context, product models.Product) (models.ProductVariant, bool) {
+	var variant models.ProductVariant
+	variantID, err := strconv.ParseUint(c.Param("variantId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz çeşit ID"})
+		return variant, false
+	}
+
+	if err := config.DB.Where("product_id = ?", product.ID).First(&variant, variantID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Çeşit bulunamadı"})
+		return variant, false
+	}
+	return variant, true
+}
+
+func findOptionGroup(c *gin.Context, product models.Product) (models.OptionGroup, bool) {
+	var group models.OptionGroup
+	groupID, err := strconv.ParseUint(c.Param("groupId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz seçenek grubu ID"})
+		return group, false
+	}
+
+	err = config.DB.Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("sort_order, id") }).
+		Where("product_id = ?", product.ID).First(&group, groupID).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Seçenek grubu bulunamadı"})
+		return group, false
+	}
+	return group, true
+}
+
+// applyVariantRequest isteği çeşide uygular; stoğu ürünün birimine göre ve
+// SKU'nun dükkan içinde benzersizliğini kontrol eder.
+func applyVariantRequest(variant *models.ProductVariant, product models.Product, req VariantRequest) (int, error) {
+	sku := strings.TrimSpace(req.SKU)
+	if sku == "" {
+		return http.StatusBadRequest, errors.New("SKU boş olamaz")
+	}
+
+	variant.Name = strings.TrimSpace(req.Name)
+	variant.SKU = sku
+	variant.Price = req.Price
+	variant.Stock = req.Stock
+	variant.SortOrder = req.SortOrder
+	if req.IsActive != nil {
+		variant.IsActive = *req.IsActive
+	}
+
+	if err := variant.ValidateStock(product.Unit); err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	var count int64
+	config.DB.Model(&models.ProductVariant{}).
+		Where("shop_id = ? AND sku = ? AND id <> ?", product.ShopID, sku, variant.ID).
+		Count(&count)
+	if count > 0 {
+		return http.StatusConflict, errors.New("Bu SKU dükkanınızda başka bir çeşitte kullanılıyor")
+	}
+	return 0, nil
+}
+
+// applyOptionGroupRequest isteği gruba uygular. Mevcut seçenekler id ile
+// eşleştirilir; başka gruba ait id'ler reddedilir.
+func applyOptionGroupRequest(group *models.OptionGroup, req OptionGroupRequest) error {
+	existing := make(map[uint]models.ProductOption, len(group.Options))
+	for _, option := range group.Options {
+		existing[option.ID] = option
+	}
+
+	options := make([]models.ProductOption, 0, len(req.Options))
+	for _, r := range req.Options {
+		option := models.ProductOption{IsActive: true}
+		if r.ID != 0 {
+			var ok bool
+			if option, ok = existing[r.ID]; !ok {
+				return errors.New("Seçenek bu gruba ait değil: " + strconv.Itoa(int(r.ID)))
+			}
+			delete(existing, r.ID)
+		}
+		option.Name = strings.TrimSpace(r.Name)
+		option.PriceModifier = r.PriceModifier
+		option.SortOrder = r.SortOrder
+		if r.IsActive != nil {
+			option.IsActive = *r.IsActive
+		}
+		options = append(options, option)
+	}
+
+	group.Name = strings.TrimSpace(req.Name)
+	group.MinSelect = req.MinSelect
+	group.MaxSelect = 1
+	if req.MaxSelect != nil {
+		group.MaxSelect = *req.MaxSelect
+	}
+	group.SortOrder = req.SortOrder
+	group.Options = options
+	return group.Validate()
+}
+
+// saveOptionGroup grubu kaydeder, seçeneklerini ekler/günceller ve listede
+// olmayan eski seçenekleri siler. tx içinde çağrılmalıdır.
+func saveOptionGroup(tx *gorm.DB, group *models.OptionGroup) error {
+	if err := tx.Omit("Options").Save(group).Error; err != nil {
+		return err
+	}
+
+	keep := []uint{0}
+	for i := range group.Options {
+		option := &group.Options[i]
+		option.OptionGroupID = group.ID
+		if option.ID == 0 {
+			if err := createWithActive(tx, option, option.IsActive); err != nil {
+				return err
+			}
+		} else if err := tx.Save(option).Error; err != nil {
+			return err
+		}
+		keep = append(keep, option.ID)
+	}
+
+	return tx.Where("option_group_id = ? AND id NOT IN ?", group.ID, keep).Delete(&models.ProductOption{}).Error
+}
+
+// createWithActive kaydı oluşturur. gorm, default etiketi olan alanların sıfır
+// değerini INSERT'e yazmadığından pasif kayıtlar ayrıca güncellenir.
+func createWithActive(tx *gorm.DB, value interface{}, active bool) error {
+	if err := tx.Create(value).Error; err != nil {
+		return err
+	}
+	if active {
+		return nil
+	}
+	return tx.Model(value).Update("is_active", false).Error
+}
+
+// loadOptionGroups ürünün seçenek gruplarını sıralı getirir; activeOnly ise
+// yalnızca aktif seçenekler yüklenir.
+func loadOptionGroups(db *gorm.DB, productID uint, activeOnly bool) ([]models.OptionGroup, error) {
+	var groups []models.OptionGroup
+	err := db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		if activeOnly {
+			db = db.Where("is_active = ?", true)
+		}
+		return db.Order("sort_order, id")
+	}).Where("product_id = ?", productID).Order("sort_order, id").Find(&groups).Error
+	return groups, err
+}
+
+// Sipariş kaleminin doğrulanmış çeşidi, seçenekleri ve seçenekler dahil birim fiyatı
+type itemSelection struct {
+	Variant *models.ProductVariant
+	Options []models.OrderItemOption
+	Price   money.Amount
+}
+
+// selectOrderItem sipariş kalemindeki çeşit ve seçenekleri ürüne göre doğrular.
+// Aktif çeşidi olan ürünlerde çeşit seçimi zorunludur. tx içinde çağrılmalıdır.
+func selectOrderItem(tx *gorm.DB, product models.Product, item OrderItem) (itemSelection, int, error) {
+	selection := itemSelection{Price: product.Price}
+
+	var variants []models.ProductVariant
+	if err := tx.Where("product_id = ? AND is_active = ?", product.ID, true).Find(&variants).Error; err != nil {
+		return selection, http.StatusInternalServerError, errors.New("Çeşitler getirilemedi")
+	}
+
+	if item.VariantID == nil {
+		if len(variants) > 0 {
+			return selection, http.StatusBadRequest, errors.New(product.Name + " için çeşit seçilmeli")
+		}
+	} else {
+		for i := range variants {
+			if variants[i].ID == *item.VariantID {
+				selection.Variant = &variants[i]
+			}
+		}
+		if selection.Variant == nil {
+			return selection, http.StatusBadRequest, errors.New("Geçersiz çeşit: " + strconv.Itoa(int(*item.VariantID)))
+		}
+		selection.Price = selection.Variant.Price
+	}
+
+	groups, err := loadOptionGroups(tx, product.ID, true)
+	if err != nil {
+		return selection, http.StatusInternalServerError, errors.New("Seçenekler getirilemedi")
+	}
+	if selection.Options, err = models.SelectOptions(groups, item.OptionIDs); err != nil {
+		return selection, http.StatusBadRequest, err
+	}
+
+	for _, option := range selection.Options {
+		selection.Price += option.PriceModifier
+	}
+	if selection.Price <= 0 {
+		return selection, http.StatusBadRequest, errors.New(product.Name + " için seçeneklerle birlikte birim fiyat sıfırdan büyük olmalı")
+	}
+	return selection, 0, nil
+}
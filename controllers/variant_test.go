@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"tradesman-api/config"
+	"tradesman-api/models"
+	"tradesman-api/money"
+
+	"gorm.io/gorm"
+)
+
+func TestOrderOptions(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T) {
+		customer, shop, product := testCatalog(t, 10)
+		owner := models.User{ID: shop.UserID, Role: models.RoleShop}
+
+		pc := &ProductController{}
+		shopRouter := asUser(owner)
+		shopRouter.POST("/products/:id/option-groups", pc.CreateOptionGroup)
+		shopRouter.PUT("/products/:id/option-groups/:groupId", pc.UpdateOptionGroup)
+		shopRouter.DELETE("/products/:id/option-groups/:groupId", pc.DeleteOptionGroup)
+		groupsPath := fmt.Sprintf("/products/%d/option-groups", product.ID)
+
+		createGroup := func(req OptionGroupRequest) models.OptionGroup {
+			t.Helper()
+			w := postJSON(shopRouter, groupsPath, req)
+			if w.Code != http.StatusCreated {
+				t.Fatalf("%s: yanıt kodu %d, beklenen 201: %s", req.Name, w.Code, w.Body)
+			}
+			var resp struct {
+				Group models.OptionGroup `json:"option_group"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			return resp.Group
+		}
+
+		two, one := 2, 1
+		inactive := false
+		extras := createGroup(OptionGroupRequest{Name: "Ekstralar", MaxSelect: &two, Options: []OptionRequest{
+			{Name: "Peynir", PriceModifier: 500},
+			{Name: "Sucuk", PriceModifier: 750},
+			{Name: "Kaşar", PriceModifier: 600, IsActive: &inactive},
+		}})
+		cooking := createGroup(OptionGroupRequest{Name: "Pişirme", MinSelect: 1, MaxSelect: &one, Options: []OptionRequest{
+			{Name: "Az", PriceModifier: 0},
+			{Name: "Çok", PriceModifier: 100},
+			{Name: "Bayat", PriceModifier: -1000},
+		}})
+		cheese, sausage, kasar := extras.Options[0].ID, extras.Options[1].ID, extras.Options[2].ID
+		rare, welldone, stale := cooking.Options[0].ID, cooking.Options[1].ID, cooking.Options[2].ID
+
+		// En az seçim aktif seçenek sayısını ve en fazla seçimi aşamaz
+		if w := postJSON(shopRouter, groupsPath, OptionGroupRequest{Name: "Sos", MinSelect: 2, MaxSelect: &one, Options: []OptionRequest{{Name: "Acı"}, {Name: "Tatlı"}}}); w.Code != http.StatusBadRequest {
+			t.Errorf("en az > en fazla: yanıt kodu %d, beklenen 400", w.Code)
+		}
+		if w := postJSON(shopRouter, groupsPath, OptionGroupRequest{Name: "Sos", MinSelect: 1, Options: []OptionRequest{{Name: "Acı", IsActive: &inactive}}}); w.Code != http.StatusBadRequest {
+			t.Errorf("aktif seçeneksiz zorunlu grup: yanıt kodu %d, beklenen 400", w.Code)
+		}
+
+		oc := &OrderController{}
+		r := asUser(customer)
+		r.POST("/orders", oc.CreateOrder)
+		order := func(options ...uint) *httptest.ResponseRecorder {
+			return postJSON(r, "/orders", CreateOrderRequest{
+				ShopID: shop.ID,
+				Items:  []OrderItem{{ProductID: product.ID, Quantity: 2, OptionIDs: options}},
+			})
+		}
+
+		invalid := map[string][]uint{
+			"zorunlu grup seçilmemiş": {cheese},
+			"grupta fazla seçim":      {rare, welldone},
+			"aynı seçenek iki kez":    {cheese, cheese, rare},
+			"pasif seçenek":           {kasar, rare},
+			"olmayan seçenek":         {rare, 9999},
+			"birim fiyat sıfır olur":  {stale},
+		}
+		for name, options := range invalid {
+			if w := order(options...); w.Code != http.StatusBadRequest {
+				t.Errorf("%s: yanıt kodu %d, beklenen 400: %s", name, w.Code, w.Body)
+			}
+		}
+
+		w := order(cheese, sausage, welldone)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("yanıt kodu %d, beklenen 201: %s", w.Code, w.Body)
+		}
+		var resp struct {
+			Order models.Order `json:"order"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		// (10.00 + 5.00 + 7.50 + 1.00) × 2 = 47.00
+		if resp.Order.TotalAmount != 4700 {
+			t.Errorf("sipariş tutarı %s, beklenen 47.00", resp.Order.TotalAmount)
+		}
+
+		// Seçenekler sonradan değişse veya silinse de sipariş kalemindeki kopya korunur
+		req := OptionGroupRequest{Name: "Ekstralar", MaxSelect: &two, Options: []OptionRequest{{ID: cheese, Name: "Beyaz Peynir", PriceModifier: 900}}}
+		w = httptest.NewRecorder()
+		shopRouter.ServeHTTP(w, jsonRequest(http.MethodPut, fmt.Sprintf("%s/%d", groupsPath, extras.ID), req))
+		if w.Code != http.StatusOK {
+			t.Fatalf("grup güncelleme yanıt kodu %d: %s", w.Code, w.Body)
+		}
+		w = httptest.NewRecorder()
+		shopRouter.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", groupsPath, cooking.ID), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("grup silme yanıt kodu %d: %s", w.Code, w.Body)
+		}
+
+		var item models.OrderItem
+		if err := config.DB.Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).Where("order_id = ?", resp.Order.ID).First(&item).Error; err != nil {
+			t.Fatal(err)
+		}
+		want := []struct {
+			group, name string
+			modifier    money.Amount
+		}{{"Ekstralar", "Peynir", 500}, {"Ekstralar", "Sucuk", 750}, {"Pişirme", "Çok", 100}}
+		if item.Price != 2350 || len(item.Options) != len(want) {
+			t.Fatalf("sipariş kalemi beklenen gibi değil: %+v", item)
+		}
+		for i, o := range item.Options {
+			if o.GroupName != want[i].group || o.Name != want[i].name || o.PriceModifier != want[i].modifier {
+				t.Errorf("seçenek kopyası %d: %+v, beklenen %+v", i, o, want[i])
+			}
+		}
+
+		var remaining int64
+		config.DB.Model(&models.ProductOption{}).Where("option_group_id = ?", cooking.ID).Count(&remaining)
+		if remaining != 0 {
+			t.Errorf("silinen grubun %d seçeneği kaldı", remaining)
+		}
+		checkLedger(t)
+	})
+}
@@ -0,0 +1,462 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"tradesman-api/config"
+	"tradesman-api/models"
+	"tradesman-api/webhooks"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type WebhookController struct{}
+
+// Dükkan başına en fazla uç nokta sayısı
+const maxWebhooksPerShop = 10
+
+type WebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Events      []string `json:"events" binding:"required,min=1"` // order.created, order.status_changed, stock.alert
+	Description string   `json:"description" binding:"max=255"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// validate isteği doğrular ve olay tiplerini tekilleştirir.
+func (req *WebhookRequest) validate() error {
+	req.URL = strings.TrimSpace(req.URL)
+	if err := webhooks.ValidateURL(req.URL, config.App.WebhookAllowPrivate); err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(req.Events))
+	events := req.Events[:0]
+	for _, t := range req.Events {
+		if !webhooks.IsEventType(t) {
+			return fmt.Errorf("geçersiz olay tipi: %s (%s)", t, strings.Join(webhooks.EventTypes, ", "))
+		}
+		if !seen[t] {
+			seen[t] = true
+			events = append(events, t)
+		}
+	}
+	req.Events = events
+	return nil
+}
+
+// @Summary Webhook Listesi
+// @Description Dükkanın webhook uç noktalarını listeler (sadece esnaflar)
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /webhooks [get]
+func (wc *WebhookController) GetWebhooks(c *gin.Context) {
+	shop, ok := loadOwnShop(c)
+	if !ok {
+		return
+	}
+
+	var endpoints []models.WebhookEndpoint
+	if err := config.DB.Where("shop_id = ?", shop.ID).Order("id").Find(&endpoints).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Webhook'lar getirilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks":    endpoints,
+		"event_types": webhooks.EventTypes,
+	})
+}
+
+// @Summary Webhook Oluştur
+// @Description Sipariş olaylarının gönderileceği bir adres ekler (sadece esnaflar). Yanıttaki secret yalnızca bir kez gösterilir; gövdeler bu anahtarla HMAC-SHA256 imzalanır (X-Webhook-Signature: sha256=HMAC(secret, timestamp + "." + gövde)).
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param webhook body WebhookRequest true "Adres ve abone olunan olaylar"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /webhooks [post]
+func (wc *WebhookController) CreateWebhook(c *gin.Context) {
+	shop, ok := loadOwnShop(c)
+	if !ok {
+		return
+	}
+
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var count int64
+	if err := config.DB.Model(&models.WebhookEndpoint{}).Where("shop_id = ?", shop.ID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Webhook oluşturulamadı"})
+		return
+	}
+	if count >= maxWebhooksPerShop {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("En fazla %d webhook eklenebilir", maxWebhooksPerShop)})
+		return
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Webhook oluşturulamadı"})
+		return
+	}
+
+	endpoint := models.WebhookEndpoint{
+		ShopID:      shop.ID,
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      secret,
+		Events:      req.Events,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	}
+	if err := createWithActive(config.DB, &endpoint, endpoint.IsActive); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Webhook oluşturulamadı"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Webhook başarıyla oluşturuldu",
+		"webhook": endpoint,
+		"secret":  secret,
+	})
+}
+
+// @Summary Webhook Detayı
+// @Description Webhook uç noktasını getirir (sadece esnaflar)
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{id} [get]
+func (wc *WebhookController) GetWebhook(c *gin.Context) {
+	endpoint, ok := loadOwnedWebhook(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhook": endpoint,
+	})
+}
+
+// @Summary Webhook Güncelle
+// @Description Adresi, olayları, açıklamayı ve aktifliği günceller (sadece esnaflar). is_active verilmezse değişmez. Pasif uç noktalara teslimat yapılmaz.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param webhook body WebhookRequest true "Adres ve abone olunan olaylar"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{id} [put]
+func (wc *WebhookController) UpdateWebhook(c *gin.Context) {
+	endpoint, ok := loadOwnedWebhook(c)
+	if !ok {
+		return
+	}
+
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint.URL = req.URL
+	endpoint.Events = req.Events
+	endpoint.Description = req.Description
+	if req.IsActive != nil {
+		endpoint.IsActive = *req.IsActive
+	}
+	if err := config.DB.Save(&endpoint).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Webhook güncellenemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook başarıyla güncellendi",
+		"webhook": endpoint,
+	})
+}
+
+// @Summary Webhook Sil
+// @Description Webhook uç noktasını siler (sadece esnaflar). Bekleyen teslimatlar gönderilmez; teslimat kayıtları saklanır.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{id} [delete]
+func (wc *WebhookController) DeleteWebhook(c *gin.Context) {
+	endpoint, ok := loadOwnedWebhook(c)
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Delete(&endpoint).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Webhook silinemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook başarıyla silindi"})
+}
+
+// @Summary Webhook Anahtarını Yenile
+// @Description Yeni bir imza anahtarı üretir; eski anahtar hemen geçersiz olur (sadece esnaflar). Yeni anahtar yalnızca bu yanıtta gösterilir.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{id}/rotate-secret [post]
+func (wc *WebhookController) RotateWebhookSecret(c *gin.Context) {
+	endpoint, ok := loadOwnedWebhook(c)
+	if !ok {
+		return
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err == nil {
+		err = config.DB.Model(&endpoint).Update("secret", secret).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Anahtar yenilenemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Anahtar yenilendi",
+		"secret":  secret,
+	})
+}
+
+// @Summary Webhook Dene
+// @Description Uç noktaya webhook.ping olayı gönderir (sadece esnaflar). Sonuç teslimat kayıtlarından izlenir.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 202 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /webhooks/{id}/ping [post]
+func (wc *WebhookController) PingWebhook(c *gin.Context) {
+	endpoint, ok := loadOwnedWebhook(c)
+	if !ok {
+		return
+	}
+
+	if !endpoint.IsActive {
+		c.JSON(http.StatusConflict, gin.H{"error": "Pasif webhook'a gönderim yapılamaz"})
+		return
+	}
+
+	delivery, err := webhooks.EnqueuePing(config.DB, endpoint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Deneme gönderilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":  "Deneme kuyruğa alındı",
+		"delivery": delivery,
+	})
+}
+
+var webhookDeliveryListOptions = listOptions{
+	table: "webhook_deliveries",
+	sorts: map[string]string{
+		"id":         "id",
+		"created_at": "created_at",
+	},
+	defaultSort: "-id",
+}
+
+// @Summary Webhook Teslimatları
+// @Description Uç noktanın teslimat kayıtlarını son denemenin yanıt koduyla sayfalı listeler (sadece esnaflar)
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param page query int false "Sayfa numarası (varsayılan 1)"
+// @Param per_page query int false "Sayfa başına kayıt (varsayılan 20, en fazla 100)"
+// @Param sort query string false "Sıralama: id, created_at (azalan için başına -, varsayılan -id)"
+// @Param status query string false "Durum filtresi: pending, succeeded, failed"
+// @Param event_type query string false "Olay tipi filtresi"
+// @Param from query string false "Başlangıç tarihi (2024-05-01 veya RFC 3339)"
+// @Param to query string false "Bitiş tarihi (2024-05-31, gün dahil, veya RFC 3339)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{id}/deliveries [get]
+func (wc *WebhookController) GetWebhookDeliveries(c *gin.Context) {
+	endpoint, ok := loadOwnedWebhook(c)
+	if !ok {
+		return
+	}
+
+	lq, err := parseListQuery(c, webhookDeliveryListOptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := config.DB.Model(&models.WebhookDelivery{}).Omit("payload").Where("endpoint_id = ?", endpoint.ID)
+
+	if status := models.WebhookDeliveryStatus(c.Query("status")); status != "" {
+		if !status.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz teslimat durumu: " + string(status) + " (pending, succeeded, failed)"})
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+	if eventType := c.Query("event_type"); eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+
+	query, err = filterDateRange(c, query, "created_at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var deliveries []models.WebhookDelivery
+	pagination, err := findPage(c, query, lq, &deliveries)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Teslimatlar getirilemedi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"pagination": pagination,
+	})
+}
+
+// @Summary Webhook Teslimat Detayı
+// @Description Teslimatı gönderilen gövde ve tüm denemeleriyle (yanıt kodu, yanıtın ilk 2 KB'ı, hata, süre) getirir (sadece esnaflar)
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param deliveryId path int true "Teslimat ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{id}/deliveries/{deliveryId} [get]
+func (wc *WebhookController) GetWebhookDelivery(c *gin.Context) {
+	delivery, ok := loadOwnedDelivery(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"delivery": delivery,
+	})
+}
+
+// @Summary Webhook Teslimatını Yeniden Gönder
+// @Description Başarılı veya başarısız bir teslimatı aynı gövdeyle yeniden kuyruğa alır (sadece esnaflar). Deneme sayısı sıfırlanır; önceki denemeler kayıtlarda kalır.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param deliveryId path int true "Teslimat ID"
+// @Success 202 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (wc *WebhookController) RedeliverWebhook(c *gin.Context) {
+	delivery, ok := loadOwnedDelivery(c)
+	if !ok {
+		return
+	}
+
+	queued, err := webhooks.Redeliver(config.DB, &delivery)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Teslimat yeniden kuyruğa alınamadı"})
+		return
+	}
+	if !queued {
+		c.JSON(http.StatusConflict, gin.H{"error": "Teslimat zaten gönderilmeyi bekliyor"})
+		return
+	}
+
+	var queuedDelivery models.WebhookDelivery
+	config.DB.Omit("payload").First(&queuedDelivery, delivery.ID)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":  "Teslimat yeniden kuyruğa alındı",
+		"delivery": queuedDelivery,
+	})
+}
+
+// loadOwnedWebhook :id parametresindeki uç noktayı, isteği yapan esnafın
+// dükkanına aitse getirir; değilse yanıtı yazar ve false döner.
+func loadOwnedWebhook(c *gin.Context) (models.WebhookEndpoint, bool) {
+	var endpoint models.WebhookEndpoint
+
+	shop, ok := loadOwnShop(c)
+	if !ok {
+		return endpoint, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz webhook ID"})
+		return endpoint, false
+	}
+
+	if err := config.DB.Where("shop_id = ?", shop.ID).First(&endpoint, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook bulunamadı"})
+		return endpoint, false
+	}
+	return endpoint, true
+}
+
+// loadOwnedDelivery :deliveryId parametresindeki teslimatı denemeleriyle getirir.
+func loadOwnedDelivery(c *gin.Context) (models.WebhookDelivery, bool) {
+	var delivery models.WebhookDelivery
+
+	endpoint, ok := loadOwnedWebhook(c)
+	if !ok {
+		return delivery, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Geçersiz teslimat ID"})
+		return delivery, false
+	}
+
+	err = config.DB.Preload("History", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Where("endpoint_id = ?", endpoint.ID).First(&delivery, id).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Teslimat bulunamadı"})
+		return delivery, false
+	}
+	return delivery, true
+}
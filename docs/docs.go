@@ -24,9 +24,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
+        "/admin/categories": {
             "post": {
-                "description": "Kullanıcı girişi yapar ve JWT token döner",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Yeni kategori oluşturur (sadece admin). Slug verilmezse isimden üretilir.",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,23 +39,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Kullanıcı Girişi",
+                "summary": "Kategori Oluştur",
                 "parameters": [
                     {
-                        "description": "Giriş bilgileri",
-                        "name": "credentials",
+                        "description": "Kategori bilgileri",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.LoginRequest"
+                            "$ref": "#/definitions/controllers.CategoryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -63,8 +68,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -73,42 +78,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/me": {
-            "get": {
+        "/admin/categories/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mevcut kullanıcının profil bilgilerini getirir",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Auth"
-                ],
-                "summary": "Kullanıcı Profili",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Yeni kullanıcı kaydı oluşturur",
+                "description": "Kategori bilgilerini ve üst kategorisini günceller (sadece admin)",
                 "consumes": [
                     "application/json"
                 ],
@@ -116,23 +93,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Admin"
                 ],
-                "summary": "Kullanıcı Kaydı",
+                "summary": "Kategori Güncelle",
                 "parameters": [
                     {
-                        "description": "Kullanıcı bilgileri",
-                        "name": "user",
+                        "type": "integer",
+                        "description": "Kategori ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Kategori bilgileri",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.RegisterRequest"
+                            "$ref": "#/definitions/controllers.CategoryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -144,25 +128,46 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
-            }
-        },
-        "/orders": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Mevcut kullanıcının siparişlerini listeler",
+                "description": "Alt kategorisi ve ürünü olmayan kategoriyi siler (sadece admin)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin"
+                ],
+                "summary": "Kategori Sil",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Kategori ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Kullanıcının Siparişlerini Listele",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -170,40 +175,87 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/users": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Yeni sipariş oluşturur (sadece müşteriler)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Kullanıcıları listeler ve arar (sadece admin)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin"
                 ],
-                "summary": "Sipariş Oluştur",
+                "summary": "Kullanıcıları Listele",
                 "parameters": [
                     {
-                        "description": "Sipariş bilgileri",
-                        "name": "order",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.CreateOrderRequest"
-                        }
+                        "type": "string",
+                        "description": "İsim, email veya telefonda arama",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rol filtresi (admin, shop, customer)",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Askı durumu filtresi",
+                        "name": "suspended",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa numarası (varsayılan 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa başına kayıt (varsayılan 20, en fazla 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sıralama: id, name, email, created_at (azalan için başına -)",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -226,25 +278,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/{id}": {
+        "/admin/users/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Belirli bir siparişin detaylarını getirir",
+                "description": "Belirli bir kullanıcının detaylarını getirir (sadece admin)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin"
                 ],
-                "summary": "Sipariş Detayı",
+                "summary": "Kullanıcı Detayı",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Sipariş ID",
+                        "description": "Kullanıcı ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -258,13 +310,6 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -273,45 +318,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/orders/{id}/status": {
-            "put": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Sipariş durumunu günceller (sadece esnaflar)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Kullanıcıyı siler (soft delete) ve tüm oturumlarını sonlandırır (sadece admin)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admin"
                 ],
-                "summary": "Sipariş Durumu Güncelle",
+                "summary": "Kullanıcı Sil",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Sipariş ID",
+                        "description": "Kullanıcı ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Yeni durum",
-                        "name": "status",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
                     }
                 ],
                 "responses": {
@@ -329,13 +357,6 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -346,33 +367,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/products": {
-            "get": {
-                "description": "Aktif olan tüm ürünleri listeler",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Products"
-                ],
-                "summary": "Tüm Ürünleri Listele",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            },
-            "post": {
+        "/admin/users/{id}/role": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Yeni ürün oluşturur (sadece esnaflar)",
+                "description": "Kullanıcıyı admin yapar veya rolünü düşürür (sadece admin)",
                 "consumes": [
                     "application/json"
                 ],
@@ -380,23 +382,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Admin"
                 ],
-                "summary": "Ürün Oluştur",
+                "summary": "Kullanıcı Rolünü Değiştir",
                 "parameters": [
                     {
-                        "description": "Ürün bilgileri",
-                        "name": "product",
+                        "type": "integer",
+                        "description": "Kullanıcı ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Yeni rol",
+                        "name": "role",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.CreateProductRequest"
+                            "$ref": "#/definitions/controllers.UpdateUserRoleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -409,8 +418,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -419,20 +428,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/{id}": {
-            "get": {
-                "description": "Belirli bir ürünün detaylarını getirir",
+        "/admin/users/{id}/suspend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Kullanıcıyı askıya alır ve tüm oturumlarını sonlandırır (sadece admin)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Admin"
                 ],
-                "summary": "Ürün Detayı",
+                "summary": "Kullanıcıyı Askıya Al",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Ürün ID",
+                        "description": "Kullanıcı ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -446,6 +460,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -454,39 +475,71 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/users/{id}/unsuspend": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Ürün bilgilerini günceller (sadece ürün sahibi)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Askıya alınmış kullanıcıyı yeniden aktif eder (sadece admin)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Admin"
                 ],
-                "summary": "Ürün Güncelle",
+                "summary": "Kullanıcı Askısını Kaldır",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Ürün ID",
+                        "description": "Kullanıcı ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Güncellenecek ürün bilgileri",
-                        "name": "product",
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Hesap varsa e-posta adresine şifre sıfırlama bağlantısı gönderir. Hesabın olup olmadığı belli edilmez; yanıt her zaman aynıdır. Aynı hesaba dakikada bir, saatte en fazla beş e-posta gönderilir.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Şifremi Unuttum",
+                "parameters": [
+                    {
+                        "description": "Hesabın e-posta adresi",
+                        "name": "email",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.CreateProductRequest"
+                            "$ref": "#/definitions/controllers.ForgotPasswordRequest"
                         }
                     }
                 ],
@@ -504,41 +557,85 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Kullanıcı girişi yapar ve JWT token döner",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Kullanıcı Girişi",
+                "parameters": [
+                    {
+                        "description": "Giriş bilgileri",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/logout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Ürünü siler (sadece ürün sahibi)",
+                "description": "Mevcut access token'ı iptal eder. Yenileme token'ı gönderilirse ailesi, all_devices=true ise kullanıcının tüm yenileme token'ları iptal edilir.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Products"
+                    "Auth"
                 ],
-                "summary": "Ürün Sil",
+                "summary": "Çıkış Yap",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Ürün ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Çıkış seçenekleri",
+                        "name": "logout",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.LogoutRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -549,15 +646,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -566,16 +656,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/shops": {
+        "/auth/me": {
             "get": {
-                "description": "Aktif olan tüm esnafları listeler",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mevcut kullanıcının profil bilgilerini getirir",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Shops"
+                    "Auth"
                 ],
-                "summary": "Tüm Esnafları Listele",
+                "summary": "Kullanıcı Profili",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -583,16 +678,20 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
-            },
+            }
+        },
+        "/auth/phone/code": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Yeni esnaf kaydı oluşturur (sadece shop rolündeki kullanıcılar)",
+                "description": "Numaraya tek kullanımlık 6 haneli giriş kodu gönderir. Türkiye cep numaraları her biçimde kabul edilir ve +90 ile E.164 biçimine çevrilir. Aynı numaraya dakikada bir, saatte en fazla beş kod gönderilir; ayrıca bir IP adresinden (OTP_IP_HOURLY_LIMIT) ve tüm istemcilerden (OTP_HOURLY_LIMIT) saatte istenebilecek kod sayısı sınırlıdır. Sınır aşılırsa 429 ve Retry-After döner. Yeni kod önceki kodları geçersiz kılar.",
                 "consumes": [
                     "application/json"
                 ],
@@ -600,23 +699,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Shops"
+                    "Auth"
                 ],
-                "summary": "Esnaf Oluştur",
+                "summary": "Telefonla Giriş Kodu İste",
                 "parameters": [
                     {
-                        "description": "Esnaf bilgileri",
-                        "name": "shop",
+                        "description": "Telefon numarası",
+                        "name": "phone",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.CreateShopRequest"
+                            "$ref": "#/definitions/controllers.PhoneCodeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -629,8 +728,15 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -639,23 +745,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/shops/{id}": {
-            "get": {
-                "description": "Belirli bir esnafın detaylarını getirir",
+        "/auth/phone/login": {
+            "post": {
+                "description": "SMS ile gelen kodla giriş yapar ve /auth/login ile aynı token'ları döner. Numara bir müşteri hesabında doğrulanmışsa (telefonla kayıt veya /auth/phone/verify) o hesapla giriş yapılır; profildeki doğrulanmamış phone alanı eşleştirmede kullanılmaz. Esnaf ve yönetici hesapları telefonla giriş yapamaz (403). Numara kayıtlı değilse name ile SMS bildirimleri açık yeni müşteri hesabı oluşturulur (201); name verilmezse kod harcanmadan name_required=true döner. Yalnızca /auth/phone/code ile istenen kodlar kabul edilir, numara doğrulama kodları girişte kullanılamaz. Her kod en fazla beş kez denenebilir ve tek kullanımlıktır.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Shops"
+                    "Auth"
                 ],
-                "summary": "Esnaf Detayı",
+                "summary": "Telefonla Giriş",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Esnaf ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Telefon numarası, kod ve yeni hesap için ad",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PhoneLoginRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -666,22 +777,52 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/auth/phone/verify": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Esnaf bilgilerini günceller",
+                "description": "Giriş yapmış müşterinin numarasını SMS kodu ve hesabın şifresiyle doğrular; numara profile doğrulanmış olarak (phone_e164) kaydedilir ve sonraki telefonla girişler bu hesaba yapılır. Profildeki phone alanı doğrulanmadan hiçbir hesaba bağlanmaz.",
                 "consumes": [
                     "application/json"
                 ],
@@ -689,24 +830,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Shops"
+                    "Auth"
                 ],
-                "summary": "Esnaf Güncelle",
+                "summary": "Telefon Numarasını Doğrula",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Esnaf ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Güncellenecek esnaf bilgileri",
-                        "name": "shop",
+                        "description": "Telefon numarası, kod ve şifre",
+                        "name": "verify",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controllers.CreateShopRequest"
+                            "$ref": "#/definitions/controllers.PhoneVerifyRequest"
                         }
                     }
                 ],
@@ -725,15 +859,22 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -742,23 +883,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/shops/{id}/products": {
-            "get": {
-                "description": "Belirli bir esnafın ürünlerini listeler",
+        "/auth/phone/verify/code": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Giriş yapmış müşterinin profiline bağlamak istediği numaraya 6 haneli doğrulama kodu gönderir. Başka bir hesaba bağlı numaralar için 409 döner. Kod yalnızca /auth/phone/verify ile kullanılabilir; gönderim sınırları /auth/phone/code ile aynıdır ancak ayrı sayılır.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Shops"
+                    "Auth"
                 ],
-                "summary": "Esnafın Ürünlerini Listele",
+                "summary": "Telefon Numarası Doğrulama Kodu İste",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Esnaf ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Telefon numarası",
+                        "name": "phone",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PhoneCodeRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -769,7 +920,3409 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Yenileme token'ı ile yeni access ve yenileme token'ı üretir. Kullanılmış bir yenileme token'ı tekrar gönderilirse tüm token ailesi iptal edilir.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Token Yenile",
+                "parameters": [
+                    {
+                        "description": "Yenileme token'ı",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Yeni müşteri veya esnaf kaydı oluşturur ve e-posta adresine doğrulama bağlantısı gönderir. Admin hesabı bu uç noktadan oluşturulamaz.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Kullanıcı Kaydı",
+                "parameters": [
+                    {
+                        "description": "Kullanıcı bilgileri",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/resend-verification": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Giriş yapmış kullanıcıya yeni bir doğrulama bağlantısı gönderir; önceki bağlantılar da süreleri dolana kadar geçerli kalır. Dakikada bir, saatte en fazla beş e-posta gönderilir; sınır aşılırsa 429 ve Retry-After döner.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Doğrulama E-postasını Tekrar Gönder",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/reset-password": {
+            "post": {
+                "description": "Şifre sıfırlama e-postasındaki token ile yeni şifre belirler. Token tek kullanımlıktır; şifre değişince kullanıcının diğer sıfırlama bağlantıları ve tüm oturumları iptal edilir, daha önce verilen access token'lar reddedilir. Bağlantıyı açmak e-posta adresini de doğrular.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Şifre Sıfırla",
+                "parameters": [
+                    {
+                        "description": "Token ve yeni şifre",
+                        "name": "reset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email": {
+            "post": {
+                "description": "Doğrulama e-postasındaki token ile e-posta adresini doğrular. Token tek kullanımlıktır ve yalnızca gönderildiği adres için geçerlidir.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "E-posta Doğrula",
+                "parameters": [
+                    {
+                        "description": "Doğrulama token'ı",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "description": "Tüm kategorileri alt kategorileriyle birlikte ağaç olarak listeler",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Kategori Ağacı",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}": {
+            "get": {
+                "description": "Kategoriyi alt kategorileri ve kökten itibaren yolu (path) ile getirir. ID yerine slug da kullanılabilir.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Kategori Detayı",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kategori ID veya slug",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}/products": {
+            "get": {
+                "description": "Kategoriye ve tüm alt kategorilerine ait aktif ürünleri sayfalı olarak listeler. ID yerine slug da kullanılabilir.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Kategorideki Ürünler",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kategori ID veya slug",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa numarası (varsayılan 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa başına kayıt (varsayılan 20, en fazla 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sıralama: id, name, price, stock, created_at (azalan için başına -)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Esnaf filtresi",
+                        "name": "shop_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "En düşük fiyat",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "En yüksek fiyat",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sadece stokta olanlar (true) veya tükenenler (false)",
+                        "name": "in_stock",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/devices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Kullanıcının push bildirimi alan cihazlarını listeler",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Push Cihazları",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mobil uygulamanın push token'ını kaydeder. Token başka bir kullanıcıya kayıtlıysa bu kullanıcıya geçer (aynı cihazda hesap değişikliği).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Push Cihazı Kaydet",
+                "parameters": [
+                    {
+                        "description": "Push token ve platform",
+                        "name": "device",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.PushDeviceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/devices/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cihaza push bildirimi gönderilmesini durdurur (ör. çıkış yaparken)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Push Cihazını Sil",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cihaz ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bildirim dilini ve açık kanalları (email, sms, push) getirir. Tercih kaydedilmemişse varsayılanlar döner: Türkçe, e-posta ve push açık, SMS kapalı.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Bildirim Tercihleri",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bildirim dilini ve kanalları günceller; verilmeyen alanlar değişmez. SMS için profilde telefon, push için kayıtlı cihaz gerekir.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Bildirim Tercihlerini Güncelle",
+                "parameters": [
+                    {
+                        "description": "Dil ve kanallar",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.NotificationPreferenceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mevcut kullanıcının siparişlerini sayfalı olarak listeler",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Kullanıcının Siparişlerini Listele",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sayfa numarası (varsayılan 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa başına kayıt (varsayılan 20, en fazla 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sıralama: id, created_at, total_amount, status (azalan için başına -, varsayılan -id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Durum filtresi, virgülle birden fazla verilebilir (ör. pending,confirmed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Esnaf filtresi (müşteri ve admin)",
+                        "name": "shop_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bu tarihten sonra oluşturulanlar (2006-01-02 veya RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bu tarihe kadar oluşturulanlar (2006-01-02 veya RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Yeni sipariş oluşturur (sadece müşteriler). Dükkan require_verified_customers ayarını açtıysa müşterinin e-posta adresi veya telefon numarası doğrulanmış olmalıdır.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Sipariş Oluştur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tekrar denemelerde aynı siparişin iki kez oluşturulmasını önleyen benzersiz anahtar",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Sipariş bilgileri",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events ile sipariş olaylarını canlı gönderir. Esnaflar dükkanlarına gelen yeni siparişleri (order.created), durum değişikliklerini (order.status_changed) ve stok uyarılarını (stock.alert), müşteriler kendi siparişlerinin durum değişikliklerini alır. Yeniden bağlanırken Last-Event-ID başlığı (veya last_event_id parametresi) ile kaçırılan olaylar tekrar gönderilir; kaçırılanlar artık bellekte değilse önce stream.reset olayı gelir ve siparişler yeniden yüklenmelidir. EventSource başlık gönderemediği için token access_token parametresiyle de verilebilir. Bağlantı token süresi dolunca kapanır.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Sipariş Akışı (SSE)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization başlığı yerine JWT",
+                        "name": "access_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alınan son olay ID'si",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alınan son olay ID'si (başlık yerine)",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "text/event-stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/ws": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sipariş olaylarını WebSocket üzerinden JSON mesajlar ({id, type, data, created_at}) olarak gönderir. Olaylar ve yetki SSE akışıyla aynıdır. Token access_token parametresiyle, kaçırılan olaylar için son olay ID'si last_event_id parametresiyle verilir. Tarayıcı bağlantıları yalnızca CORS_ORIGINS listesindeki originlerden kabul edilir. Bağlantı token süresi dolunca kapanır.",
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Sipariş Akışı (WebSocket)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization başlığı yerine JWT",
+                        "name": "access_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alınan son olay ID'si",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Belirli bir siparişin detaylarını getirir",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Sipariş Detayı",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sipariş ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Müşteri, esnaf hazırlamaya başlamadan (pending/confirmed) siparişini iptal eder. Sipariş kalemlerinin stokları iade edilir.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Siparişi İptal Et",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sipariş ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "İptal nedeni",
+                        "name": "cancel",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CancelOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Siparişin durum değişikliklerini kim tarafından ve ne zaman yapıldığıyla birlikte kronolojik sırada listeler",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Sipariş Durum Geçmişi",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sipariş ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sipariş durumunu günceller (sadece esnaflar). İzin verilen geçişler: pending→confirmed→preparing→ready→delivered; teslim edilmemiş siparişler iptal edilebilir. İptalde note (neden) zorunludur ve stoklar iade edilir.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Sipariş Durumu Güncelle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sipariş ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Yeni durum ve isteğe bağlı not",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.UpdateOrderStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "description": "Aktif olan ürünleri sayfalı olarak listeler",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Tüm Ürünleri Listele",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sayfa numarası (varsayılan 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa başına kayıt (varsayılan 20, en fazla 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sıralama: id, name, price, stock, created_at (azalan için başına -)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Esnaf filtresi",
+                        "name": "shop_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Kategori filtresi (alt kategoriler dahil)",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "En düşük fiyat",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "En yüksek fiyat",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sadece stokta olanlar (true) veya tükenenler (false)",
+                        "name": "in_stock",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Yeni ürün oluşturur (sadece esnaflar)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Ürün Oluştur",
+                "parameters": [
+                    {
+                        "description": "Ürün bilgileri",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Dükkanın tüm ürünlerini (pasifler dahil) içe aktarmayla aynı sütunlarla CSV veya XLSX olarak indirir (sadece esnaflar)",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Ürünleri Dışa Aktar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (varsayılan) veya xlsx",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Dükkanın ürünlerini CSV veya XLSX dosyasından ekler/günceller (sadece esnaflar). Satırlar id doluysa ürün ID'siyle, değilse SKU ile eşleştirilir; böylece SKU'su olmayan ürünler de dışa aktarılıp geri yüklenebilir. İlk satır sütun başlıklarıdır; sku, name ve price sütunları zorunludur, yeni ürünlerde sku hücresi de zorunludur. Mevcut üründe boş bırakılan hücreler değeri değiştirmez. Bir satır bile hatalıysa hiçbir değişiklik yapılmaz ve 422 döner; dry_run=true ile sadece doğrulama raporu alınır.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Ürünleri İçe Aktar",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV veya XLSX dosyası",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv veya xlsx (verilmezse dosya uzantısından)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sadece doğrula, kaydetme",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ImportReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/low-stock": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Dükkanın aktif ürün ve çeşitlerinden stoğu biten veya yeniden sipariş eşiğine (reorder_threshold) düşenleri listeler (sadece esnaflar). Tükenenler önce, sonra stoğun eşiğe oranına göre sıralanır; eşitlikte ürün ve çeşit ID sırası kullanılır.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Düşük Stok Raporu",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/search": {
+            "get": {
+                "description": "Aktif ürünlerde isim ve açıklamada tam metin arama yapar; stoğu biten ürünleri gizleyen dükkanların tükenen ürünleri listede olduğu gibi sonuçlarda da yer almaz. Türkçe karakterlerden ve büyük/küçük harften bağımsızdır, kelime başlarıyla eşleşir (ör. \"sim\" → \"Simit\"). Sonuçlar alaka sırasıyla döner; eşleşen kelimeler highlight alanında \u003cmark\u003e ile işaretlenir.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Ürün Ara",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Arama ifadesi",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa numarası (varsayılan 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa başına kayıt (varsayılan 20, en fazla 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/stock-alerts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Dükkanın stok uyarılarını sayfalı olarak listeler (sadece esnaflar). Bir stok çıkışı (satış, fire, sayım) kalemi yeniden sipariş eşiğinin üstünden eşiğe düşürdüğünde low_stock, sıfıra düşürdüğünde out_of_stock uyarısı oluşur. Her uyarı stock.alert olayı olarak canlı akışa ve webhook'lara gönderilir, esnafa stock_alert bildirimi gider.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Stok Uyarıları",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sayfa numarası (varsayılan 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa başına kayıt (varsayılan 20, en fazla 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sıralama: id, created_at (azalan için başına -, varsayılan -id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Uyarı tipi: low_stock veya out_of_stock",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ürün filtresi",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bu tarihten sonraki uyarılar (2006-01-02 veya RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bu tarihe kadarki uyarılar (2006-01-02 veya RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "description": "Belirli bir ürünün detaylarını görselleri, aktif çeşitleri ve seçenek gruplarıyla birlikte getirir",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Ürün Detayı",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ürün bilgilerini günceller (sadece ürün sahibi). Stok doğrudan değiştirilmez; girilen stok mevcut stoktan farklıysa fark stok defterine düzeltme hareketi olarak yazılır. Aktif çeşidi olan ürünlerde stok çeşitlerde tutulur; farklı bir stok girilirse 400 döner. Stok hareketi veya siparişi olan ürünün birimi değiştirilemez (409).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Ürün Güncelle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Güncellenecek ürün bilgileri",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ürünü siler (sadece ürün sahibi). Ürünün görselleri depodan da silinir.",
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Ürün Sil",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/images": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ürüne bir veya birden fazla görsel yükler (sadece ürün sahibi). JPEG, PNG, GIF ve WebP kabul edilir; tür dosya içeriğinden tespit edilir. Her görsel için small (150px), medium (400px) ve large (800px) thumbnail üretilir. Görseller mevcutların sonuna eklenir.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Ürün Görseli Yükle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Görsel dosyaları (aynı alan adıyla birden fazla)",
+                        "name": "images",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/images/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ürün görsellerinin sırasını değiştirir (sadece ürün sahibi). Listede ürünün tüm görselleri bulunmalı; ilk görsel kapak görselidir.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Ürün Görsellerini Sırala",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Görsel ID'leri",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ReorderImagesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/images/{imageId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ürün görselini ve thumbnail'lerini siler (sadece ürün sahibi)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Ürün Görseli Sil",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Görsel ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/option-groups": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ürüne seçenekleriyle birlikte bir seçenek grubu ekler (ör. \"Ekstralar\": Ekstra peynir +10.00). Siparişte gruptan en az min_select, en fazla max_select seçenek seçilir (sadece ürün sahibi).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Seçenek Grubu Ekle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Seçenek grubu bilgileri",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OptionGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/option-groups/{groupId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Seçenek grubunu ve seçeneklerini günceller (sadece ürün sahibi). id'si verilen seçenekler güncellenir, id'siz olanlar eklenir, listede olmayanlar silinir.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Seçenek Grubu Güncelle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seçenek grubu ID",
+                        "name": "groupId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Seçenek grubu bilgileri",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.OptionGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Seçenek grubunu seçenekleriyle birlikte siler (sadece ürün sahibi). Geçmiş siparişler seçilen seçeneklerin kopyasını korur.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Seçenek Grubu Sil",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seçenek grubu ID",
+                        "name": "groupId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/stock/adjust": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sayım sonucunu (adjustment: count verilir, farkı kadar hareket yazılır) veya fireyi (spoilage: quantity kadar düşülür) stok defterine işler (sadece ürün sahibi). Sebep zorunludur.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Stok Düzelt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sayım veya fire",
+                        "name": "adjustment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.AdjustStockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sayım stokla aynı, hareket yazılmadı",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/stock/movements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ürünün ve çeşitlerinin stok defterini sayfalı olarak listeler (sadece ürün sahibi). Yanıttaki balances, her kalemin stok bakiyesini ve defter toplamını içerir.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Stok Hareketleri",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa numarası (varsayılan 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa başına kayıt (varsayılan 20, en fazla 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sıralama: id, created_at (azalan için başına -, varsayılan -id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hareket tipi, virgülle birden fazla verilebilir (sale, restock, adjustment, cancellation, spoilage)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Çeşit filtresi",
+                        "name": "variant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bu tarihten sonraki hareketler (2006-01-02 veya RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bu tarihe kadarki hareketler (2006-01-02 veya RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/stock/receive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gelen malı stoğa ekler ve stok defterine restock hareketi yazar (sadece ürün sahibi)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Mal Kabul",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Gelen mal",
+                        "name": "receipt",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.ReceiveStockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/variants": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ürünün pasifler dahil tüm çeşitlerini ve seçenek gruplarını getirir (sadece ürün sahibi)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Ürün Çeşitleri ve Seçenekleri",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ürüne kendi SKU, fiyat ve stoğu olan bir çeşit ekler (sadece ürün sahibi). Aktif çeşidi olan ürünler çeşit seçilerek sipariş edilir.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Çeşit Ekle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Çeşit bilgileri",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.VariantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/variants/{variantId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ürün çeşidini günceller (sadece ürün sahibi)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Çeşit Güncelle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Çeşit ID",
+                        "name": "variantId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Çeşit bilgileri",
+                        "name": "variant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.VariantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ürün çeşidini siler (sadece ürün sahibi). Geçmiş siparişler çeşidin adını ve SKU'sunu korur.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Çeşit Sil",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Ürün ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Çeşit ID",
+                        "name": "variantId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/shops": {
+            "get": {
+                "description": "Aktif olan esnafları sayfalı olarak listeler",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shops"
+                ],
+                "summary": "Tüm Esnafları Listele",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sayfa numarası (varsayılan 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa başına kayıt (varsayılan 20, en fazla 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sıralama: id, name, created_at (azalan için başına -)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Esnaf adında arama",
+                        "name": "q",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Yeni esnaf kaydı oluşturur (sadece shop rolündeki kullanıcılar)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shops"
+                ],
+                "summary": "Esnaf Oluştur",
+                "parameters": [
+                    {
+                        "description": "Esnaf bilgileri",
+                        "name": "shop",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateShopRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/shops/{id}": {
+            "get": {
+                "description": "Belirli bir esnafın detaylarını getirir",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shops"
+                ],
+                "summary": "Esnaf Detayı",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Esnaf ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Esnaf bilgilerini günceller",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shops"
+                ],
+                "summary": "Esnaf Güncelle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Esnaf ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Güncellenecek esnaf bilgileri",
+                        "name": "shop",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.CreateShopRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/shops/{id}/products": {
+            "get": {
+                "description": "Belirli bir esnafın ürünlerini sayfalı olarak listeler",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shops"
+                ],
+                "summary": "Esnafın Ürünlerini Listele",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Esnaf ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa numarası (varsayılan 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa başına kayıt (varsayılan 20, en fazla 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sıralama: id, name, price, stock, created_at (azalan için başına -)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "En düşük fiyat",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "En yüksek fiyat",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Sadece stokta olanlar (true) veya tükenenler (false)",
+                        "name": "in_stock",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Dükkanın webhook uç noktalarını listeler (sadece esnaflar)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Webhook Listesi",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sipariş olaylarının gönderileceği bir adres ekler (sadece esnaflar). Yanıttaki secret yalnızca bir kez gösterilir; gövdeler bu anahtarla HMAC-SHA256 imzalanır (X-Webhook-Signature: sha256=HMAC(secret, timestamp + \".\" + gövde)).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Webhook Oluştur",
+                "parameters": [
+                    {
+                        "description": "Adres ve abone olunan olaylar",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Webhook uç noktasını getirir (sadece esnaflar)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Webhook Detayı",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adresi, olayları, açıklamayı ve aktifliği günceller (sadece esnaflar). is_active verilmezse değişmez. Pasif uç noktalara teslimat yapılmaz.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Webhook Güncelle",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Adres ve abone olunan olaylar",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Webhook uç noktasını siler (sadece esnaflar). Bekleyen teslimatlar gönderilmez; teslimat kayıtları saklanır.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Webhook Sil",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uç noktanın teslimat kayıtlarını son denemenin yanıt koduyla sayfalı listeler (sadece esnaflar)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Webhook Teslimatları",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa numarası (varsayılan 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sayfa başına kayıt (varsayılan 20, en fazla 100)",
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sıralama: id, created_at (azalan için başına -, varsayılan -id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Durum filtresi: pending, succeeded, failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Olay tipi filtresi",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Başlangıç tarihi (2024-05-01 veya RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bitiş tarihi (2024-05-31, gün dahil, veya RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Teslimatı gönderilen gövde ve tüm denemeleriyle (yanıt kodu, yanıtın ilk 2 KB'ı, hata, süre) getirir (sadece esnaflar)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Webhook Teslimat Detayı",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Teslimat ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryId}/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Başarılı veya başarısız bir teslimatı aynı gövdeyle yeniden kuyruğa alır (sadece esnaflar). Deneme sayısı sıfırlanır; önceki denemeler kayıtlarda kalır.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Webhook Teslimatını Yeniden Gönder",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Teslimat ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/ping": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uç noktaya webhook.ping olayı gönderir (sadece esnaflar). Sonuç teslimat kayıtlarından izlenir.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Webhook Dene",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/rotate-secret": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Yeni bir imza anahtarı üretir; eski anahtar hemen geçersiz olur (sadece esnaflar). Yeni anahtar yalnızca bu yanıtta gösterilir.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Webhook Anahtarını Yenile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
                         "description": "Not Found",
                         "schema": {
                             "type": "object",
@@ -781,6 +4334,80 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "controllers.AdjustStockRequest": {
+            "type": "object",
+            "required": [
+                "reason",
+                "type"
+            ],
+            "properties": {
+                "count": {
+                    "description": "adjustment: rafta sayılan miktar",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "quantity": {
+                    "description": "spoilage: bozulan / atılan miktar",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Ay sonu sayımı"
+                },
+                "type": {
+                    "description": "adjustment (sayım) veya spoilage (fire)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.StockMovementType"
+                        }
+                    ],
+                    "example": "adjustment"
+                },
+                "variant_id": {
+                    "description": "Aktif çeşidi olan ürünlerde zorunlu",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.CancelOrderRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "controllers.CategoryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "sort_order": {
+                    "type": "integer"
+                }
+            }
+        },
         "controllers.CreateOrderRequest": {
             "type": "object",
             "required": [
@@ -790,57 +4417,325 @@ const docTemplate = `{
             "properties": {
                 "items": {
                     "type": "array",
-                    "minItems": 1,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/controllers.OrderItem"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "shop_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.CreateProductRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "price"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "description": "ISO 4217 kodu (varsayılan TRY)",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "min_quantity": {
+                    "description": "Verilmezse birimin varsayılanı",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "description": "Birim başına fiyat, en fazla 2 ondalık",
+                    "type": "number",
+                    "example": 12.5
+                },
+                "quantity_step": {
+                    "description": "Verilmezse birimin varsayılanı",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "reorder_threshold": {
+                    "description": "Stok bu değere düşünce uyarı oluşur; 0 ise yalnızca tükenince",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "sku": {
+                    "description": "İsteğe bağlı, dükkan içinde benzersiz; toplu içe aktarmada eşleştirme anahtarı",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "stock": {
+                    "description": "Birim cinsinden",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "unit": {
+                    "description": "piece, kg, g, litre, bunch (varsayılan piece)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Unit"
+                        }
+                    ]
+                }
+            }
+        },
+        "controllers.CreateShopRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "hide_out_of_stock": {
+                    "description": "Stoğu biten ürünleri müşteri listelerinden gizle. Oluştururken verilmezse\nfalse, güncellerken değişmez.",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "require_verified_customers": {
+                    "description": "Yalnızca e-postası veya telefonu doğrulanmış müşterilerden sipariş kabul et. Oluştururken\nverilmezse false, güncellerken değişmez.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "controllers.ForgotPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.ImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.ImportRowErrors"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.ImportRowErrors": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
                     "items": {
-                        "$ref": "#/definitions/controllers.OrderItem"
+                        "type": "string"
                     }
                 },
-                "note": {
+                "id": {
                     "type": "string"
                 },
-                "shop_id": {
+                "line": {
+                    "description": "Dosyadaki satır numarası (başlık 1. satır)",
                     "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
                 }
             }
         },
-        "controllers.CreateProductRequest": {
+        "controllers.LoginRequest": {
             "type": "object",
             "required": [
-                "name",
-                "price"
+                "email",
+                "password"
             ],
             "properties": {
-                "description": {
+                "email": {
                     "type": "string"
                 },
-                "image_url": {
+                "password": {
                     "type": "string"
+                }
+            }
+        },
+        "controllers.LogoutRequest": {
+            "type": "object",
+            "properties": {
+                "all_devices": {
+                    "description": "Kullanıcının tüm yenileme token'larını iptal eder",
+                    "type": "boolean"
                 },
-                "name": {
+                "refresh_token": {
                     "type": "string"
+                }
+            }
+        },
+        "controllers.NotificationPreferenceRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "boolean"
                 },
-                "price": {
-                    "type": "number"
+                "language": {
+                    "description": "tr, en",
+                    "type": "string"
                 },
-                "stock": {
+                "push": {
+                    "type": "boolean"
+                },
+                "sms": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "controllers.OptionGroupRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "max_select": {
+                    "description": "Verilmezse 1, 0 sınırsız",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "min_select": {
                     "type": "integer",
                     "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controllers.OptionRequest"
+                    }
+                },
+                "sort_order": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.CreateShopRequest": {
+        "controllers.OptionRequest": {
             "type": "object",
             "required": [
                 "name"
             ],
             "properties": {
-                "address": {
+                "id": {
+                    "description": "Güncellemede mevcut seçeneği korumak için; listede olmayan seçenekler silinir",
+                    "type": "integer"
+                },
+                "is_active": {
+                    "description": "Verilmezse true",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "price_modifier": {
+                    "description": "Birim fiyata eklenir, negatif olabilir",
+                    "type": "number",
+                    "example": 10
+                },
+                "sort_order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.OrderItem": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "option_ids": {
+                    "description": "Seçenek gruplarından seçilen seçenekler (ör. ekstra peynir)",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "description": "Ürünün birimi cinsinden; en az miktar ve artışa uymalı (ör. 0.5 kg)",
+                    "type": "number"
+                },
+                "variant_id": {
+                    "description": "Ürünün aktif çeşidi varsa zorunlu",
+                    "type": "integer"
+                }
+            }
+        },
+        "controllers.PhoneCodeRequest": {
+            "type": "object",
+            "required": [
+                "phone"
+            ],
+            "properties": {
+                "language": {
+                    "description": "SMS dili: tr (varsayılan), en",
                     "type": "string"
                 },
-                "description": {
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.PhoneLoginRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "phone"
+            ],
+            "properties": {
+                "code": {
                     "type": "string"
                 },
                 "name": {
+                    "description": "Numara kayıtlı değilse yeni hesap için gerekli",
                     "type": "string"
                 },
                 "phone": {
@@ -848,36 +4743,80 @@ const docTemplate = `{
                 }
             }
         },
-        "controllers.LoginRequest": {
+        "controllers.PhoneVerifyRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "code",
+                "password",
+                "phone"
             ],
             "properties": {
-                "email": {
+                "code": {
                     "type": "string"
                 },
                 "password": {
+                    "description": "Hesabın mevcut şifresi",
+                    "type": "string"
+                },
+                "phone": {
                     "type": "string"
                 }
             }
         },
-        "controllers.OrderItem": {
+        "controllers.PushDeviceRequest": {
             "type": "object",
             "required": [
-                "product_id",
-                "quantity"
+                "platform",
+                "token"
             ],
             "properties": {
-                "product_id": {
-                    "type": "integer"
+                "platform": {
+                    "description": "ios, android, web",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PushPlatform"
+                        }
+                    ]
                 },
+                "token": {
+                    "type": "string",
+                    "maxLength": 512
+                }
+            }
+        },
+        "controllers.ReceiveStockRequest": {
+            "type": "object",
+            "required": [
+                "quantity"
+            ],
+            "properties": {
                 "quantity": {
+                    "description": "Gelen miktar, ürünün birimi cinsinden",
+                    "type": "number"
+                },
+                "reason": {
+                    "description": "Ör. tedarikçi, irsaliye no",
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Sabah teslimatı"
+                },
+                "variant_id": {
+                    "description": "Aktif çeşidi olan ürünlerde zorunlu",
                     "type": "integer"
                 }
             }
         },
+        "controllers.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
         "controllers.RegisterRequest": {
             "type": "object",
             "required": [
@@ -901,10 +4840,246 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "role": {
-                    "$ref": "#/definitions/models.UserRole"
+                    "enum": [
+                        "customer",
+                        "shop"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.UserRole"
+                        }
+                    ]
+                }
+            }
+        },
+        "controllers.ReorderImagesRequest": {
+            "type": "object",
+            "required": [
+                "image_ids"
+            ],
+            "properties": {
+                "image_ids": {
+                    "description": "Ürünün tüm görselleri, istenen sırayla",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "controllers.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.UpdateOrderStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.OrderStatus"
+                }
+            }
+        },
+        "controllers.UpdateUserRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "enum": [
+                        "admin",
+                        "shop",
+                        "customer"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.UserRole"
+                        }
+                    ]
+                }
+            }
+        },
+        "controllers.VariantRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "price",
+                "sku"
+            ],
+            "properties": {
+                "is_active": {
+                    "description": "Oluştururken verilmezse true, güncellerken değişmez",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "price": {
+                    "description": "Çeşidin birim fiyatı",
+                    "type": "number",
+                    "example": 15
+                },
+                "sku": {
+                    "description": "Dükkan içinde benzersiz",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "description": "Ürünün birimi cinsinden",
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "controllers.VerifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.WebhookRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "events": {
+                    "description": "order.created, order.status_changed, stock.alert",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
         },
+        "models.OrderStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "confirmed",
+                "preparing",
+                "ready",
+                "delivered",
+                "cancelled"
+            ],
+            "x-enum-comments": {
+                "OrderStatusCancelled": "İptal edildi",
+                "OrderStatusConfirmed": "Onaylandı",
+                "OrderStatusDelivered": "Teslim edildi",
+                "OrderStatusPending": "Beklemede",
+                "OrderStatusPreparing": "Hazırlanıyor",
+                "OrderStatusReady": "Hazır"
+            },
+            "x-enum-varnames": [
+                "OrderStatusPending",
+                "OrderStatusConfirmed",
+                "OrderStatusPreparing",
+                "OrderStatusReady",
+                "OrderStatusDelivered",
+                "OrderStatusCancelled"
+            ]
+        },
+        "models.PushPlatform": {
+            "type": "string",
+            "enum": [
+                "ios",
+                "android",
+                "web"
+            ],
+            "x-enum-varnames": [
+                "PlatformIOS",
+                "PlatformAndroid",
+                "PlatformWeb"
+            ]
+        },
+        "models.StockMovementType": {
+            "type": "string",
+            "enum": [
+                "sale",
+                "restock",
+                "adjustment",
+                "cancellation",
+                "spoilage"
+            ],
+            "x-enum-comments": {
+                "StockMovementAdjustment": "Sayım düzeltmesi",
+                "StockMovementCancellation": "İptal edilen siparişin iadesi",
+                "StockMovementRestock": "Mal kabul",
+                "StockMovementSale": "Satış",
+                "StockMovementSpoilage": "Fire / bozulma"
+            },
+            "x-enum-varnames": [
+                "StockMovementSale",
+                "StockMovementRestock",
+                "StockMovementAdjustment",
+                "StockMovementCancellation",
+                "StockMovementSpoilage"
+            ]
+        },
+        "models.Unit": {
+            "type": "string",
+            "enum": [
+                "piece",
+                "kg",
+                "g",
+                "litre",
+                "bunch"
+            ],
+            "x-enum-comments": {
+                "UnitBunch": "Bağ",
+                "UnitGram": "Gram",
+                "UnitKg": "Kilogram",
+                "UnitLitre": "Litre",
+                "UnitPiece": "Adet"
+            },
+            "x-enum-varnames": [
+                "UnitPiece",
+                "UnitKg",
+                "UnitGram",
+                "UnitLitre",
+                "UnitBunch"
+            ]
+        },
         "models.UserRole": {
             "type": "string",
             "enum": [
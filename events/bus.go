@@ -0,0 +1,150 @@
+// Package events kayıtlardaki değişiklikleri (yeni sipariş, durum değişikliği,
+// stok uyarısı) süreç içinde abonelere dağıtan olay veri yolunu içerir.
+//
+// Olaylar bellekte tutulur: son olaylar yeniden bağlanan abonelere Last-Event-ID
+// ile tekrar gönderilmek üzere sınırlı bir geçmişte saklanır. Olay ID'leri
+// başlangıç zamanından türetildiği için yeniden başlatmadan sonra da artmaya
+// devam eder; eski süreçten gelen bir ID geçmişte bulunamaz ve abone olayların
+// kaçırıldığını öğrenir. Birden fazla sunucu çalıştırılıyorsa her biri yalnızca
+// kendi yayınladığı olayları dağıtır.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Olay tipleri
+const (
+	OrderCreated       = "order.created"
+	OrderStatusChanged = "order.status_changed"
+	StockAlert         = "stock.alert"
+)
+
+const (
+	defaultHistorySize = 1000
+	// Aboneye iletilemeyen olay sayısı bunu aşarsa abonelik kapatılır; istemci
+	// Last-Event-ID ile yeniden bağlanıp kaçırdıklarını alır.
+	subscriberBuffer = 64
+)
+
+type Event struct {
+	ID        uint64      `json:"id"`
+	Type      string      `json:"type"`
+	ShopID    uint        `json:"-"` // Olayın ilgili olduğu dükkan
+	UserID    uint        `json:"-"` // Olayın ilgili olduğu müşteri
+	Data      interface{} `json:"data"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Filter abonenin görmesi gereken olaylar için true döner.
+type Filter func(Event) bool
+
+type Bus struct {
+	mu      sync.Mutex
+	lastID  uint64
+	history []Event
+	size    int
+	subs    map[*Subscription]struct{}
+}
+
+// Subscription olayları C kanalından okur. Abonelik Close ile veya abone
+// olaylara yetişemediğinde kapatılır; her iki durumda da C kapanır.
+type Subscription struct {
+	C      <-chan Event
+	ch     chan Event
+	filter Filter
+	bus    *Bus
+}
+
+// Replay abonelik anında yeniden gönderilecek olaylar. Complete false ise
+// istenen ID'den sonraki olayların bir kısmı geçmişte yoktur; Events boştur ve
+// istemci durumu baştan yüklemelidir. LastID abonelik anındaki son olayın ID'sidir.
+type Replay struct {
+	Events   []Event
+	Complete bool
+	LastID   uint64
+}
+
+var Default = NewBus(defaultHistorySize)
+
+func NewBus(historySize int) *Bus {
+	return &Bus{
+		lastID: uint64(time.Now().UnixMilli()) * 1000,
+		size:   historySize,
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish olaya ID verir, geçmişe ekler ve filtresi uyan abonelere gönderir.
+// Olayı beklemeden döner; Data yayınlandıktan sonra değiştirilmemelidir.
+func (b *Bus) Publish(e Event) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	e.ID = b.lastID
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+
+	if len(b.history) == b.size {
+		copy(b.history, b.history[1:])
+		b.history = b.history[:b.size-1]
+	}
+	b.history = append(b.history, e)
+
+	for s := range b.subs {
+		if !s.filter(e) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			b.remove(s)
+		}
+	}
+	return e
+}
+
+// Subscribe filtreye uyan olaylar için abonelik açar. lastID verilirse
+// (Last-Event-ID) ondan sonraki olaylar Replay'de döner; geçmiş ile abonelik
+// arasında olay kaçmaz.
+func (b *Bus) Subscribe(filter Filter, lastID uint64) (*Subscription, Replay) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	replay := Replay{Complete: true, LastID: b.lastID}
+	if lastID > 0 {
+		// Geçmişteki ID'ler ardışıktır
+		oldest := b.lastID - uint64(len(b.history)) + 1
+		if lastID > b.lastID || lastID+1 < oldest {
+			replay.Complete = false
+			lastID = b.lastID
+		}
+		for _, e := range b.history {
+			if e.ID > lastID && filter(e) {
+				replay.Events = append(replay.Events, e)
+			}
+		}
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	s := &Subscription{C: ch, ch: ch, filter: filter, bus: b}
+	b.subs[s] = struct{}{}
+	return s, replay
+}
+
+// Close aboneliği kapatır; birden fazla çağrılabilir.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.remove(s)
+}
+
+func (b *Bus) remove(s *Subscription) {
+	if _, ok := b.subs[s]; ok {
+		delete(b.subs, s)
+		close(s.ch)
+	}
+}
@@ -0,0 +1,50 @@
+package inventory
+
+import (
+	"tradesman-api/models"
+
+	"gorm.io/gorm"
+)
+
+// checkThreshold stok çıkışı kalemi yeniden sipariş eşiğinin üstünden eşiğe
+// veya altına ya da sıfıra düşürdüyse uyarı kaydeder ve m.Alert'e yazar. Uyarı
+// hareketle aynı transaction'da yazılır; hareket geri alınırsa uyarı da
+// oluşmaz. Olay ve bildirim çağıran tarafından commit sonrası gönderilir.
+func checkThreshold(tx *gorm.DB, m *models.StockMovement, balance float64) error {
+	if m.Quantity >= 0 {
+		return nil
+	}
+	before := models.RoundQuantity(balance - m.Quantity)
+
+	var thresholds []float64
+	err := tx.Unscoped().Model(&models.Product{}).Where("id = ?", m.ProductID).Pluck("reorder_threshold", &thresholds).Error
+	if err != nil || len(thresholds) == 0 {
+		return err
+	}
+	threshold := models.RoundQuantity(thresholds[0])
+
+	var kind models.StockAlertKind
+	switch {
+	case balance <= 0 && before > 0:
+		kind = models.StockAlertOutOfStock
+	case threshold > 0 && balance <= threshold && before > threshold:
+		kind = models.StockAlertLow
+	default:
+		return nil
+	}
+
+	alert := models.StockAlert{
+		ShopID:       m.ShopID,
+		ProductID:    m.ProductID,
+		VariantID:    m.VariantID,
+		Kind:         kind,
+		Stock:        balance,
+		Threshold:    threshold,
+		MovementType: m.Type,
+	}
+	if err := tx.Create(&alert).Error; err != nil {
+		return err
+	}
+	m.Alert = &alert
+	return nil
+}
@@ -0,0 +1,52 @@
+package inventory
+
+import (
+	"tradesman-api/models"
+
+	"gorm.io/gorm"
+)
+
+// Discrepancy stok kolonu defterdeki hareketlerin toplamından farklı olan kalem.
+type Discrepancy struct {
+	ProductID uint
+	VariantID *uint
+	Stock     float64 // Stok kolonundaki bakiye
+	Ledger    float64 // Hareketlerin toplamı
+}
+
+const (
+	productLedgerQuery = `SELECT p.id AS product_id, NULL AS variant_id, p.stock AS stock, COALESCE(SUM(m.quantity), 0) AS ledger
+		FROM products p LEFT JOIN stock_movements m ON m.product_id = p.id AND m.variant_id IS NULL
+		WHERE p.deleted_at IS NULL
+		GROUP BY p.id, p.stock
+		HAVING ROUND(p.stock - COALESCE(SUM(m.quantity), 0), 3) <> 0`
+
+	variantLedgerQuery = `SELECT v.product_id AS product_id, v.id AS variant_id, v.stock AS stock, COALESCE(SUM(m.quantity), 0) AS ledger
+		FROM product_variants v LEFT JOIN stock_movements m ON m.variant_id = v.id
+		GROUP BY v.id, v.product_id, v.stock
+		HAVING ROUND(v.stock - COALESCE(SUM(m.quantity), 0), 3) <> 0`
+)
+
+// Check stok kolonlarını defterle karşılaştırır ve tutmayan kalemleri döner.
+// Silinmiş ürünler atlanır.
+func Check(db *gorm.DB) ([]Discrepancy, error) {
+	var discrepancies []Discrepancy
+	for _, query := range []string{productLedgerQuery, variantLedgerQuery} {
+		var found []Discrepancy
+		if err := db.Raw(query).Scan(&found).Error; err != nil {
+			return nil, err
+		}
+		discrepancies = append(discrepancies, found...)
+	}
+	for i := range discrepancies {
+		discrepancies[i].Stock = models.RoundQuantity(discrepancies[i].Stock)
+		discrepancies[i].Ledger = models.RoundQuantity(discrepancies[i].Ledger)
+	}
+	return discrepancies, nil
+}
+
+// Fix stok kolonunu defterdeki toplama eşitler. tx içinde çağrılmalıdır.
+func Fix(tx *gorm.DB, d Discrepancy) error {
+	model, id := holder(&models.StockMovement{ProductID: d.ProductID, VariantID: d.VariantID})
+	return tx.Model(model).Where("id = ?", id).Update("stock", d.Ledger).Error
+}
@@ -0,0 +1,113 @@
+// Package inventory stok değişikliklerini stok defterine (stock_movements)
+// yazarak uygular.
+//
+// Stok kolonları (products.stock, product_variants.stock) hızlı okuma için
+// tutulan bakiyelerdir; doğrusu defterdir. Stoğu değiştiren her işlem (satış,
+// iptal iadesi, mal kabul, sayım, fire) bu paketten geçer; böylece bir kalemin
+// hareketlerinin toplamı her zaman stok kolonuna eşit kalır. Tutarlılık Check
+// ile denetlenir. Stok çıkışları ürünün yeniden sipariş eşiğini geçtiğinde
+// stock_alerts tablosuna uyarı yazılır.
+package inventory
+
+import (
+	"errors"
+	"tradesman-api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientStock = errors.New("yetersiz stok")
+	ErrItemNotFound      = errors.New("stok kalemi bulunamadı")
+)
+
+// Miktar parametreleri stok kolonuyla aynı tipe çevrilir; Postgres'te numeric
+// aritmetik kesin kalır, SQLite'ta ROUND ile kayan nokta birikimi önlenir.
+const quantityParam = "CAST(? AS DECIMAL(12,3))"
+
+// holder hareketin stoğunu tutan tabloyu ve satırı döner: çeşit varsa çeşit,
+// yoksa ürün.
+func holder(m *models.StockMovement) (interface{}, uint) {
+	if m.VariantID != nil {
+		return &models.ProductVariant{}, *m.VariantID
+	}
+	return &models.Product{}, m.ProductID
+}
+
+// Move stoğu m.Quantity kadar değiştirir ve m.BalanceAfter'ı doldurur; hareketi
+// kaydetmez, ancak yeniden sipariş eşiği geçildiyse uyarıyı kaydeder. Çıkışlar
+// koşullu tek sorguyla yapılır, stok yetersizse ErrInsufficientStock döner ve
+// eşzamanlı işlemler stoğu eksiye düşüremez. Girişler silinmiş ürünlere de
+// yapılabilir (iptal iadesi). tx içinde çağrılmalıdır.
+func Move(tx *gorm.DB, m *models.StockMovement) error {
+	m.Quantity = models.RoundQuantity(m.Quantity)
+	model, id := holder(m)
+
+	var result *gorm.DB
+	if m.Quantity < 0 {
+		result = tx.Model(model).
+			Where("id = ? AND stock >= "+quantityParam, id, -m.Quantity).
+			Update("stock", gorm.Expr("ROUND(stock + "+quantityParam+", 3)", m.Quantity))
+	} else {
+		result = tx.Unscoped().Model(model).
+			Where("id = ?", id).
+			Update("stock", gorm.Expr("ROUND(stock + "+quantityParam+", 3)", m.Quantity))
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+
+	balance, err := Balance(tx, m.ProductID, m.VariantID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+	m.BalanceAfter = balance
+	return checkThreshold(tx, m, balance)
+}
+
+// Record stoğu değiştirir ve hareketi deftere yazar.
+func Record(tx *gorm.DB, m *models.StockMovement) error {
+	if err := Move(tx, m); err != nil {
+		return err
+	}
+	return tx.Create(m).Error
+}
+
+// SetCount sayılan miktarı stok olarak kaydeder: mevcut stokla arasındaki fark
+// kadar bir hareket yazar. Fark yoksa hareket yazılmaz ve false döner.
+func SetCount(tx *gorm.DB, m *models.StockMovement, count float64) (bool, error) {
+	model, id := holder(m)
+
+	var current float64
+	query := tx.Unscoped().Model(model)
+	if tx.Dialector.Name() == "postgres" {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	if err := query.Where("id = ?", id).Select("stock").Scan(&current).Error; err != nil {
+		return false, err
+	}
+
+	m.Quantity = models.RoundQuantity(count - current)
+	if m.Quantity == 0 {
+		return false, nil
+	}
+	return true, Record(tx, m)
+}
+
+// Balance kalemin stok kolonundaki bakiyeyi döner; kalem yoksa ErrItemNotFound.
+func Balance(tx *gorm.DB, productID uint, variantID *uint) (float64, error) {
+	model, id := holder(&models.StockMovement{ProductID: productID, VariantID: variantID})
+
+	var stocks []float64
+	if err := tx.Unscoped().Model(model).Where("id = ?", id).Pluck("stock", &stocks).Error; err != nil {
+		return 0, err
+	}
+	if len(stocks) == 0 {
+		return 0, ErrItemNotFound
+	}
+	return models.RoundQuantity(stocks[0]), nil
+}
@@ -22,23 +22,14 @@ package main
 
 import (
 	"log"
-	"tradesman-api/config"
+	"os"
+	"tradesman-api/cli"
 	_ "tradesman-api/docs" // Swagger docs
-	"tradesman-api/routes"
 )
 
 func main() {
-	// Veritabanı bağlantısı
-	config.InitDatabase()
-
-	// Routes kurulumu
-	r := routes.SetupRoutes()
-
-	// Server başlat
-	log.Println("🚀 Server başlatılıyor... http://localhost:8080")
-	log.Println("📚 Swagger dokümantasyonu: http://localhost:8080/swagger/index.html")
-
-	if err := r.Run(":8080"); err != nil {
-		log.Fatal("Server başlatılamadı:", err)
+	// Alt komutlar: serve (varsayılan), migrate, seed, create-admin, routes
+	if err := cli.Run(os.Args[1:]); err != nil {
+		log.Fatal(err)
 	}
 }
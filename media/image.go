@@ -0,0 +1,130 @@
+// Package media yüklenen görselleri doğrular ve küçük boyutlu kopyalarını
+// (thumbnail) üretir.
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // GIF çözümleyici
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // WebP çözümleyici
+)
+
+var (
+	ErrUnsupportedImage = errors.New("desteklenmeyen görsel türü, JPEG, PNG, GIF veya WebP olmalı")
+	ErrImageDimensions  = fmt.Errorf("görsel en fazla %d megapiksel olabilir", maxPixels/1_000_000)
+)
+
+// Çözülmeden önce boyutu kontrol edilir; küçük bir dosya devasa bir görsel olabilir.
+const maxPixels = 40_000_000
+
+// Yüklenebilen türler ve orijinal dosyanın uzantısı
+var contentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Thumbnail'ler en-boy oranı korunarak Max×Max kutuya sığdırılır, büyütülmez.
+type ThumbnailSize struct {
+	Name string
+	Max  int
+}
+
+var ThumbnailSizes = []ThumbnailSize{
+	{"small", 150},
+	{"medium", 400},
+	{"large", 800},
+}
+
+type Image struct {
+	ContentType string // İçerikten tespit edilen tür; istemcinin bildirdiği türe güvenilmez
+	Ext         string
+	Width       int
+	Height      int
+	Thumbnails  []Thumbnail
+}
+
+type Thumbnail struct {
+	Size   string
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// Process görseli içeriğinden doğrular, çözer ve ThumbnailSizes'daki her boyut
+// için bir kopya üretir.
+func Process(data []byte) (*Image, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := contentTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedImage
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrImageDimensions
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	img := &Image{ContentType: contentType, Ext: ext, Width: cfg.Width, Height: cfg.Height}
+	for _, size := range ThumbnailSizes {
+		thumb, err := resize(src, size.Max, contentType)
+		if err != nil {
+			return nil, err
+		}
+		thumb.Size = size.Name
+		img.Thumbnails = append(img.Thumbnails, thumb)
+	}
+	return img, nil
+}
+
+// ThumbnailExt thumbnail dosyalarının uzantısını döner. JPEG'ler JPEG kalır;
+// saydamlık içerebilen diğer türler PNG'ye çevrilir.
+func ThumbnailExt(contentType string) string {
+	if contentType == "image/jpeg" {
+		return ".jpg"
+	}
+	return ".png"
+}
+
+func resize(src image.Image, box int, contentType string) (Thumbnail, error) {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > box || h > box {
+		if w >= h {
+			w, h = box, max(1, h*box/w)
+		} else {
+			w, h = max(1, w*box/h), box
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+
+	var buf bytes.Buffer
+	var err error
+	if ThumbnailExt(contentType) == ".jpg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return Thumbnail{}, err
+	}
+	return Thumbnail{Data: buf.Bytes(), Width: w, Height: h}, nil
+}
@@ -3,14 +3,13 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"tradesman-api/config"
 	"tradesman-api/models"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = []byte("super-secret-key-change-in-production")
-
 type Claims struct {
 	UserID uint            `json:"user_id"`
 	Email  string          `json:"email"`
@@ -30,8 +29,8 @@ func AuthMiddleware() gin.HandlerFunc {
 		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
 
 		token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-			return jwtSecret, nil
-		})
+			return []byte(config.App.JWTSecret), nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Geçersiz token"})
@@ -39,16 +38,55 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if claims, ok := token.Claims.(*Claims); ok {
-			c.Set("user_id", claims.UserID)
-			c.Set("user_email", claims.Email)
-			c.Set("user_role", claims.Role)
-			c.Next()
-		} else {
+		claims, ok := token.Claims.(*Claims)
+		if !ok {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token claims okunamadı"})
 			c.Abort()
 			return
 		}
+
+		// İptal edilmiş token kontrolü (logout)
+		if claims.ID != "" {
+			var count int64
+			if err := config.DB.Model(&models.RevokedToken{}).Where("jti = ?", claims.ID).Count(&count).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Token doğrulanamadı"})
+				c.Abort()
+				return
+			}
+			if count > 0 {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Token iptal edilmiş"})
+				c.Abort()
+				return
+			}
+		}
+
+		// Kullanıcı hâlâ var mı ve askıya alınmış mı kontrolü
+		var user models.User
+		if err := config.DB.Select("id", "email", "role", "is_suspended", "password_changed_at").First(&user, claims.UserID).Error; err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Kullanıcı bulunamadı"})
+			c.Abort()
+			return
+		}
+
+		// Şifre değişmeden önce verilen token'lar (ör. çalınmış olanlar) geçersiz
+		if user.PasswordChangedAt != nil && (claims.IssuedAt == nil || claims.IssuedAt.Before(*user.PasswordChangedAt)) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Şifre değiştirildi, tekrar giriş yapın"})
+			c.Abort()
+			return
+		}
+
+		if user.IsSuspended {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Hesabınız askıya alınmış"})
+			c.Abort()
+			return
+		}
+
+		// Rol değişiklikleri token yenilenmeden geçerli olsun diye veritabanındaki rol kullanılır
+		c.Set("user_id", user.ID)
+		c.Set("user_email", user.Email)
+		c.Set("user_role", user.Role)
+		c.Set("token_claims", claims)
+		c.Next()
 	}
 }
 
@@ -83,3 +121,22 @@ func GetUserRole(c *gin.Context) models.UserRole {
 	userRole, _ := c.Get("user_role")
 	return userRole.(models.UserRole)
 }
+
+func GetClaims(c *gin.Context) *Claims {
+	claims, _ := c.Get("token_claims")
+	return claims.(*Claims)
+}
+
+// TokenFromQuery Authorization başlığı yoksa access_token sorgu parametresini
+// Bearer token olarak kullanır. Tarayıcıdaki EventSource ve WebSocket
+// istemcileri başlık gönderemediği için yalnızca akış uçlarında kullanılır.
+func TokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+	"tradesman-api/config"
+	"tradesman-api/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency, Idempotency-Key başlığı ile gelen isteklerin yanıtını saklar ve aynı
+// anahtarla tekrar gelen isteklerde handler'ı çalıştırmadan ilk yanıtı döner.
+// Aynı anahtar farklı bir istek gövdesiyle kullanılırsa 422 döner. Anahtarlar
+// kullanıcıya özeldir ve IdempotencyTTL sonunda silinir. AuthMiddleware'den sonra kullanılmalıdır.
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > 255 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key en fazla 255 karakter olabilir"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "İstek gövdesi okunamadı"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(append([]byte(c.Request.Method+" "+c.FullPath()+"\n"), body...))
+		requestHash := hex.EncodeToString(sum[:])
+		userID := GetUserID(c)
+		now := time.Now()
+
+		// Süresi dolmuş anahtarları temizle
+		config.DB.Where("expires_at < ?", now).Delete(&models.IdempotencyKey{})
+
+		record := models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: requestHash,
+			ExpiresAt:   now.Add(config.App.IdempotencyTTL),
+		}
+		result := config.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Idempotency-Key kaydedilemedi"})
+			c.Abort()
+			return
+		}
+
+		// Anahtar daha önce kullanılmış
+		if result.RowsAffected == 0 {
+			var existing models.IdempotencyKey
+			if err := config.DB.Where("user_id = ? AND key = ?", userID, key).First(&existing).Error; err != nil {
+				c.JSON(http.StatusConflict, gin.H{"error": "Idempotency-Key işlenemedi, tekrar deneyin"})
+				c.Abort()
+				return
+			}
+
+			if existing.RequestHash != requestHash {
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Bu Idempotency-Key farklı bir istek için kullanılmış"})
+				c.Abort()
+				return
+			}
+
+			if existing.CompletedAt == nil {
+				c.JSON(http.StatusConflict, gin.H{"error": "Bu Idempotency-Key ile gönderilen istek hâlâ işleniyor"})
+				c.Abort()
+				return
+			}
+
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(existing.StatusCode, "application/json; charset=utf-8", []byte(existing.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// Handler paniklerse anahtar silinir, yoksa istemci TTL dolana kadar 409 alır.
+		// Panik yeniden fırlatılır; 500 yanıtını Recovery yazar.
+		defer func() {
+			if err := recover(); err != nil {
+				config.DB.Delete(&record)
+				panic(err)
+			}
+		}()
+		c.Next()
+
+		// Sunucu hatalarında anahtar silinir, istemci aynı anahtarla tekrar deneyebilir
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			config.DB.Delete(&record)
+			return
+		}
+
+		completedAt := time.Now()
+		config.DB.Model(&record).Updates(map[string]interface{}{
+			"status_code":   status,
+			"response_body": recorder.body.String(),
+			"completed_at":  completedAt,
+		})
+	}
+}
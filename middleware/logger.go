@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Logger gin'in varsayılan istek günlüğünü yazar, ancak yoldaki access_token
+// parametresinin değerini gizler. Akış uçları token'ı URL'de aldığı için
+// gin.Logger günlüklere geçerli access token'ları yazardı.
+func Logger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		var statusColor, methodColor, resetColor string
+		if param.IsOutputColor() {
+			statusColor = param.StatusCodeColor()
+			methodColor = param.MethodColor()
+			resetColor = param.ResetColor()
+		}
+
+		if param.Latency > time.Minute {
+			param.Latency = param.Latency.Truncate(time.Second)
+		}
+		return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+			param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			statusColor, param.StatusCode, resetColor,
+			param.Latency,
+			param.ClientIP,
+			methodColor, param.Method, resetColor,
+			redactQuery(param.Path),
+			param.ErrorMessage,
+		)
+	})
+}
+
+// redactQuery yoldaki access_token değerlerini gizler; diğer parametreler
+// olduğu gibi kalır. Anahtar, c.Query gibi çözülerek karşılaştırılır.
+func redactQuery(path string) string {
+	i := strings.IndexByte(path, '?')
+	if i < 0 {
+		return path
+	}
+	params := strings.Split(path[i+1:], "&")
+	for j, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil && name == "access_token" {
+			params[j] = key + "=REDACTED"
+		}
+	}
+	return path[:i+1] + strings.Join(params, "&")
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLoggerRedactsAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var out bytes.Buffer
+	previous := gin.DefaultWriter
+	gin.DefaultWriter = &out
+	t.Cleanup(func() { gin.DefaultWriter = previous })
+
+	r := gin.New()
+	r.Use(Logger())
+	r.GET("/orders/stream", TokenFromQuery(), func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "Bearer gizli.jwt.token" {
+			t.Errorf("Authorization başlığı %q", c.GetHeader("Authorization"))
+		}
+		c.Status(http.StatusOK)
+	})
+
+	paths := []string{
+		"/orders/stream?access_token=gizli.jwt.token&last_event_id=42",
+		"/orders/stream?last_event_id=42&access%5Ftoken=gizli.jwt.token",
+	}
+	for _, path := range paths {
+		out.Reset()
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+
+		line := out.String()
+		if strings.Contains(line, "gizli.jwt.token") {
+			t.Errorf("token günlüğe yazıldı: %s", line)
+		}
+		if !strings.Contains(line, "REDACTED") || !strings.Contains(line, "last_event_id=42") {
+			t.Errorf("günlük satırı beklenen gibi değil: %s", line)
+		}
+	}
+}
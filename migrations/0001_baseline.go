@@ -0,0 +1,151 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Baseline: AutoMigrate ile oluşturulmuş mevcut şemanın birebir karşılığı.
+// Tablolar zaten varsa eksik kolon ve indeksler tamamlanır, veri korunur.
+
+type baselineUser struct {
+	ID          uint   `gorm:"primaryKey"`
+	Email       string `gorm:"uniqueIndex;not null"`
+	Password    string `gorm:"not null"`
+	Name        string `gorm:"not null"`
+	Phone       string
+	Role        string `gorm:"type:varchar(20);default:'customer'"`
+	IsSuspended bool   `gorm:"default:false"`
+	SuspendedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+
+	Shop   *baselineShop   `gorm:"foreignKey:UserID"`
+	Orders []baselineOrder `gorm:"foreignKey:UserID"`
+}
+
+func (baselineUser) TableName() string { return "users" }
+
+type baselineShop struct {
+	ID          uint   `gorm:"primaryKey"`
+	UserID      uint   `gorm:"not null;uniqueIndex"`
+	Name        string `gorm:"not null"`
+	Description string
+	Address     string
+	Phone       string
+	IsActive    bool `gorm:"default:true"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+
+	User     baselineUser      `gorm:"foreignKey:UserID"`
+	Products []baselineProduct `gorm:"foreignKey:ShopID"`
+	Orders   []baselineOrder   `gorm:"foreignKey:ShopID"`
+}
+
+func (baselineShop) TableName() string { return "shops" }
+
+type baselineProduct struct {
+	ID          uint   `gorm:"primaryKey"`
+	ShopID      uint   `gorm:"not null;index"`
+	Name        string `gorm:"not null"`
+	Description string
+	Price       float64 `gorm:"not null"`
+	Stock       int     `gorm:"default:0"`
+	IsActive    bool    `gorm:"default:true"`
+	ImageURL    string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+
+	Shop       baselineShop        `gorm:"foreignKey:ShopID"`
+	OrderItems []baselineOrderItem `gorm:"foreignKey:ProductID"`
+}
+
+func (baselineProduct) TableName() string { return "products" }
+
+type baselineOrder struct {
+	ID          uint    `gorm:"primaryKey"`
+	UserID      uint    `gorm:"not null;index"`
+	ShopID      uint    `gorm:"not null;index"`
+	TotalAmount float64 `gorm:"not null"`
+	Status      string  `gorm:"type:varchar(20);default:'pending'"`
+	Note        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+
+	User       baselineUser        `gorm:"foreignKey:UserID"`
+	Shop       baselineShop        `gorm:"foreignKey:ShopID"`
+	OrderItems []baselineOrderItem `gorm:"foreignKey:OrderID"`
+}
+
+func (baselineOrder) TableName() string { return "orders" }
+
+type baselineOrderItem struct {
+	ID        uint    `gorm:"primaryKey"`
+	OrderID   uint    `gorm:"not null;index"`
+	ProductID uint    `gorm:"not null;index"`
+	Quantity  int     `gorm:"not null"`
+	Price     float64 `gorm:"not null"`
+	CreatedAt time.Time
+
+	Order   baselineOrder   `gorm:"foreignKey:OrderID"`
+	Product baselineProduct `gorm:"foreignKey:ProductID"`
+}
+
+func (baselineOrderItem) TableName() string { return "order_items" }
+
+type baselineRefreshToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	FamilyID  string    `gorm:"type:varchar(64);not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	RevokedAt *time.Time
+	CreatedAt time.Time
+
+	User baselineUser `gorm:"foreignKey:UserID"`
+}
+
+func (baselineRefreshToken) TableName() string { return "refresh_tokens" }
+
+type baselineRevokedToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	JTI       string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+func (baselineRevokedToken) TableName() string { return "revoked_tokens" }
+
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "baseline",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(
+				&baselineUser{},
+				&baselineShop{},
+				&baselineProduct{},
+				&baselineOrder{},
+				&baselineOrderItem{},
+				&baselineRefreshToken{},
+				&baselineRevokedToken{},
+			)
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(
+				&baselineRevokedToken{},
+				&baselineRefreshToken{},
+				&baselineOrderItem{},
+				&baselineOrder{},
+				&baselineProduct{},
+				&baselineShop{},
+				&baselineUser{},
+			)
+		},
+	})
+}
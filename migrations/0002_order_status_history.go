@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type orderStatusHistory0002 struct {
+	ID          uint   `gorm:"primaryKey"`
+	OrderID     uint   `gorm:"not null;index"`
+	FromStatus  string `gorm:"type:varchar(20)"`
+	ToStatus    string `gorm:"type:varchar(20);not null"`
+	ChangedByID uint   `gorm:"not null;index"`
+	Note        string
+	CreatedAt   time.Time
+
+	Order     baselineOrder `gorm:"foreignKey:OrderID"`
+	ChangedBy baselineUser  `gorm:"foreignKey:ChangedByID"`
+}
+
+func (orderStatusHistory0002) TableName() string { return "order_status_histories" }
+
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "order_status_history",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&orderStatusHistory0002{}); err != nil {
+				return err
+			}
+
+			// Mevcut siparişler için başlangıç kaydı: sipariş sahibi tarafından oluşturuldu
+			return tx.Exec(`INSERT INTO order_status_histories (order_id, from_status, to_status, changed_by_id, note, created_at)
+				SELECT id, '', status, user_id, 'Geçmiş kaydı öncesi oluşturulan sipariş', created_at FROM orders`).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&orderStatusHistory0002{})
+		},
+	})
+}
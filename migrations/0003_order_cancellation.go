@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type order0003 struct {
+	CancellationReason string
+	CancelledAt        *time.Time
+	CancelledByID      *uint
+}
+
+func (order0003) TableName() string { return "orders" }
+
+var order0003Columns = []string{"CancellationReason", "CancelledAt", "CancelledByID"}
+
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "order_cancellation",
+		Up: func(tx *gorm.DB) error {
+			for _, column := range order0003Columns {
+				if err := tx.Migrator().AddColumn(&order0003{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return keepIndexes(tx, "orders", func() error {
+				for _, column := range order0003Columns {
+					if err := tx.Migrator().DropColumn(&order0003{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	})
+}
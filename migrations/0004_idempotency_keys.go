@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type idempotencyKey0004 struct {
+	ID           uint   `gorm:"primaryKey"`
+	UserID       uint   `gorm:"not null;uniqueIndex:idx_idempotency_keys_user_key"`
+	Key          string `gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_user_key"`
+	RequestHash  string `gorm:"type:varchar(64);not null"`
+	StatusCode   int
+	ResponseBody string
+	CompletedAt  *time.Time
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+func (idempotencyKey0004) TableName() string { return "idempotency_keys" }
+
+func init() {
+	register(Migration{
+		Version: 4,
+		Name:    "idempotency_keys",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&idempotencyKey0004{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&idempotencyKey0004{})
+		},
+	})
+}
@@ -0,0 +1,92 @@
+package migrations
+
+import (
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SQLite'ta FTS5 yalnızca sqlite_fts5 build tag'i ile derlenmişse vardır
+// (Makefile varsayılan olarak ekler); yoksa her sqlite3 derlemesinde bulunan
+// FTS4 kullanılır. İndeks, search paketinin o anki katlama kurallarının
+// kopyasıyla mevcut ürünlerden doldurulur.
+
+var fold0005 = strings.NewReplacer(
+	"İ", "i", "I", "i", "ı", "i",
+	"Ş", "s", "ş", "s",
+	"Ğ", "g", "ğ", "g",
+	"Ç", "c", "ç", "c",
+	"Ö", "o", "ö", "o",
+	"Ü", "u", "ü", "u",
+	"Â", "a", "â", "a",
+	"Î", "i", "î", "i",
+	"Û", "u", "û", "u",
+)
+
+func init() {
+	register(Migration{
+		Version: 5,
+		Name:    "product_search",
+		Up: func(tx *gorm.DB) error {
+			postgres := tx.Dialector.Name() == "postgres"
+			if postgres {
+				if err := tx.Exec(`CREATE TABLE product_search (
+					product_id bigint PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+					document tsvector NOT NULL
+				)`).Error; err != nil {
+					return err
+				}
+				if err := tx.Exec("CREATE INDEX idx_product_search_document ON product_search USING GIN (document)").Error; err != nil {
+					return err
+				}
+			} else {
+				var fts5 bool
+				if err := tx.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5).Error; err != nil {
+					return err
+				}
+				module := "fts4(name, description, tokenize=unicode61)"
+				if fts5 {
+					module = "fts5(name, description, tokenize='unicode61')"
+				} else {
+					log.Println("⚠️  sqlite sürücüsü FTS5'siz derlenmiş, arama indeksi BM25 sıralaması olmadan FTS4 ile oluşturuluyor (`make build` veya -tags sqlite_fts5)")
+				}
+				if err := tx.Exec("CREATE VIRTUAL TABLE product_fts USING " + module).Error; err != nil {
+					return err
+				}
+			}
+
+			var products []struct {
+				ID          uint
+				Name        string
+				Description string
+			}
+			if err := tx.Table("products").Select("id, name, description").Where("deleted_at IS NULL").Find(&products).Error; err != nil {
+				return err
+			}
+			for _, p := range products {
+				name := strings.ToLower(fold0005.Replace(p.Name))
+				description := strings.ToLower(fold0005.Replace(p.Description))
+				var err error
+				if postgres {
+					err = tx.Exec(`INSERT INTO product_search (product_id, document)
+						VALUES (?, setweight(to_tsvector('simple', ?), 'A') || setweight(to_tsvector('simple', ?), 'B'))`,
+						p.ID, name, description).Error
+				} else {
+					err = tx.Exec("INSERT INTO product_fts (rowid, name, description) VALUES (?, ?, ?)",
+						p.ID, name, description).Error
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			if tx.Dialector.Name() == "postgres" {
+				return tx.Exec("DROP TABLE IF EXISTS product_search").Error
+			}
+			return tx.Exec("DROP TABLE IF EXISTS product_fts").Error
+		},
+	})
+}
@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type category0006 struct {
+	ID          uint   `gorm:"primaryKey"`
+	ParentID    *uint  `gorm:"index"`
+	Name        string `gorm:"not null"`
+	Slug        string `gorm:"type:varchar(100);not null;uniqueIndex"`
+	Description string
+	SortOrder   int `gorm:"default:0"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Parent *category0006 `gorm:"foreignKey:ParentID"`
+}
+
+func (category0006) TableName() string { return "categories" }
+
+type product0006 struct {
+	CategoryID *uint `gorm:"index"`
+}
+
+func (product0006) TableName() string { return "products" }
+
+func init() {
+	register(Migration{
+		Version: 6,
+		Name:    "categories",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&category0006{}); err != nil {
+				return err
+			}
+			if err := tx.Migrator().AddColumn(&product0006{}, "CategoryID"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&product0006{}, "CategoryID")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&product0006{}, "CategoryID"); err != nil {
+				return err
+			}
+			err := keepIndexes(tx, "products", func() error {
+				return tx.Migrator().DropColumn(&product0006{}, "CategoryID")
+			})
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&category0006{})
+		},
+	})
+}
@@ -0,0 +1,84 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Ürünlere ölçü birimi eklenir; stok ve sipariş miktarları ondalıklı olur.
+// Mevcut ürünler adet (piece) birimiyle, en az 1 ve 1'er artışla kalır.
+
+type product0007 struct {
+	Unit         string  `gorm:"type:varchar(10);not null;default:'piece'"`
+	MinQuantity  float64 `gorm:"type:decimal(12,3);not null;default:1"`
+	QuantityStep float64 `gorm:"type:decimal(12,3);not null;default:1"`
+	Stock        float64 `gorm:"type:decimal(12,3);default:0"`
+}
+
+func (product0007) TableName() string { return "products" }
+
+type orderItem0007 struct {
+	Quantity float64 `gorm:"type:decimal(12,3);not null"`
+	Unit     string  `gorm:"type:varchar(10);not null;default:'piece'"`
+}
+
+func (orderItem0007) TableName() string { return "order_items" }
+
+// Geri alırken kolonların eski tipleri; ondalıklı miktarlar tam sayıya yuvarlanır
+type product0007Down struct {
+	Stock int `gorm:"default:0"`
+}
+
+func (product0007Down) TableName() string { return "products" }
+
+type orderItem0007Down struct {
+	Quantity int `gorm:"not null"`
+}
+
+func (orderItem0007Down) TableName() string { return "order_items" }
+
+var product0007Columns = []string{"Unit", "MinQuantity", "QuantityStep"}
+
+func init() {
+	register(Migration{
+		Version: 7,
+		Name:    "product_units",
+		Up: func(tx *gorm.DB) error {
+			err := keepIndexes(tx, "products", func() error {
+				for _, column := range product0007Columns {
+					if err := tx.Migrator().AddColumn(&product0007{}, column); err != nil {
+						return err
+					}
+				}
+				return tx.Migrator().AlterColumn(&product0007{}, "Stock")
+			})
+			if err != nil {
+				return err
+			}
+
+			return keepIndexes(tx, "order_items", func() error {
+				if err := tx.Migrator().AddColumn(&orderItem0007{}, "Unit"); err != nil {
+					return err
+				}
+				return tx.Migrator().AlterColumn(&orderItem0007{}, "Quantity")
+			})
+		},
+		Down: func(tx *gorm.DB) error {
+			err := keepIndexes(tx, "products", func() error {
+				for _, column := range product0007Columns {
+					if err := tx.Migrator().DropColumn(&product0007{}, column); err != nil {
+						return err
+					}
+				}
+				return tx.Migrator().AlterColumn(&product0007Down{}, "Stock")
+			})
+			if err != nil {
+				return err
+			}
+
+			return keepIndexes(tx, "order_items", func() error {
+				if err := tx.Migrator().DropColumn(&orderItem0007{}, "Unit"); err != nil {
+					return err
+				}
+				return tx.Migrator().AlterColumn(&orderItem0007Down{}, "Quantity")
+			})
+		},
+	})
+}
@@ -0,0 +1,119 @@
+package migrations
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Tutarlar float64 yerine tam sayı kuruş olarak saklanır. Mevcut değerler 100 ile
+// çarpılıp en yakın kuruşa yuvarlanır; ürün ve siparişlere para birimi eklenir.
+
+type product0008 struct {
+	Price    int64  `gorm:"not null"`
+	Currency string `gorm:"type:varchar(3);not null;default:'TRY'"`
+}
+
+func (product0008) TableName() string { return "products" }
+
+type order0008 struct {
+	TotalAmount int64  `gorm:"not null"`
+	Currency    string `gorm:"type:varchar(3);not null;default:'TRY'"`
+}
+
+func (order0008) TableName() string { return "orders" }
+
+type orderItem0008 struct {
+	Price int64 `gorm:"not null"`
+}
+
+func (orderItem0008) TableName() string { return "order_items" }
+
+// Geri alırken kolonların eski tipleri
+type product0008Down struct {
+	Price float64 `gorm:"not null"`
+}
+
+func (product0008Down) TableName() string { return "products" }
+
+type order0008Down struct {
+	TotalAmount float64 `gorm:"not null"`
+}
+
+func (order0008Down) TableName() string { return "orders" }
+
+type orderItem0008Down struct {
+	Price float64 `gorm:"not null"`
+}
+
+func (orderItem0008Down) TableName() string { return "order_items" }
+
+type moneyColumn0008 struct {
+	table, column, field string
+	up, down             interface{}
+}
+
+var moneyColumns0008 = []moneyColumn0008{
+	{"products", "price", "Price", &product0008{}, &product0008Down{}},
+	{"orders", "total_amount", "TotalAmount", &order0008{}, &order0008Down{}},
+	{"order_items", "price", "Price", &orderItem0008{}, &orderItem0008Down{}},
+}
+
+func init() {
+	register(Migration{
+		Version: 8,
+		Name:    "money_minor_units",
+		Up: func(tx *gorm.DB) error {
+			for _, m := range moneyColumns0008 {
+				err := keepIndexes(tx, m.table, func() error {
+					if tx.Dialector.Name() == "postgres" {
+						return tx.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE bigint USING ROUND(%s * 100)::bigint",
+							m.table, m.column, m.column)).Error
+					}
+					if err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = CAST(ROUND(%s * 100) AS INTEGER)",
+						m.table, m.column, m.column)).Error; err != nil {
+						return err
+					}
+					return tx.Migrator().AlterColumn(m.up, m.field)
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Migrator().AddColumn(&product0008{}, "Currency"); err != nil {
+				return err
+			}
+			return tx.Migrator().AddColumn(&order0008{}, "Currency")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := keepIndexes(tx, "products", func() error {
+				return tx.Migrator().DropColumn(&product0008{}, "Currency")
+			}); err != nil {
+				return err
+			}
+			if err := keepIndexes(tx, "orders", func() error {
+				return tx.Migrator().DropColumn(&order0008{}, "Currency")
+			}); err != nil {
+				return err
+			}
+
+			for _, m := range moneyColumns0008 {
+				err := keepIndexes(tx, m.table, func() error {
+					if tx.Dialector.Name() == "postgres" {
+						return tx.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE double precision USING %s / 100.0",
+							m.table, m.column, m.column)).Error
+					}
+					if err := tx.Migrator().AlterColumn(m.down, m.field); err != nil {
+						return err
+					}
+					return tx.Exec(fmt.Sprintf("UPDATE %s SET %s = %s / 100.0", m.table, m.column, m.column)).Error
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
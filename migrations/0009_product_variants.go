@@ -0,0 +1,116 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Ürün çeşitleri (kendi SKU, fiyat ve stoğuyla) ve fiyat farklı seçenek grupları
+// eklenir. Sipariş kalemleri seçilen çeşidi ve seçenekleri sipariş anındaki
+// haliyle saklar.
+
+type productVariant0009 struct {
+	ID        uint    `gorm:"primaryKey"`
+	ProductID uint    `gorm:"not null;index"`
+	ShopID    uint    `gorm:"not null;uniqueIndex:idx_product_variants_shop_sku"`
+	Name      string  `gorm:"not null"`
+	SKU       string  `gorm:"type:varchar(64);not null;uniqueIndex:idx_product_variants_shop_sku"`
+	Price     int64   `gorm:"not null"`
+	Stock     float64 `gorm:"type:decimal(12,3);default:0"`
+	IsActive  bool    `gorm:"default:true"`
+	SortOrder int     `gorm:"default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (productVariant0009) TableName() string { return "product_variants" }
+
+type optionGroup0009 struct {
+	ID        uint   `gorm:"primaryKey"`
+	ProductID uint   `gorm:"not null;index"`
+	Name      string `gorm:"not null"`
+	MinSelect int    `gorm:"default:0"`
+	MaxSelect int    `gorm:"default:1"`
+	SortOrder int    `gorm:"default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Options []productOption0009 `gorm:"foreignKey:OptionGroupID;constraint:OnDelete:CASCADE"`
+}
+
+func (optionGroup0009) TableName() string { return "option_groups" }
+
+type productOption0009 struct {
+	ID            uint   `gorm:"primaryKey"`
+	OptionGroupID uint   `gorm:"not null;index"`
+	Name          string `gorm:"not null"`
+	PriceModifier int64  `gorm:"not null;default:0"`
+	IsActive      bool   `gorm:"default:true"`
+	SortOrder     int    `gorm:"default:0"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (productOption0009) TableName() string { return "product_options" }
+
+type orderItemOption0009 struct {
+	ID            uint `gorm:"primaryKey"`
+	OrderItemID   uint `gorm:"not null;index"`
+	OptionID      uint
+	GroupName     string
+	Name          string
+	PriceModifier int64 `gorm:"not null"`
+	CreatedAt     time.Time
+}
+
+func (orderItemOption0009) TableName() string { return "order_item_options" }
+
+type orderItem0009 struct {
+	VariantID   *uint `gorm:"index"`
+	VariantName string
+	SKU         string `gorm:"type:varchar(64)"`
+}
+
+func (orderItem0009) TableName() string { return "order_items" }
+
+var orderItem0009Columns = []string{"VariantID", "VariantName", "SKU"}
+
+func init() {
+	register(Migration{
+		Version: 9,
+		Name:    "product_variants",
+		Up: func(tx *gorm.DB) error {
+			// Seçenekler gruplarına bağlı olduğundan grup tablosundan önce oluşturulamaz
+			err := tx.Migrator().CreateTable(&productVariant0009{}, &optionGroup0009{},
+				&productOption0009{}, &orderItemOption0009{})
+			if err != nil {
+				return err
+			}
+			for _, column := range orderItem0009Columns {
+				if err := tx.Migrator().AddColumn(&orderItem0009{}, column); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().CreateIndex(&orderItem0009{}, "VariantID")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&orderItem0009{}, "VariantID"); err != nil {
+				return err
+			}
+			err := keepIndexes(tx, "order_items", func() error {
+				for _, column := range orderItem0009Columns {
+					if err := tx.Migrator().DropColumn(&orderItem0009{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&orderItemOption0009{}, &productOption0009{},
+				&optionGroup0009{}, &productVariant0009{})
+		},
+	})
+}
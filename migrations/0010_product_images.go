@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Ürünlere birden fazla, sıralı görsel eklenir. Dosyaların kendisi veritabanında
+// değil, storage paketindeki depoda tutulur.
+
+type productImage0010 struct {
+	ID          uint   `gorm:"primaryKey"`
+	ProductID   uint   `gorm:"not null;index"`
+	Key         string `gorm:"not null"`
+	ContentType string `gorm:"type:varchar(50);not null"`
+	Size        int64
+	Width       int
+	Height      int
+	SortOrder   int `gorm:"default:0"`
+	CreatedAt   time.Time
+}
+
+func (productImage0010) TableName() string { return "product_images" }
+
+func init() {
+	register(Migration{
+		Version: 10,
+		Name:    "product_images",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&productImage0010{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&productImage0010{})
+		},
+	})
+}
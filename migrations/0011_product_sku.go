@@ -0,0 +1,35 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Ürünlere dükkan içinde benzersiz, isteğe bağlı bir SKU eklenir. Toplu içe
+// aktarmada ürünler SKU ile eşleştirilir. SKU'su olmayan (NULL) ürünler
+// benzersizlik kısıtına takılmaz.
+
+type product0011 struct {
+	ShopID uint    `gorm:"uniqueIndex:idx_products_shop_sku"`
+	SKU    *string `gorm:"type:varchar(64);uniqueIndex:idx_products_shop_sku"`
+}
+
+func (product0011) TableName() string { return "products" }
+
+func init() {
+	register(Migration{
+		Version: 11,
+		Name:    "product_sku",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&product0011{}, "SKU"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&product0011{}, "idx_products_shop_sku")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&product0011{}, "idx_products_shop_sku"); err != nil {
+				return err
+			}
+			return keepIndexes(tx, "products", func() error {
+				return tx.Migrator().DropColumn(&product0011{}, "SKU")
+			})
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Stok defteri eklenir: her stok değişikliği (satış, iptal iadesi, mal kabul,
+// sayım, fire) işaretli miktarı ve sonraki bakiyesiyle bir hareket olarak
+// yazılır. Mevcut stoklar, defter toplamı stok kolonlarına eşit olsun diye
+// açılış bakiyesi hareketleriyle deftere aktarılır.
+
+type stockMovement0012 struct {
+	ID           uint    `gorm:"primaryKey"`
+	ShopID       uint    `gorm:"not null;index"`
+	ProductID    uint    `gorm:"not null;index"`
+	VariantID    *uint   `gorm:"index"`
+	Type         string  `gorm:"type:varchar(20);not null"`
+	Quantity     float64 `gorm:"type:decimal(12,3);not null"`
+	BalanceAfter float64 `gorm:"type:decimal(12,3);not null"`
+	ActorID      *uint   `gorm:"index"`
+	OrderID      *uint   `gorm:"index"`
+	Reason       string
+	CreatedAt    time.Time
+}
+
+func (stockMovement0012) TableName() string { return "stock_movements" }
+
+func init() {
+	register(Migration{
+		Version: 12,
+		Name:    "stock_movements",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&stockMovement0012{}); err != nil {
+				return err
+			}
+
+			now := time.Now()
+			err := tx.Exec(`INSERT INTO stock_movements (shop_id, product_id, type, quantity, balance_after, reason, created_at)
+				SELECT shop_id, id, 'adjustment', stock, stock, 'Açılış bakiyesi', ? FROM products WHERE stock <> 0`, now).Error
+			if err != nil {
+				return err
+			}
+			return tx.Exec(`INSERT INTO stock_movements (shop_id, product_id, variant_id, type, quantity, balance_after, reason, created_at)
+				SELECT shop_id, product_id, id, 'adjustment', stock, stock, 'Açılış bakiyesi', ? FROM product_variants WHERE stock <> 0`, now).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&stockMovement0012{})
+		},
+	})
+}
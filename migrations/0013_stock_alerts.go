@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Ürünlere yeniden sipariş eşiği, dükkanlara stoğu biten ürünleri gizleme
+// ayarı eklenir. Stok çıkışı eşiği geçtiğinde stock_alerts tablosuna uyarı
+// yazılır.
+
+type product0013 struct {
+	ReorderThreshold float64 `gorm:"type:decimal(12,3);not null;default:0"`
+}
+
+func (product0013) TableName() string { return "products" }
+
+type shop0013 struct {
+	HideOutOfStock bool `gorm:"not null;default:false"`
+}
+
+func (shop0013) TableName() string { return "shops" }
+
+type stockAlert0013 struct {
+	ID           uint `gorm:"primaryKey"`
+	ShopID       uint `gorm:"not null;index"`
+	ProductID    uint `gorm:"not null;index"`
+	VariantID    *uint
+	Kind         string  `gorm:"type:varchar(20);not null"`
+	Stock        float64 `gorm:"type:decimal(12,3);not null"`
+	Threshold    float64 `gorm:"type:decimal(12,3);not null"`
+	MovementType string  `gorm:"type:varchar(20);not null"`
+	CreatedAt    time.Time
+}
+
+func (stockAlert0013) TableName() string { return "stock_alerts" }
+
+func init() {
+	register(Migration{
+		Version: 13,
+		Name:    "stock_alerts",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&product0013{}, "ReorderThreshold"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().AddColumn(&shop0013{}, "HideOutOfStock"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&stockAlert0013{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&stockAlert0013{}); err != nil {
+				return err
+			}
+			err := keepIndexes(tx, "shops", func() error {
+				return tx.Migrator().DropColumn(&shop0013{}, "HideOutOfStock")
+			})
+			if err != nil {
+				return err
+			}
+			return keepIndexes(tx, "products", func() error {
+				return tx.Migrator().DropColumn(&product0013{}, "ReorderThreshold")
+			})
+		},
+	})
+}
@@ -0,0 +1,71 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Esnafların sipariş olaylarını kendi sistemlerine almaları için webhook uç
+// noktaları eklenir. Her olay uç nokta başına bir teslimat olarak kuyruğa
+// yazılır; denemeler webhook_attempts tablosunda tutulur.
+
+type webhookEndpoint0014 struct {
+	ID          uint   `gorm:"primaryKey"`
+	ShopID      uint   `gorm:"not null;index"`
+	URL         string `gorm:"type:varchar(2048);not null"`
+	Description string
+	Secret      string `gorm:"type:varchar(100);not null"`
+	Events      string `gorm:"type:text;not null"`
+	IsActive    bool   `gorm:"not null;default:true"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+func (webhookEndpoint0014) TableName() string { return "webhook_endpoints" }
+
+type webhookDelivery0014 struct {
+	ID            uint       `gorm:"primaryKey"`
+	EndpointID    uint       `gorm:"not null;index"`
+	ShopID        uint       `gorm:"not null;index"`
+	EventID       uint64     `gorm:"not null"`
+	EventType     string     `gorm:"type:varchar(50);not null"`
+	Payload       string     `gorm:"type:text;not null"`
+	Status        string     `gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts      int        `gorm:"not null;default:0"`
+	NextAttemptAt *time.Time `gorm:"index:idx_webhook_deliveries_due,priority:2"`
+	LastAttemptAt *time.Time
+	ResponseCode  *int
+	Error         string
+	DeliveredAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (webhookDelivery0014) TableName() string { return "webhook_deliveries" }
+
+type webhookAttempt0014 struct {
+	ID           uint `gorm:"primaryKey"`
+	DeliveryID   uint `gorm:"not null;index"`
+	ResponseCode *int
+	ResponseBody string
+	Error        string
+	DurationMs   int64
+	CreatedAt    time.Time
+}
+
+func (webhookAttempt0014) TableName() string { return "webhook_attempts" }
+
+func init() {
+	register(Migration{
+		Version: 14,
+		Name:    "webhooks",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&webhookEndpoint0014{}, &webhookDelivery0014{}, &webhookAttempt0014{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&webhookAttempt0014{}, &webhookDelivery0014{}, &webhookEndpoint0014{})
+		},
+	})
+}
@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Bildirimler için kullanıcı tercihleri, push cihazları ve yerel geliştirmede
+// gönderilen mesajların yazıldığı giden kutusu eklenir.
+
+type notificationPreference0015 struct {
+	UserID    uint   `gorm:"primaryKey;autoIncrement:false"`
+	Language  string `gorm:"type:varchar(5);not null"`
+	Email     bool   `gorm:"not null"`
+	SMS       bool   `gorm:"not null"`
+	Push      bool   `gorm:"not null"`
+	UpdatedAt time.Time
+}
+
+func (notificationPreference0015) TableName() string { return "notification_preferences" }
+
+type pushDevice0015 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	Token     string `gorm:"type:varchar(512);not null;uniqueIndex"`
+	Platform  string `gorm:"type:varchar(10);not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (pushDevice0015) TableName() string { return "push_devices" }
+
+type outboxMessage0015 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Channel   string `gorm:"type:varchar(10);not null"`
+	Recipient string `gorm:"not null"`
+	UserID    *uint  `gorm:"index"`
+	Kind      string `gorm:"type:varchar(50);not null"`
+	Language  string `gorm:"type:varchar(5);not null"`
+	Subject   string
+	Body      string `gorm:"type:text;not null"`
+	CreatedAt time.Time
+}
+
+func (outboxMessage0015) TableName() string { return "notification_outbox" }
+
+func init() {
+	register(Migration{
+		Version: 15,
+		Name:    "notifications",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&notificationPreference0015{}, &pushDevice0015{}, &outboxMessage0015{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&outboxMessage0015{}, &pushDevice0015{}, &notificationPreference0015{})
+		},
+	})
+}
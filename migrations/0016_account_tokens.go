@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Şifre sıfırlama ve e-posta doğrulama için tek kullanımlık token'lar eklenir.
+// Kullanıcılara e-posta doğrulama zamanı, dükkanlara yalnızca doğrulanmış
+// müşterilerden sipariş alma ayarı eklenir. Mevcut kullanıcılar doğrulanmamış
+// sayılır.
+
+type user0016 struct {
+	EmailVerifiedAt *time.Time
+}
+
+func (user0016) TableName() string { return "users" }
+
+type shop0016 struct {
+	RequireVerifiedCustomers bool `gorm:"not null;default:false"`
+}
+
+func (shop0016) TableName() string { return "shops" }
+
+type accountToken0016 struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index:idx_account_tokens_user_purpose"`
+	Purpose   string    `gorm:"type:varchar(30);not null;index:idx_account_tokens_user_purpose"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Email     string    `gorm:"not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (accountToken0016) TableName() string { return "account_tokens" }
+
+func init() {
+	register(Migration{
+		Version: 16,
+		Name:    "account_tokens",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&user0016{}, "EmailVerifiedAt"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().AddColumn(&shop0016{}, "RequireVerifiedCustomers"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&accountToken0016{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&accountToken0016{}); err != nil {
+				return err
+			}
+			err := keepIndexes(tx, "shops", func() error {
+				return tx.Migrator().DropColumn(&shop0016{}, "RequireVerifiedCustomers")
+			})
+			if err != nil {
+				return err
+			}
+			return keepIndexes(tx, "users", func() error {
+				return tx.Migrator().DropColumn(&user0016{}, "EmailVerifiedAt")
+			})
+		},
+	})
+}
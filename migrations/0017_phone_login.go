@@ -0,0 +1,80 @@
+package migrations
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Telefonla giriş: kullanıcılara SMS koduyla doğrulanan numara, tek kullanımlık
+// kodlar için phone_otps tablosu eklenir. Kodlar amaçlarına (giriş, numara
+// doğrulama) göre ayrılır. Telefonla kayıtlı hesapların e-posta adresi boş
+// olduğundan e-posta tekilliği yalnızca dolu adresler için aranır.
+
+type user0017 struct {
+	PhoneE164 *string `gorm:"type:varchar(16);uniqueIndex:idx_users_phone_e164"`
+}
+
+func (user0017) TableName() string { return "users" }
+
+type phoneOTP0017 struct {
+	ID        uint      `gorm:"primaryKey"`
+	Phone     string    `gorm:"type:varchar(16);not null;index:idx_phone_otps_phone_purpose"`
+	Purpose   string    `gorm:"type:varchar(10);not null;index:idx_phone_otps_phone_purpose"`
+	IP        string    `gorm:"type:varchar(45);not null;default:'';index"`
+	CodeHash  string    `gorm:"type:varchar(64);not null"`
+	Attempts  int       `gorm:"not null;default:0"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (phoneOTP0017) TableName() string { return "phone_otps" }
+
+func init() {
+	register(Migration{
+		Version: 17,
+		Name:    "phone_login",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&user0017{}, "PhoneE164"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateIndex(&user0017{}, "idx_users_phone_e164"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropIndex("users", "idx_users_email"); err != nil {
+				return err
+			}
+			if err := tx.Exec("CREATE UNIQUE INDEX idx_users_email ON users (email) WHERE email <> ''").Error; err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&phoneOTP0017{})
+		},
+		Down: func(tx *gorm.DB) error {
+			var phoneOnly int64
+			if err := tx.Table("users").Where("email = ''").Count(&phoneOnly).Error; err != nil {
+				return err
+			}
+			if phoneOnly > 0 {
+				return fmt.Errorf("e-posta adresi olmayan %d kullanıcı var; geri almadan önce bu hesaplara adres atayın veya silin", phoneOnly)
+			}
+
+			if err := tx.Migrator().DropTable(&phoneOTP0017{}); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropIndex("users", "idx_users_email"); err != nil {
+				return err
+			}
+			if err := tx.Exec("CREATE UNIQUE INDEX idx_users_email ON users (email)").Error; err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropIndex(&user0017{}, "idx_users_phone_e164"); err != nil {
+				return err
+			}
+			return keepIndexes(tx, "users", func() error {
+				return tx.Migrator().DropColumn(&user0017{}, "PhoneE164")
+			})
+		},
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Kullanıcılara son şifre değişikliği zamanı eklenir; bu zamandan önce verilen
+// access token'lar reddedilir. Mevcut kullanıcılar için boş kalır.
+
+type user0018 struct {
+	PasswordChangedAt *time.Time
+}
+
+func (user0018) TableName() string { return "users" }
+
+func init() {
+	register(Migration{
+		Version: 18,
+		Name:    "password_changed_at",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&user0018{}, "PasswordChangedAt")
+		},
+		Down: func(tx *gorm.DB) error {
+			return keepIndexes(tx, "users", func() error {
+				return tx.Migrator().DropColumn(&user0018{}, "PasswordChangedAt")
+			})
+		},
+	})
+}
@@ -0,0 +1,221 @@
+// Package migrations numaralı, geri alınabilir şema migrasyonlarını yönetir.
+//
+// Her migrasyon kendi dosyasında (ör. 0001_baseline.go) init içinde register
+// ile kaydedilir. Migrasyonlar models paketindeki struct'ları değil, o anki
+// şemanın kopyası olan yerel struct'ları kullanır; böylece modeller değiştiğinde
+// eski migrasyonların davranışı değişmez.
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// Uygulanmış migrasyonların kaydı
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Unknown   bool // Veritabanında kayıtlı ama kodda bulunmayan migrasyon
+}
+
+var registry []Migration
+
+func register(m Migration) {
+	for _, existing := range registry {
+		if existing.Version == m.Version {
+			panic(fmt.Sprintf("migrasyon versiyonu tekrar kullanılmış: %d", m.Version))
+		}
+	}
+	registry = append(registry, m)
+	sort.Slice(registry, func(i, j int) bool { return registry[i].Version < registry[j].Version })
+}
+
+// All kayıtlı tüm migrasyonları versiyon sırasıyla döner.
+func All() []Migration {
+	return append([]Migration(nil), registry...)
+}
+
+// Up uygulanmamış tüm migrasyonları sırayla, her birini ayrı bir transaction
+// içinde uygular.
+func Up(db *gorm.DB) ([]Migration, error) {
+	pending, err := Pending(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, m := range pending {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return applied, fmt.Errorf("%04d_%s uygulanamadı: %w", m.Version, m.Name, err)
+		}
+		applied = append(applied, m)
+	}
+
+	return applied, nil
+}
+
+// Down son uygulanan steps adet migrasyonu geri alır.
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	records, err := appliedRecords(db)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]Migration, len(registry))
+	for _, m := range registry {
+		byVersion[m.Version] = m
+	}
+
+	var rolledBack []Migration
+	for i := len(records) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+		m, ok := byVersion[records[i].Version]
+		if !ok {
+			return rolledBack, fmt.Errorf("%d versiyonlu migrasyon kodda bulunamadı", records[i].Version)
+		}
+		if m.Down == nil {
+			return rolledBack, fmt.Errorf("%04d_%s geri alınamaz", m.Version, m.Name)
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return rolledBack, fmt.Errorf("%04d_%s geri alınamadı: %w", m.Version, m.Name, err)
+		}
+		rolledBack = append(rolledBack, m)
+	}
+
+	return rolledBack, nil
+}
+
+// Pending henüz uygulanmamış migrasyonları döner.
+func Pending(db *gorm.DB) ([]Migration, error) {
+	records, err := appliedRecords(db)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]bool, len(records))
+	for _, r := range records {
+		applied[r.Version] = true
+	}
+
+	var pending []Migration
+	for _, m := range registry {
+		if !applied[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// StatusList kayıtlı ve uygulanmış migrasyonların durumunu versiyon sırasıyla döner.
+func StatusList(db *gorm.DB) ([]Status, error) {
+	records, err := appliedRecords(db)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]SchemaMigration, len(records))
+	for _, r := range records {
+		applied[r.Version] = r
+	}
+
+	var list []Status
+	known := make(map[int64]bool, len(registry))
+	for _, m := range registry {
+		known[m.Version] = true
+		s := Status{Version: m.Version, Name: m.Name}
+		if r, ok := applied[m.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = &r.AppliedAt
+		}
+		list = append(list, s)
+	}
+	for _, r := range records {
+		if !known[r.Version] {
+			appliedAt := r.AppliedAt
+			list = append(list, Status{Version: r.Version, Name: r.Name, Applied: true, AppliedAt: &appliedAt, Unknown: true})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+func appliedRecords(db *gorm.DB) ([]SchemaMigration, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("schema_migrations tablosu oluşturulamadı: %w", err)
+	}
+
+	var records []SchemaMigration
+	if err := db.Order("version").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// keepIndexes SQLite'ta tabloyu yeniden oluşturan işlemlerden (AlterColumn,
+// DropColumn) sonra tablonun indekslerini yeniden oluşturur; SQLite tabloyu
+// silerken indekslerini de siler. Diğer veritabanlarında fn doğrudan çalışır.
+// Silinecek bir kolonun indeksi fn'den önce ayrıca kaldırılmalıdır.
+func keepIndexes(tx *gorm.DB, table string, fn func() error) error {
+	if tx.Dialector.Name() != "sqlite" {
+		return fn()
+	}
+
+	var indexes []struct {
+		Name string
+		SQL  string
+	}
+	err := tx.Raw("SELECT name, sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL", table).
+		Scan(&indexes).Error
+	if err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	for _, idx := range indexes {
+		if tx.Migrator().HasIndex(table, idx.Name) {
+			continue
+		}
+		if err := tx.Exec(idx.SQL).Error; err != nil {
+			return fmt.Errorf("%s indeksi yeniden oluşturulamadı: %w", idx.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type AccountTokenPurpose string
+
+const (
+	TokenPasswordReset     AccountTokenPurpose = "password_reset"
+	TokenEmailVerification AccountTokenPurpose = "email_verification"
+)
+
+// AccountToken e-postayla gönderilen tek kullanımlık bağlantı token'ı. Token'ın
+// kendisi saklanmaz, yalnızca SHA-256 özeti tutulur. Email token'ın gönderildiği
+// adrestir; kullanıcının adresi değişmişse token geçersizdir.
+type AccountToken struct {
+	ID        uint                `json:"id" gorm:"primaryKey"`
+	UserID    uint                `json:"user_id" gorm:"not null;index:idx_account_tokens_user_purpose"`
+	Purpose   AccountTokenPurpose `json:"purpose" gorm:"type:varchar(30);not null;index:idx_account_tokens_user_purpose"`
+	TokenHash string              `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Email     string              `json:"email" gorm:"not null"`
+	ExpiresAt time.Time           `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time          `json:"used_at,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Platform genelinde ortak ürün kategorileri. ParentID boş olanlar kök
+// kategorilerdir; kategoriler admin tarafından yönetilir. Yalnızca alt kategorisi
+// ve ürünü olmayan kategoriler silinebildiği için silme kalıcıdır ve slug tekrar
+// kullanılabilir.
+type Category struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ParentID    *uint     `json:"parent_id" gorm:"index"`
+	Name        string    `json:"name" gorm:"not null"`
+	Slug        string    `json:"slug" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Description string    `json:"description"`
+	SortOrder   int       `json:"sort_order" gorm:"default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// İlişkiler
+	Parent   *Category  `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
+	Children []Category `json:"children,omitempty" gorm:"foreignKey:ParentID"`
+	Products []Product  `json:"products,omitempty" gorm:"foreignKey:CategoryID"`
+}
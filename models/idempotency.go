@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Idempotency-Key ile gelen isteğin kaydı. Yanıt tamamlanana kadar CompletedAt boştur.
+type IdempotencyKey struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	UserID       uint       `json:"user_id" gorm:"not null;uniqueIndex:idx_idempotency_keys_user_key"`
+	Key          string     `json:"key" gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_keys_user_key"`
+	RequestHash  string     `json:"-" gorm:"type:varchar(64);not null"`
+	StatusCode   int        `json:"status_code"`
+	ResponseBody string     `json:"-"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null;index"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
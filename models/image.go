@@ -0,0 +1,64 @@
+package models
+
+import (
+	"path"
+	"strings"
+	"time"
+	"tradesman-api/media"
+	"tradesman-api/storage"
+
+	"gorm.io/gorm"
+)
+
+// Ürün görseli. Orijinal dosya Key altında, thumbnail'ler ise aynı adın
+// sonuna boyut eklenerek (ör. products/3/ab12_small.jpg) saklanır.
+type ProductImage struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ProductID   uint      `json:"product_id" gorm:"not null;index"`
+	Key         string    `json:"-" gorm:"not null"`
+	ContentType string    `json:"content_type" gorm:"type:varchar(50);not null"`
+	Size        int64     `json:"size"` // byte
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	SortOrder   int       `json:"sort_order" gorm:"default:0"` // İlk görsel kapak görselidir
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Depodan üretilen adresler
+	URL        string            `json:"url" gorm:"-"`
+	Thumbnails map[string]string `json:"thumbnails" gorm:"-"`
+}
+
+// ThumbnailKey verilen boyuttaki thumbnail'in depo anahtarını döner.
+func (img *ProductImage) ThumbnailKey(size string) string {
+	return strings.TrimSuffix(img.Key, path.Ext(img.Key)) + "_" + size + media.ThumbnailExt(img.ContentType)
+}
+
+// Keys orijinal dosya ve tüm thumbnail'lerin anahtarlarını döner.
+func (img *ProductImage) Keys() []string {
+	keys := []string{img.Key}
+	for _, size := range media.ThumbnailSizes {
+		keys = append(keys, img.ThumbnailKey(size.Name))
+	}
+	return keys
+}
+
+func (img *ProductImage) AfterFind(tx *gorm.DB) error {
+	img.fillURLs()
+	return nil
+}
+
+func (img *ProductImage) AfterCreate(tx *gorm.DB) error {
+	img.fillURLs()
+	return nil
+}
+
+func (img *ProductImage) fillURLs() {
+	if storage.Default == nil {
+		return
+	}
+	img.URL = storage.Default.URL(img.Key)
+	img.Thumbnails = make(map[string]string, len(media.ThumbnailSizes))
+	for _, size := range media.ThumbnailSizes {
+		img.Thumbnails[size.Name] = storage.Default.URL(img.ThumbnailKey(size.Name))
+	}
+}
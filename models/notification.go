@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+type NotificationChannel string
+
+const (
+	ChannelEmail NotificationChannel = "email"
+	ChannelSMS   NotificationChannel = "sms"
+	ChannelPush  NotificationChannel = "push"
+)
+
+func (c NotificationChannel) IsValid() bool {
+	switch c {
+	case ChannelEmail, ChannelSMS, ChannelPush:
+		return true
+	}
+	return false
+}
+
+// Bildirim dilleri; ilk dil varsayılandır.
+var NotificationLanguages = []string{"tr", "en"}
+
+func IsNotificationLanguage(lang string) bool {
+	for _, l := range NotificationLanguages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+// Kullanıcının bildirim tercihleri. Kaydı olmayan kullanıcılar için
+// DefaultNotificationPreference geçerlidir.
+type NotificationPreference struct {
+	UserID    uint      `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	Language  string    `json:"language" gorm:"type:varchar(5);not null"`
+	Email     bool      `json:"email" gorm:"not null"`
+	SMS       bool      `json:"sms" gorm:"not null"`
+	Push      bool      `json:"push" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func DefaultNotificationPreference(userID uint) NotificationPreference {
+	return NotificationPreference{
+		UserID:   userID,
+		Language: NotificationLanguages[0],
+		Email:    true,
+		SMS:      false,
+		Push:     true,
+	}
+}
+
+// Enabled kanalın açık olup olmadığını döner.
+func (p NotificationPreference) Enabled(channel NotificationChannel) bool {
+	switch channel {
+	case ChannelEmail:
+		return p.Email
+	case ChannelSMS:
+		return p.SMS
+	case ChannelPush:
+		return p.Push
+	}
+	return false
+}
+
+type PushPlatform string
+
+const (
+	PlatformIOS     PushPlatform = "ios"
+	PlatformAndroid PushPlatform = "android"
+	PlatformWeb     PushPlatform = "web"
+)
+
+func (p PushPlatform) IsValid() bool {
+	return p == PlatformIOS || p == PlatformAndroid || p == PlatformWeb
+}
+
+// Push bildirimlerinin gönderildiği cihaz. Token cihaz başına tekildir; başka
+// bir kullanıcı aynı cihazdan giriş yapıp kaydettiğinde cihaz ona geçer.
+type PushDevice struct {
+	ID        uint         `json:"id" gorm:"primaryKey"`
+	UserID    uint         `json:"user_id" gorm:"not null;index"`
+	Token     string       `json:"token" gorm:"type:varchar(512);not null;uniqueIndex"`
+	Platform  PushPlatform `json:"platform" gorm:"type:varchar(10);not null"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Yerel geliştirmede gerçek sağlayıcı yerine kullanılan giden kutusu: gönderilen
+// e-posta, SMS ve push mesajları bu tabloya yazılır.
+type OutboxMessage struct {
+	ID        uint                `json:"id" gorm:"primaryKey"`
+	Channel   NotificationChannel `json:"channel" gorm:"type:varchar(10);not null"`
+	Recipient string              `json:"recipient" gorm:"not null"` // E-posta adresi, telefon veya cihaz token'ı
+	UserID    *uint               `json:"user_id" gorm:"index"`
+	Kind      string              `json:"kind" gorm:"type:varchar(50);not null"`
+	Language  string              `json:"language" gorm:"type:varchar(5);not null"`
+	Subject   string              `json:"subject"`
+	Body      string              `json:"body" gorm:"type:text;not null"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+func (OutboxMessage) TableName() string { return "notification_outbox" }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"time"
+	"tradesman-api/money"
 
 	"gorm.io/gorm"
 )
@@ -17,16 +18,57 @@ const (
 	OrderStatusCancelled OrderStatus = "cancelled" // İptal edildi
 )
 
+// İzin verilen durum geçişleri. Teslim edilen ve iptal edilen siparişler son durumdur.
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:   {OrderStatusConfirmed, OrderStatusCancelled},
+	OrderStatusConfirmed: {OrderStatusPreparing, OrderStatusCancelled},
+	OrderStatusPreparing: {OrderStatusReady, OrderStatusCancelled},
+	OrderStatusReady:     {OrderStatusDelivered, OrderStatusCancelled},
+}
+
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case OrderStatusPending, OrderStatusConfirmed, OrderStatusPreparing,
+		OrderStatusReady, OrderStatusDelivered, OrderStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// NextStatuses bu durumdan geçilebilecek durumları döner.
+func (s OrderStatus) NextStatuses() []OrderStatus {
+	return orderStatusTransitions[s]
+}
+
+// Müşteri siparişini yalnızca esnaf hazırlamaya başlamadan iptal edebilir.
+func (s OrderStatus) CustomerCancellable() bool {
+	return s == OrderStatusPending || s == OrderStatusConfirmed
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type Order struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	UserID      uint           `json:"user_id" gorm:"not null;index"`
-	ShopID      uint           `json:"shop_id" gorm:"not null;index"`
-	TotalAmount float64        `json:"total_amount" gorm:"not null"`
-	Status      OrderStatus    `json:"status" gorm:"type:varchar(20);default:'pending'"`
-	Note        string         `json:"note"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	UserID      uint         `json:"user_id" gorm:"not null;index"`
+	ShopID      uint         `json:"shop_id" gorm:"not null;index"`
+	TotalAmount money.Amount `json:"total_amount" gorm:"not null"` // Kalem tutarlarının toplamı, kuruş olarak saklanır
+	Currency    string       `json:"currency" gorm:"type:varchar(3);not null;default:'TRY'"`
+	Status      OrderStatus  `json:"status" gorm:"type:varchar(20);default:'pending'"`
+	Note        string       `json:"note"`
+	// İptal bilgileri
+	CancellationReason string         `json:"cancellation_reason,omitempty"`
+	CancelledAt        *time.Time     `json:"cancelled_at,omitempty"`
+	CancelledByID      *uint          `json:"cancelled_by_id,omitempty"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// İlişkiler
 	User       User        `json:"user" gorm:"foreignKey:UserID"`
@@ -35,14 +77,51 @@ type Order struct {
 }
 
 type OrderItem struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	OrderID   uint      `json:"order_id" gorm:"not null;index"`
-	ProductID uint      `json:"product_id" gorm:"not null;index"`
-	Quantity  int       `json:"quantity" gorm:"not null"`
-	Price     float64   `json:"price" gorm:"not null"` // Sipariş anındaki fiyat
-	CreatedAt time.Time `json:"created_at"`
+	ID        uint         `json:"id" gorm:"primaryKey"`
+	OrderID   uint         `json:"order_id" gorm:"not null;index"`
+	ProductID uint         `json:"product_id" gorm:"not null;index"`
+	Quantity  float64      `json:"quantity" gorm:"type:decimal(12,3);not null"`
+	Unit      Unit         `json:"unit" gorm:"type:varchar(10);not null;default:'piece'"` // Sipariş anındaki birim
+	Price     money.Amount `json:"price" gorm:"not null"`                                 // Sipariş anındaki birim fiyatı (seçenekler dahil)
+	// Sipariş anındaki çeşit bilgisi; çeşit sonradan silinse de korunur
+	VariantID   *uint     `json:"variant_id,omitempty" gorm:"index"`
+	VariantName string    `json:"variant_name,omitempty"`
+	SKU         string    `json:"sku,omitempty" gorm:"type:varchar(64)"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// İlişkiler
+	Order   Order             `json:"order" gorm:"foreignKey:OrderID"`
+	Product Product           `json:"product" gorm:"foreignKey:ProductID"`
+	Options []OrderItemOption `json:"options,omitempty" gorm:"foreignKey:OrderItemID"`
+}
+
+// Sipariş kalemi için seçilen seçeneğin sipariş anındaki kopyası
+type OrderItemOption struct {
+	ID            uint         `json:"id" gorm:"primaryKey"`
+	OrderItemID   uint         `json:"order_item_id" gorm:"not null;index"`
+	OptionID      uint         `json:"option_id"`
+	GroupName     string       `json:"group_name"`
+	Name          string       `json:"name"`
+	PriceModifier money.Amount `json:"price_modifier" gorm:"not null"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// Total kalemin kuruşa yuvarlanmış tutarını döner (ör. 0.4 kg × 450.00 = 180.00).
+func (i OrderItem) Total() money.Amount {
+	return i.Price.MulQuantity(i.Quantity)
+}
+
+// Sipariş durum değişikliklerinin kaydı. Oluşturma kaydında FromStatus boştur.
+type OrderStatusHistory struct {
+	ID          uint        `json:"id" gorm:"primaryKey"`
+	OrderID     uint        `json:"order_id" gorm:"not null;index"`
+	FromStatus  OrderStatus `json:"from_status" gorm:"type:varchar(20)"`
+	ToStatus    OrderStatus `json:"to_status" gorm:"type:varchar(20);not null"`
+	ChangedByID uint        `json:"changed_by_id" gorm:"not null;index"`
+	Note        string      `json:"note"`
+	CreatedAt   time.Time   `json:"created_at"`
 
 	// İlişkiler
-	Order   Order   `json:"order" gorm:"foreignKey:OrderID"`
-	Product Product `json:"product" gorm:"foreignKey:ProductID"`
+	Order     Order `json:"-" gorm:"foreignKey:OrderID"`
+	ChangedBy User  `json:"-" gorm:"foreignKey:ChangedByID"`
 }
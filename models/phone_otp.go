@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type PhoneOTPPurpose string
+
+const (
+	PhoneOTPLogin  PhoneOTPPurpose = "login"
+	PhoneOTPVerify PhoneOTPPurpose = "verify"
+)
+
+// PhoneOTP SMS ile gönderilen tek kullanımlık kod. Kodun kendisi saklanmaz,
+// numarayla birlikte HMAC özeti tutulur. Kod yalnızca gönderildiği amaçla
+// (giriş veya numara doğrulama) kullanılabilir; gönderim sınırları ve hatalı
+// deneme sayısı (Attempts) da amaç başına tutulur. Numara sınırlarının
+// yanında isteyen IP'ye ve tüm gönderimlere saatlik sınır uygulanır.
+type PhoneOTP struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	Phone     string          `json:"phone" gorm:"type:varchar(16);not null;index:idx_phone_otps_phone_purpose"` // E.164
+	Purpose   PhoneOTPPurpose `json:"purpose" gorm:"type:varchar(10);not null;index:idx_phone_otps_phone_purpose"`
+	IP        string          `json:"-" gorm:"type:varchar(45);not null;default:'';index"` // Kodu isteyen istemci
+	CodeHash  string          `json:"-" gorm:"type:varchar(64);not null"`
+	Attempts  int             `json:"attempts" gorm:"not null;default:0"`
+	ExpiresAt time.Time       `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time      `json:"used_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at" gorm:"index"`
+}
@@ -1,25 +1,183 @@
 package models
 
 import (
+	"fmt"
+	"math"
+	"strconv"
 	"time"
+	"tradesman-api/money"
+	"tradesman-api/search"
 
 	"gorm.io/gorm"
 )
 
+type Unit string
+
+const (
+	UnitPiece Unit = "piece" // Adet
+	UnitKg    Unit = "kg"    // Kilogram
+	UnitGram  Unit = "g"     // Gram
+	UnitLitre Unit = "litre" // Litre
+	UnitBunch Unit = "bunch" // Bağ
+)
+
+// Birim başına varsayılan en az sipariş miktarı ve artış
+var unitDefaults = map[Unit]struct{ min, step float64 }{
+	UnitPiece: {1, 1},
+	UnitKg:    {0.1, 0.1},
+	UnitGram:  {100, 50},
+	UnitLitre: {0.5, 0.5},
+	UnitBunch: {1, 1},
+}
+
+// Miktarlar (stok, sipariş miktarı) 3 ondalık basamakla (gram / mililitre) tutulur.
+const quantityScale = 1000
+
+func (u Unit) IsValid() bool {
+	_, ok := unitDefaults[u]
+	return ok
+}
+
+// Countable adet ve bağ gibi yalnızca tam sayı miktarla satılan birimler için true döner.
+func (u Unit) Countable() bool {
+	return u == UnitPiece || u == UnitBunch
+}
+
+// ValidateStock stok miktarının bu birimde geçerli olduğunu kontrol eder.
+func (u Unit) ValidateStock(stock float64) error {
+	if stock < 0 {
+		return fmt.Errorf("stok negatif olamaz")
+	}
+	if u.Countable() && !isMultiple(stock, 1) {
+		return fmt.Errorf("%s biriminde stok tam sayı olmalı", u)
+	}
+	if !isMultiple(stock, 1.0/quantityScale) {
+		return fmt.Errorf("miktarlar en fazla 3 ondalık basamak içerebilir")
+	}
+	return nil
+}
+
+// RoundQuantity miktarı 3 ondalık basamağa yuvarlar.
+func RoundQuantity(q float64) float64 {
+	return math.Round(q*quantityScale) / quantityScale
+}
+
+// FormatQuantity miktarı gereksiz sıfırlar olmadan yazar (ör. 1.5, 2).
+func FormatQuantity(q float64) string {
+	return strconv.FormatFloat(RoundQuantity(q), 'f', -1, 64)
+}
+
 type Product struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	ShopID      uint           `json:"shop_id" gorm:"not null;index"`
-	Name        string         `json:"name" gorm:"not null"`
-	Description string         `json:"description"`
-	Price       float64        `json:"price" gorm:"not null"`
-	Stock       int            `json:"stock" gorm:"default:0"`
-	IsActive    bool           `json:"is_active" gorm:"default:true"`
-	ImageURL    string         `json:"image_url"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	ShopID           uint           `json:"shop_id" gorm:"not null;index;uniqueIndex:idx_products_shop_sku"`
+	SKU              *string        `json:"sku" gorm:"type:varchar(64);uniqueIndex:idx_products_shop_sku"` // Dükkan içinde benzersiz, isteğe bağlı
+	CategoryID       *uint          `json:"category_id" gorm:"index"`
+	Name             string         `json:"name" gorm:"not null"`
+	Description      string         `json:"description"`
+	Price            money.Amount   `json:"price" gorm:"not null"` // Unit başına fiyat, kuruş olarak saklanır
+	Currency         string         `json:"currency" gorm:"type:varchar(3);not null;default:'TRY'"`
+	Unit             Unit           `json:"unit" gorm:"type:varchar(10);not null;default:'piece'"`
+	MinQuantity      float64        `json:"min_quantity" gorm:"type:decimal(12,3);not null;default:1"`
+	QuantityStep     float64        `json:"quantity_step" gorm:"type:decimal(12,3);not null;default:1"`
+	Stock            float64        `json:"stock" gorm:"type:decimal(12,3);default:0"`                      // Unit cinsinden
+	ReorderThreshold float64        `json:"reorder_threshold" gorm:"type:decimal(12,3);not null;default:0"` // Stok bu değere düşünce uyarı oluşur; 0 ise yalnızca tükenince
+	IsActive         bool           `json:"is_active" gorm:"default:true"`
+	ImageURL         string         `json:"image_url"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// İlişkiler
-	Shop       Shop        `json:"shop" gorm:"foreignKey:ShopID"`
-	OrderItems []OrderItem `json:"order_items,omitempty" gorm:"foreignKey:ProductID"`
+	Shop         Shop             `json:"shop" gorm:"foreignKey:ShopID"`
+	Category     *Category        `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Variants     []ProductVariant `json:"variants,omitempty" gorm:"foreignKey:ProductID"`
+	OptionGroups []OptionGroup    `json:"option_groups,omitempty" gorm:"foreignKey:ProductID"`
+	Images       []ProductImage   `json:"images,omitempty" gorm:"foreignKey:ProductID"`
+	OrderItems   []OrderItem      `json:"order_items,omitempty" gorm:"foreignKey:ProductID"`
+}
+
+// ApplyUnitDefaults boş birimi adet yapar; verilmemiş en az miktar ve artışı
+// birimin varsayılanlarıyla doldurur.
+func (p *Product) ApplyUnitDefaults() {
+	if p.Unit == "" {
+		p.Unit = UnitPiece
+	}
+	defaults := unitDefaults[p.Unit]
+	if p.MinQuantity <= 0 {
+		p.MinQuantity = defaults.min
+	}
+	if p.QuantityStep <= 0 {
+		p.QuantityStep = defaults.step
+	}
+}
+
+// ValidateUnit birim, en az miktar, artış, stok ve yeniden sipariş eşiğinin
+// birbiriyle tutarlı olduğunu kontrol eder.
+func (p Product) ValidateUnit() error {
+	if !p.Unit.IsValid() {
+		return fmt.Errorf("geçersiz birim: %s (piece, kg, g, litre, bunch)", p.Unit)
+	}
+	if p.QuantityStep <= 0 || p.MinQuantity <= 0 {
+		return fmt.Errorf("en az miktar ve artış sıfırdan büyük olmalı")
+	}
+	if !isMultiple(p.MinQuantity, p.QuantityStep) {
+		return fmt.Errorf("en az miktar (%s) artışın (%s) katı olmalı", FormatQuantity(p.MinQuantity), FormatQuantity(p.QuantityStep))
+	}
+	if p.Unit.Countable() && (!isMultiple(p.QuantityStep, 1) || !isMultiple(p.Stock, 1)) {
+		return fmt.Errorf("%s biriminde artış ve stok tam sayı olmalı", p.Unit)
+	}
+	if !isMultiple(p.QuantityStep, 1.0/quantityScale) || !isMultiple(p.Stock, 1.0/quantityScale) {
+		return fmt.Errorf("miktarlar en fazla 3 ondalık basamak içerebilir")
+	}
+	if p.ReorderThreshold < 0 {
+		return fmt.Errorf("yeniden sipariş eşiği negatif olamaz")
+	}
+	if p.Unit.Countable() && !isMultiple(p.ReorderThreshold, 1) {
+		return fmt.Errorf("%s biriminde yeniden sipariş eşiği tam sayı olmalı", p.Unit)
+	}
+	if !isMultiple(p.ReorderThreshold, 1.0/quantityScale) {
+		return fmt.Errorf("miktarlar en fazla 3 ondalık basamak içerebilir")
+	}
+	return nil
+}
+
+// ValidateQuantity sipariş miktarının ürünün en az miktarı ve artışına uyduğunu
+// kontrol eder.
+func (p Product) ValidateQuantity(q float64) error {
+	if RoundQuantity(q) < RoundQuantity(p.MinQuantity) {
+		return fmt.Errorf("%s için en az %s %s sipariş verilebilir", p.Name, FormatQuantity(p.MinQuantity), p.Unit)
+	}
+	if !isMultiple(q, p.QuantityStep) {
+		return fmt.Errorf("%s %s %s artışlarla sipariş verilebilir", p.Name, FormatQuantity(p.QuantityStep), p.Unit)
+	}
+	return nil
+}
+
+// isMultiple q'nun step'in tam katı olup olmadığını miktar hassasiyetinde (3
+// ondalık basamak) kontrol eder.
+func isMultiple(q, step float64) bool {
+	units := q * quantityScale
+	if math.Abs(units-math.Round(units)) > 1e-6 {
+		return false
+	}
+	stepUnits := math.Round(step * quantityScale)
+	return stepUnits > 0 && math.Mod(math.Round(units), stepUnits) == 0
+}
+
+// Arama indeksi ürün oluşturma, güncelleme ve silme ile aynı transaction içinde
+// güncellenir. Model(&Product{}) ile yapılan toplu güncellemeler (stok gibi)
+// isim ve açıklamayı değiştirmediği için ID'siz çağrılar atlanır.
+
+func (p *Product) AfterSave(tx *gorm.DB) error {
+	if p.ID == 0 {
+		return nil
+	}
+	return search.IndexProduct(tx, p.ID, p.Name, p.Description)
+}
+
+func (p *Product) AfterDelete(tx *gorm.DB) error {
+	if p.ID == 0 {
+		return nil
+	}
+	return search.RemoveProduct(tx, p.ID)
 }
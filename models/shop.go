@@ -7,16 +7,18 @@ import (
 )
 
 type Shop struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	UserID      uint           `json:"user_id" gorm:"not null;uniqueIndex"`
-	Name        string         `json:"name" gorm:"not null"`
-	Description string         `json:"description"`
-	Address     string         `json:"address"`
-	Phone       string         `json:"phone"`
-	IsActive    bool           `json:"is_active" gorm:"default:true"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                       uint           `json:"id" gorm:"primaryKey"`
+	UserID                   uint           `json:"user_id" gorm:"not null;uniqueIndex"`
+	Name                     string         `json:"name" gorm:"not null"`
+	Description              string         `json:"description"`
+	Address                  string         `json:"address"`
+	Phone                    string         `json:"phone"`
+	IsActive                 bool           `json:"is_active" gorm:"default:true"`
+	HideOutOfStock           bool           `json:"hide_out_of_stock" gorm:"not null;default:false"`          // Stoğu biten ürünler müşteri listelerinde gösterilmez
+	RequireVerifiedCustomers bool           `json:"require_verified_customers" gorm:"not null;default:false"` // Yalnızca e-postası veya telefonu doğrulanmış müşteriler sipariş verebilir
+	CreatedAt                time.Time      `json:"created_at"`
+	UpdatedAt                time.Time      `json:"updated_at"`
+	DeletedAt                gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// İlişkiler
 	User     User      `json:"user" gorm:"foreignKey:UserID"`
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+type StockMovementType string
+
+const (
+	StockMovementSale         StockMovementType = "sale"         // Satış
+	StockMovementRestock      StockMovementType = "restock"      // Mal kabul
+	StockMovementAdjustment   StockMovementType = "adjustment"   // Sayım düzeltmesi
+	StockMovementCancellation StockMovementType = "cancellation" // İptal edilen siparişin iadesi
+	StockMovementSpoilage     StockMovementType = "spoilage"     // Fire / bozulma
+)
+
+func (t StockMovementType) IsValid() bool {
+	switch t {
+	case StockMovementSale, StockMovementRestock, StockMovementAdjustment,
+		StockMovementCancellation, StockMovementSpoilage:
+		return true
+	}
+	return false
+}
+
+// Stok defterindeki tek hareket. Stok tutan kalem çeşit varsa çeşit, yoksa
+// üründür. Quantity işaretlidir (giriş pozitif, çıkış negatif); bir kalemin
+// hareketlerinin toplamı stok kolonuna eşit olmalıdır. Hareketler
+// değiştirilmez ve silinmez; hatalar yeni bir düzeltme hareketiyle giderilir.
+type StockMovement struct {
+	ID           uint              `json:"id" gorm:"primaryKey"`
+	ShopID       uint              `json:"shop_id" gorm:"not null;index"`
+	ProductID    uint              `json:"product_id" gorm:"not null;index"`
+	VariantID    *uint             `json:"variant_id" gorm:"index"`
+	Type         StockMovementType `json:"type" gorm:"type:varchar(20);not null"`
+	Quantity     float64           `json:"quantity" gorm:"type:decimal(12,3);not null"`      // Ürünün birimi cinsinden, işaretli
+	BalanceAfter float64           `json:"balance_after" gorm:"type:decimal(12,3);not null"` // Hareketten sonraki stok
+	ActorID      *uint             `json:"actor_id" gorm:"index"`                            // Hareketi yapan kullanıcı; migrasyonla açılan bakiyelerde boş
+	OrderID      *uint             `json:"order_id" gorm:"index"`                            // Satış ve iptal iadelerinde
+	Reason       string            `json:"reason"`
+	CreatedAt    time.Time         `json:"created_at"`
+
+	Alert *StockAlert `json:"-" gorm:"-"` // Hareketin oluşturduğu stok uyarısı; commit sonrası yayınlanmak üzere
+}
+
+// ValidateQuantity hareket miktarını ürünün birim kurallarına göre doğrular.
+func (m StockMovement) ValidateQuantity(unit Unit) error {
+	q := math.Abs(m.Quantity)
+	if RoundQuantity(q) == 0 {
+		return fmt.Errorf("miktar sıfır olamaz")
+	}
+	if unit.Countable() && !isMultiple(q, 1) {
+		return fmt.Errorf("%s biriminde miktar tam sayı olmalı", unit)
+	}
+	if !isMultiple(q, 1.0/quantityScale) {
+		return fmt.Errorf("miktarlar en fazla 3 ondalık basamak içerebilir")
+	}
+	return nil
+}
+
+type StockAlertKind string
+
+const (
+	StockAlertLow        StockAlertKind = "low_stock"    // Yeniden sipariş eşiğine düştü
+	StockAlertOutOfStock StockAlertKind = "out_of_stock" // Tükendi
+)
+
+func (k StockAlertKind) IsValid() bool {
+	return k == StockAlertLow || k == StockAlertOutOfStock
+}
+
+// Stok çıkışı bir kalemin stoğunu yeniden sipariş eşiğinin üstünden eşiğe veya
+// altına ya da sıfıra düşürdüğünde oluşan uyarı. Eşik bir kez geçildiğinde
+// stok tekrar eşiğin üstüne çıkana kadar yeni düşük stok uyarısı oluşmaz.
+type StockAlert struct {
+	ID           uint              `json:"id" gorm:"primaryKey"`
+	ShopID       uint              `json:"shop_id" gorm:"not null;index"`
+	ProductID    uint              `json:"product_id" gorm:"not null;index"`
+	VariantID    *uint             `json:"variant_id"`
+	Kind         StockAlertKind    `json:"kind" gorm:"type:varchar(20);not null"`
+	Stock        float64           `json:"stock" gorm:"type:decimal(12,3);not null"`     // Uyarı anındaki stok
+	Threshold    float64           `json:"threshold" gorm:"type:decimal(12,3);not null"` // Uyarı anındaki yeniden sipariş eşiği
+	MovementType StockMovementType `json:"movement_type" gorm:"type:varchar(20);not null"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Yenileme token'ı. Token'ın kendisi değil SHA-256 özeti saklanır.
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	FamilyID  string     `json:"family_id" gorm:"type:varchar(64);not null;index"` // Aynı girişten türeyen token zinciri
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+
+	// İlişkiler
+	User User `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// İptal edilmiş access token'lar (jti). Süresi dolan kayıtlar temizlenebilir.
+type RevokedToken struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	JTI       string    `json:"jti" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -14,18 +14,42 @@ const (
 	RoleCustomer UserRole = "customer" // Müşteri
 )
 
+func (r UserRole) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleShop, RoleCustomer:
+		return true
+	}
+	return false
+}
+
 type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
-	Password  string         `json:"-" gorm:"not null"`
-	Name      string         `json:"name" gorm:"not null"`
-	Phone     string         `json:"phone"`
-	Role      UserRole       `json:"role" gorm:"type:varchar(20);default:'customer'"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                uint           `json:"id" gorm:"primaryKey"`
+	Email             string         `json:"email" gorm:"uniqueIndex:idx_users_email,where:email <> '';not null"` // Telefonla kayıtlı hesaplarda boş
+	Password          string         `json:"-" gorm:"not null"`
+	Name              string         `json:"name" gorm:"not null"`
+	Phone             string         `json:"phone"`
+	EmailVerifiedAt   *time.Time     `json:"email_verified_at,omitempty"`                              // Doğrulama bağlantısı açılana kadar boş
+	PhoneE164         *string        `json:"phone_e164,omitempty" gorm:"type:varchar(16);uniqueIndex"` // SMS koduyla doğrulanmış, telefonla girişte kullanılan numara
+	Role              UserRole       `json:"role" gorm:"type:varchar(20);default:'customer'"`
+	IsSuspended       bool           `json:"is_suspended" gorm:"default:false"`
+	SuspendedAt       *time.Time     `json:"suspended_at,omitempty"`
+	PasswordChangedAt *time.Time     `json:"-"` // Bu zamandan önce verilen access token'lar geçersiz
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// İlişkiler
 	Shop   *Shop   `json:"shop,omitempty" gorm:"foreignKey:UserID"`
 	Orders []Order `json:"orders,omitempty" gorm:"foreignKey:UserID"`
 }
+
+// EmailVerified kullanıcının e-posta adresini doğrulayıp doğrulamadığını döner.
+func (u User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// Verified kullanıcının e-posta adresini veya telefon numarasını doğrulayıp
+// doğrulamadığını döner.
+func (u User) Verified() bool {
+	return u.EmailVerified() || u.PhoneE164 != nil
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"fmt"
+	"time"
+	"tradesman-api/money"
+)
+
+// Aynı ürünün kendi SKU, fiyat ve stoğu olan çeşidi (ör. simit: sade / susamlı,
+// çay: küçük / büyük). Çeşidi olan ürünlerde stok ve fiyat çeşit üzerinden
+// takip edilir; miktar kuralları (birim, en az miktar, artış) üründen gelir.
+type ProductVariant struct {
+	ID        uint         `json:"id" gorm:"primaryKey"`
+	ProductID uint         `json:"product_id" gorm:"not null;index"`
+	ShopID    uint         `json:"shop_id" gorm:"not null;uniqueIndex:idx_product_variants_shop_sku"`
+	Name      string       `json:"name" gorm:"not null"`
+	SKU       string       `json:"sku" gorm:"type:varchar(64);not null;uniqueIndex:idx_product_variants_shop_sku"` // Dükkan içinde benzersiz
+	Price     money.Amount `json:"price" gorm:"not null"`
+	Stock     float64      `json:"stock" gorm:"type:decimal(12,3);default:0"` // Ürünün birimi cinsinden
+	IsActive  bool         `json:"is_active" gorm:"default:true"`
+	SortOrder int          `json:"sort_order" gorm:"default:0"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Ürüne eklenebilen seçenek grubu (ör. "Ekstralar", "Pişirme"). Siparişte bu
+// gruptan en az MinSelect, en fazla MaxSelect seçenek seçilir; MaxSelect 0 ise
+// üst sınır yoktur.
+type OptionGroup struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProductID uint      `json:"product_id" gorm:"not null;index"`
+	Name      string    `json:"name" gorm:"not null"`
+	MinSelect int       `json:"min_select" gorm:"default:0"`
+	MaxSelect int       `json:"max_select" gorm:"default:1"`
+	SortOrder int       `json:"sort_order" gorm:"default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// İlişkiler
+	Options []ProductOption `json:"options" gorm:"foreignKey:OptionGroupID;constraint:OnDelete:CASCADE"`
+}
+
+// Seçenek grubundaki tek seçenek; PriceModifier birim fiyata eklenir
+// (ör. "Ekstra peynir" +10.00, negatif olabilir).
+type ProductOption struct {
+	ID            uint         `json:"id" gorm:"primaryKey"`
+	OptionGroupID uint         `json:"option_group_id" gorm:"not null;index"`
+	Name          string       `json:"name" gorm:"not null"`
+	PriceModifier money.Amount `json:"price_modifier" gorm:"not null;default:0"`
+	IsActive      bool         `json:"is_active" gorm:"default:true"`
+	SortOrder     int          `json:"sort_order" gorm:"default:0"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// ValidateStock çeşit stoğunu ürünün birim kurallarına göre doğrular.
+func (v ProductVariant) ValidateStock(unit Unit) error {
+	return unit.ValidateStock(v.Stock)
+}
+
+// Validate grubun seçim sınırlarını aktif seçenek sayısına göre doğrular.
+func (g OptionGroup) Validate() error {
+	if g.MinSelect < 0 || g.MaxSelect < 0 {
+		return fmt.Errorf("seçim sınırları negatif olamaz")
+	}
+	if g.MaxSelect != 0 && g.MaxSelect < g.MinSelect {
+		return fmt.Errorf("en fazla seçim (%d) en az seçimden (%d) küçük olamaz", g.MaxSelect, g.MinSelect)
+	}
+	active := 0
+	for _, option := range g.Options {
+		if option.IsActive {
+			active++
+		}
+	}
+	if active < g.MinSelect {
+		return fmt.Errorf("%s grubunda en az %d aktif seçenek olmalı", g.Name, g.MinSelect)
+	}
+	return nil
+}
+
+// SelectOptions müşterinin seçtiği seçenekleri ürünün gruplarına göre doğrular
+// ve sipariş kalemine kopyalanacak halleriyle döner. Her seçenek bir kez
+// seçilebilir; her grubun en az/en fazla seçim sınırına uyulmalı.
+func SelectOptions(groups []OptionGroup, optionIDs []uint) ([]OrderItemOption, error) {
+	type choice struct {
+		group  *OptionGroup
+		option ProductOption
+	}
+	available := make(map[uint]choice)
+	for i := range groups {
+		for _, option := range groups[i].Options {
+			if option.IsActive {
+				available[option.ID] = choice{group: &groups[i], option: option}
+			}
+		}
+	}
+
+	selected := make([]OrderItemOption, 0, len(optionIDs))
+	seen := make(map[uint]bool)
+	counts := make(map[uint]int)
+	for _, id := range optionIDs {
+		ch, ok := available[id]
+		if !ok {
+			return nil, fmt.Errorf("geçersiz seçenek: %d", id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("%s seçeneği birden fazla seçilmiş", ch.option.Name)
+		}
+		seen[id] = true
+		counts[ch.group.ID]++
+		selected = append(selected, OrderItemOption{
+			OptionID:      ch.option.ID,
+			GroupName:     ch.group.Name,
+			Name:          ch.option.Name,
+			PriceModifier: ch.option.PriceModifier,
+		})
+	}
+
+	for _, group := range groups {
+		n := counts[group.ID]
+		if n < group.MinSelect {
+			return nil, fmt.Errorf("%s için en az %d seçenek seçilmeli", group.Name, group.MinSelect)
+		}
+		if group.MaxSelect != 0 && n > group.MaxSelect {
+			return nil, fmt.Errorf("%s için en fazla %d seçenek seçilebilir", group.Name, group.MaxSelect)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Esnafın sipariş olaylarını kendi sistemine (POS, etiket yazıcısı vb.)
+// aldığı adres. Gövdeler Secret ile HMAC-SHA256 imzalanır; Secret yalnızca
+// oluşturulurken ve yenilendiğinde gösterilir.
+type WebhookEndpoint struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	ShopID      uint           `json:"shop_id" gorm:"not null;index"`
+	URL         string         `json:"url" gorm:"type:varchar(2048);not null"`
+	Description string         `json:"description"`
+	Secret      string         `json:"-" gorm:"type:varchar(100);not null"`
+	Events      []string       `json:"events" gorm:"type:text;not null;serializer:json"` // Abone olunan olay tipleri (ör. order.created)
+	IsActive    bool           `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Subscribed uç noktanın olay tipine abone olup olmadığını döner.
+func (e WebhookEndpoint) Subscribed(eventType string) bool {
+	for _, t := range e.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // Gönderilmeyi veya yeniden denenmeyi bekliyor
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // 2xx yanıt alındı
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // Denemeler tükendi veya uç nokta kapatıldı
+)
+
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliverySucceeded, WebhookDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// Bir olayın bir uç noktaya teslimatı. Aynı gövde her denemede yeniden
+// gönderilir; alıcılar tekrarları EventID ile ayıklayabilir.
+type WebhookDelivery struct {
+	ID            uint                  `json:"id" gorm:"primaryKey"`
+	EndpointID    uint                  `json:"endpoint_id" gorm:"not null;index"`
+	ShopID        uint                  `json:"shop_id" gorm:"not null;index"`
+	EventID       uint64                `json:"event_id" gorm:"not null"`
+	EventType     string                `json:"event_type" gorm:"type:varchar(50);not null"`
+	Payload       string                `json:"payload,omitempty" gorm:"type:text;not null"` // İmzalanan JSON gövde
+	Status        WebhookDeliveryStatus `json:"status" gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts      int                   `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt *time.Time            `json:"next_attempt_at" gorm:"index:idx_webhook_deliveries_due,priority:2"`
+	LastAttemptAt *time.Time            `json:"last_attempt_at"`
+	ResponseCode  *int                  `json:"response_code"` // Son denemenin HTTP durum kodu
+	Error         string                `json:"error"`         // Son denemenin hatası
+	DeliveredAt   *time.Time            `json:"delivered_at"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+
+	// İlişkiler
+	History []WebhookAttempt `json:"history,omitempty" gorm:"foreignKey:DeliveryID"`
+}
+
+// Teslimatın tek bir gönderim denemesi.
+type WebhookAttempt struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	DeliveryID   uint      `json:"delivery_id" gorm:"not null;index"`
+	ResponseCode *int      `json:"response_code"`
+	ResponseBody string    `json:"response_body"` // İlk 2 KB
+	Error        string    `json:"error"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,125 @@
+// Package money para tutarlarını kayan nokta hatası olmadan, tam sayı alt birim
+// (kuruş) olarak tutar.
+//
+// Amount veritabanında bigint olarak saklanır; JSON'da ise mobil uygulamanın
+// beklediği gibi ondalıklı sayı olarak yazılır (1250 kuruş → 12.50). JSON'dan
+// okunurken sayı metin olarak ayrıştırılır, float64'e hiç çevrilmez.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Tutarların para birimi ISO 4217 koduyla ayrıca saklanır.
+const DefaultCurrency = "TRY"
+
+// Alt birim basamak sayısı (1 TL = 100 kuruş)
+const (
+	minorDigits = 2
+	minorScale  = 100
+)
+
+var ErrInvalidAmount = errors.New("geçersiz tutar, en fazla 2 ondalık basamaklı bir sayı olmalı")
+
+// Amount kuruş cinsinden tutar.
+type Amount int64
+
+// Parse "12", "12.5" veya "12.50" gibi ondalık bir metni Amount'a çevirir. İkiden
+// fazla ondalık basamak hata döner; tutarlar sessizce yuvarlanmaz.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || (hasFrac && frac == "") || len(frac) > minorDigits || !digitsOnly(whole+frac) {
+		return 0, ErrInvalidAmount
+	}
+	frac += strings.Repeat("0", minorDigits-len(frac))
+
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || w > math.MaxInt64/minorScale-1 {
+		return 0, ErrInvalidAmount
+	}
+	f, _ := strconv.ParseInt(frac, 10, 64)
+
+	a := Amount(w*minorScale + f)
+	if negative {
+		a = -a
+	}
+	return a, nil
+}
+
+func digitsOnly(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String tutarı iki ondalık basamakla yazar (ör. 12.50).
+func (a Amount) String() string {
+	sign := ""
+	v := int64(a)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/minorScale, v%minorScale)
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON sayı (12.5) veya metin ("12.50") kabul eder.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	} else if _, err := json.Number(s).Float64(); err != nil {
+		return ErrInvalidAmount
+	}
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// MulQuantity birim fiyatı 3 ondalık basamaklı bir miktarla çarpar ve sonucu
+// kuruşa yarımdan yukarı (0.5 kuruş ve üstü sıfırdan uzağa) yuvarlar.
+// Örn. 450.00 × 0.355 = 159.75, 0.15 × 0.5 = 0.08.
+func (a Amount) MulQuantity(quantity float64) Amount {
+	milli := int64(math.Round(quantity * 1000))
+	product := int64(a) * milli
+	if product < 0 {
+		return Amount((product - 500) / 1000)
+	}
+	return Amount((product + 500) / 1000)
+}
+
+// ValidCurrency üç büyük harften oluşan ISO 4217 kodları için true döner.
+func ValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
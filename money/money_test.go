@@ -0,0 +1,155 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Amount
+		err  bool
+	}{
+		{"12", 1200, false},
+		{"12.5", 1250, false},
+		{"12.50", 1250, false},
+		{" 0.05 ", 5, false},
+		{"0", 0, false},
+		{"-12.5", -1250, false},
+		{"-0.01", -1, false},
+		{"92233720368547757.99", 9223372036854775799, false},
+		{"12.505", 0, true},            // Üç ondalık basamak yuvarlanmaz
+		{"0.1234", 0, true},            // Dört ondalık basamak
+		{"92233720368547758", 0, true}, // int64 taşması
+		{"99999999999999999999", 0, true},
+		{"", 0, true},
+		{"-", 0, true},
+		{".5", 0, true},
+		{"5.", 0, true},
+		{"--5", 0, true},
+		{"+5", 0, true},
+		{"1e3", 0, true},
+		{"1,50", 0, true},
+		{"abc", 0, true},
+	}
+	for _, tc := range cases {
+		got, err := Parse(tc.in)
+		if tc.err {
+			if !errors.Is(err, ErrInvalidAmount) {
+				t.Errorf("Parse(%q) = %d, %v; beklenen ErrInvalidAmount", tc.in, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("Parse(%q) = %d, %v; beklenen %d", tc.in, got, err, tc.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	cases := []struct {
+		in   Amount
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{1250, "12.50"},
+		{100000, "1000.00"},
+		{-5, "-0.05"},
+		{-1250, "-12.50"},
+	}
+	for _, tc := range cases {
+		if got := tc.in.String(); got != tc.want {
+			t.Errorf("Amount(%d).String() = %q, beklenen %q", int64(tc.in), got, tc.want)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type item struct {
+		Price Amount `json:"price"`
+	}
+
+	// Yazılan değer aynen geri okunur
+	for _, a := range []Amount{0, 5, 1250, -1250, 9223372036854775799} {
+		data, err := json.Marshal(item{Price: a})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got item
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("%s okunamadı: %v", data, err)
+		}
+		if got.Price != a {
+			t.Errorf("%s okunduğunda %d, beklenen %d", data, got.Price, a)
+		}
+	}
+
+	cases := []struct {
+		in   string
+		want Amount
+		err  bool
+	}{
+		{`{"price":12.5}`, 1250, false},
+		{`{"price":"12.50"}`, 1250, false},
+		{`{"price":-0.05}`, -5, false},
+		{`{"price":null}`, 0, false},
+		{`{"price":12.505}`, 0, true},
+		{`{"price":"12.505"}`, 0, true},
+		{`{"price":1e3}`, 0, true},
+		{`{"price":"abc"}`, 0, true},
+		{`{"price":true}`, 0, true},
+	}
+	for _, tc := range cases {
+		var got item
+		err := json.Unmarshal([]byte(tc.in), &got)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: hata bekleniyordu, %d okundu", tc.in, got.Price)
+			}
+			continue
+		}
+		if err != nil || got.Price != tc.want {
+			t.Errorf("%s = %d, %v; beklenen %d", tc.in, got.Price, err, tc.want)
+		}
+	}
+
+	data, _ := json.Marshal(item{Price: 1250})
+	if string(data) != `{"price":12.50}` {
+		t.Errorf("JSON %s, beklenen {\"price\":12.50}", data)
+	}
+}
+
+func TestMulQuantity(t *testing.T) {
+	cases := []struct {
+		price    Amount
+		quantity float64
+		want     Amount
+	}{
+		{45000, 0.355, 15975}, // 450.00 × 0.355 = 159.75
+		{15, 0.5, 8},          // 7.5 kuruş yukarı yuvarlanır
+		{15, 0.3, 5},          // 4.5 kuruş
+		{11, 0.4, 4},          // 4.4 kuruş aşağı
+		{-15, 0.5, -8},        // Negatifte yarım sıfırdan uzağa
+		{-11, 0.4, -4},
+		{1250, 3, 3750},
+		{1250, 0, 0},
+		{999, 0.001, 1}, // 0.999 kuruş
+		{499, 0.001, 0}, // 0.499 kuruş
+	}
+	for _, tc := range cases {
+		if got := tc.price.MulQuantity(tc.quantity); got != tc.want {
+			t.Errorf("%s × %v = %d, beklenen %d", tc.price, tc.quantity, got, tc.want)
+		}
+	}
+}
+
+func TestValidCurrency(t *testing.T) {
+	for code, want := range map[string]bool{"TRY": true, "EUR": true, "try": false, "TR": false, "TRYY": false, "T1Y": false} {
+		if got := ValidCurrency(code); got != want {
+			t.Errorf("ValidCurrency(%q) = %v, beklenen %v", code, got, want)
+		}
+	}
+}
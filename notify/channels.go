@@ -0,0 +1,180 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+	"tradesman-api/models"
+
+	"gorm.io/gorm"
+)
+
+// Outbox mesajları göndermek yerine notification_outbox tablosuna yazar.
+type Outbox struct {
+	DB *gorm.DB
+}
+
+func (o Outbox) Send(ctx context.Context, msg Message) error {
+	return o.DB.WithContext(ctx).Create(&models.OutboxMessage{
+		Channel:   msg.Channel,
+		Recipient: msg.To,
+		UserID:    msg.UserID,
+		Kind:      string(msg.Kind),
+		Language:  msg.Language,
+		Subject:   msg.Subject,
+		Body:      msg.Body,
+	}).Error
+}
+
+// Log mesajları göndermek yerine sunucu günlüğüne yazar; yerel geliştirmede
+// SMS kodlarını görmek için kullanılır.
+type Log struct{}
+
+func (Log) Send(ctx context.Context, msg Message) error {
+	if msg.Subject != "" {
+		log.Printf("📨 %s → %s [%s] %s\n%s", msg.Channel, msg.To, msg.Kind, msg.Subject, msg.Body)
+	} else {
+		log.Printf("📨 %s → %s [%s] %s", msg.Channel, msg.To, msg.Kind, msg.Body)
+	}
+	return nil
+}
+
+// File mesajları göndermek yerine dosyaya satır başına bir JSON olarak ekler.
+type File struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewFile(path string) *File {
+	return &File{Path: path}
+}
+
+func (f *File) Send(ctx context.Context, msg Message) error {
+	line, err := json.Marshal(map[string]interface{}{
+		"channel":    msg.Channel,
+		"to":         msg.To,
+		"user_id":    msg.UserID,
+		"kind":       msg.Kind,
+		"language":   msg.Language,
+		"subject":    msg.Subject,
+		"body":       msg.Body,
+		"created_at": time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// SMTP e-postaları düz metin olarak SMTP sunucusu üzerinden gönderir. Sunucu
+// destekliyorsa STARTTLS kullanılır; 465 portunda doğrudan TLS ile bağlanılır.
+// Username boşsa kimlik doğrulama yapılmaz.
+type SMTP struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string // "Esnaf <noreply@example.com>" veya yalnızca adres
+}
+
+func (s SMTP) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("gönderen adresi geçersiz: %w", err)
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("alıcı adresi geçersiz: %w", err)
+	}
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	if s.Port == 465 {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.Host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && s.Port != 465 {
+		if err := client.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.message(from, to, msg)); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (s SMTP) message(from, to *mail.Address, msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", to.String())
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&b)
+	qp.Write(bytes.ReplaceAll([]byte(msg.Body), []byte("\n"), []byte("\r\n")))
+	qp.Close()
+	return b.Bytes()
+}
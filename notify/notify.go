@@ -0,0 +1,267 @@
+// Package notify kullanıcılara e-posta, SMS ve push bildirimleri gönderir.
+//
+// Bildirimler Notify ile kuyruğa alınır ve arka planda gönderilir; böylece
+// sipariş gibi istekler sağlayıcıları beklemez. Her bildirim türünün Türkçe ve
+// İngilizce şablonu vardır; kullanıcının dil ve kanal tercihleri
+// notification_preferences tablosundan okunur. Kanallar Channel arayüzünü
+// uygular: e-posta SMTP ile gönderilebilir, SMS ve push için gerçek bir
+// sağlayıcı eklenene kadar mesajlar giden kutusuna (tablo veya dosya) yazılır.
+//
+// Kuyruk bellekte tutulur; sunucu kapanırken gönderilmemiş bildirimler kaybolur.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"tradesman-api/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	queueSize   = 1000
+	workers     = 4
+	maxAttempts = 3
+	sendTimeout = 30 * time.Second
+)
+
+// Message tek bir alıcıya tek kanaldan giden, şablonu işlenmiş bildirim.
+type Message struct {
+	Channel  models.NotificationChannel
+	To       string // E-posta adresi, telefon veya cihaz token'ı
+	UserID   *uint
+	Kind     Kind
+	Language string
+	Subject  string // Yalnızca e-posta ve push başlığı
+	Body     string
+}
+
+// Channel mesajı bir sağlayıcı üzerinden gönderir.
+type Channel interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// ErrNoChannel kanal için gönderici kurulmadığında döner.
+var ErrNoChannel = errors.New("bildirim kanalı kurulmamış")
+
+// Default sunucunun bildirim servisi; serve komutu kurar. Kurulmamışsa (CLI
+// komutları) bildirimler gönderilmez.
+var Default *Service
+
+type job struct {
+	userID    uint
+	kind      Kind
+	data      interface{}
+	emailOnly bool // Kanal tercihlerine bakmadan yalnızca e-posta gönder
+}
+
+type Service struct {
+	db       *gorm.DB
+	channels map[models.NotificationChannel]Channel
+	queue    chan job
+}
+
+// NewService verilen kanallarla servis kurar; kanalı olmayan türler gönderilmez.
+func NewService(db *gorm.DB, channels map[models.NotificationChannel]Channel) *Service {
+	return &Service{
+		db:       db,
+		channels: channels,
+		queue:    make(chan job, queueSize),
+	}
+}
+
+// Notify bildirimi Default servisin kuyruğuna alır. Servis yoksa veya kuyruk
+// doluysa bildirim atılır; çağıranı hiçbir zaman bekletmez.
+func Notify(userID uint, kind Kind, data interface{}) {
+	if Default != nil {
+		Default.Notify(userID, kind, data)
+	}
+}
+
+func (s *Service) Notify(userID uint, kind Kind, data interface{}) {
+	s.enqueue(job{userID: userID, kind: kind, data: data})
+}
+
+// Transactional hesapla ilgili bildirimi (şifre sıfırlama, e-posta doğrulama)
+// kullanıcının kanal tercihlerine bakmadan yalnızca e-postayla gönderir; dil
+// tercihi yine uygulanır. Notify gibi çağıranı bekletmez.
+func Transactional(userID uint, kind Kind, data interface{}) {
+	if Default != nil {
+		Default.Transactional(userID, kind, data)
+	}
+}
+
+func (s *Service) Transactional(userID uint, kind Kind, data interface{}) {
+	s.enqueue(job{userID: userID, kind: kind, data: data, emailOnly: true})
+}
+
+// SendNow mesajı kuyruğa almadan tek denemede gönderir ve sonucunu döner.
+// Henüz hesabı olmayan alıcılar içindir (ör. telefonla girişte SMS kodu);
+// kullanıcı tercihleri uygulanmaz. Default servis yoksa ErrNoChannel döner.
+func SendNow(ctx context.Context, channel models.NotificationChannel, to string, kind Kind, lang string, data interface{}) error {
+	if Default == nil {
+		return ErrNoChannel
+	}
+	return Default.SendNow(ctx, channel, to, kind, lang, data)
+}
+
+func (s *Service) SendNow(ctx context.Context, channel models.NotificationChannel, to string, kind Kind, lang string, data interface{}) error {
+	sender, ok := s.channels[channel]
+	if !ok {
+		return ErrNoChannel
+	}
+	msg, ok, err := render(kind, channel, lang, data)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s bildirim türü bilinmiyor", kind)
+	}
+	msg.To = to
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	return sender.Send(ctx, msg)
+}
+
+func (s *Service) enqueue(j job) {
+	select {
+	case s.queue <- j:
+	default:
+		log.Printf("bildirim kuyruğu dolu, %s bildirimi atıldı (kullanıcı %d)", j.kind, j.userID)
+	}
+}
+
+// Run ctx bitene kadar kuyruktaki bildirimleri gönderir.
+func (s *Service) Run(ctx context.Context) {
+	done := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case j := <-s.queue:
+					if err := s.process(ctx, j); err != nil {
+						log.Printf("%s bildirimi gönderilemedi (kullanıcı %d): %v", j.kind, j.userID, err)
+					}
+				}
+			}
+		}()
+	}
+	for i := 0; i < workers; i++ {
+		<-done
+	}
+}
+
+// process kullanıcının tercihlerine göre mesajları oluşturur ve gönderir.
+func (s *Service) process(ctx context.Context, j job) error {
+	var user models.User
+	if err := s.db.Select("id", "email", "email_verified_at", "phone", "phone_e164").First(&user, j.userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	pref, err := Preference(s.db, user.ID)
+	if err != nil {
+		return err
+	}
+
+	channels := []models.NotificationChannel{models.ChannelEmail, models.ChannelSMS, models.ChannelPush}
+	if j.emailOnly {
+		channels = channels[:1]
+	}
+
+	var errs []error
+	for _, channel := range channels {
+		if !j.emailOnly && !pref.Enabled(channel) {
+			continue
+		}
+		sender, ok := s.channels[channel]
+		if !ok {
+			continue
+		}
+		recipients, err := s.recipients(user, channel, j.emailOnly)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(recipients) == 0 {
+			continue
+		}
+
+		msg, ok, err := render(j.kind, channel, pref.Language, j.data)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !ok {
+			continue // Bu tür bu kanaldan gönderilmiyor
+		}
+		msg.UserID = &user.ID
+		for _, to := range recipients {
+			msg.To = to
+			if err := send(ctx, sender, msg); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// recipients kullanıcının kanaldaki adreslerini döner.
+func (s *Service) recipients(user models.User, channel models.NotificationChannel, transactional bool) ([]string, error) {
+	switch channel {
+	case models.ChannelEmail:
+		// Yalnızca doğrulanmış adrese gönderilir; kayıtta yazılan adres başkasına
+		// ait olabilir. Hesap e-postaları (doğrulama bağlantısı dahil) istisnadır.
+		if user.Email != "" && (transactional || user.EmailVerified()) {
+			return []string{user.Email}, nil
+		}
+	case models.ChannelSMS:
+		// Yalnızca SMS koduyla doğrulanmış numaraya gönderilir; profildeki
+		// serbest biçimli numara başkasına ait olabilir
+		if user.PhoneE164 != nil {
+			return []string{*user.PhoneE164}, nil
+		}
+	case models.ChannelPush:
+		var tokens []string
+		err := s.db.Model(&models.PushDevice{}).Where("user_id = ?", user.ID).Order("id").Pluck("token", &tokens).Error
+		return tokens, err
+	}
+	return nil, nil
+}
+
+// send mesajı geçici hatalara karşı birkaç kez dener.
+func send(ctx context.Context, channel Channel, msg Message) error {
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err = channel.Send(sendCtx, msg)
+		cancel()
+		if err == nil || attempt == maxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * time.Second):
+		}
+	}
+	return err
+}
+
+// Preference kullanıcının bildirim tercihlerini döner; kaydı yoksa varsayılanları.
+func Preference(db *gorm.DB, userID uint) (models.NotificationPreference, error) {
+	var pref models.NotificationPreference
+	err := db.Where("user_id = ?", userID).First(&pref).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultNotificationPreference(userID), nil
+	}
+	return pref, err
+}
@@ -0,0 +1,344 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"tradesman-api/models"
+)
+
+// Kind bildirim türü; her türün dil başına şablonu vardır.
+type Kind string
+
+const (
+	OrderReceived Kind = "order_received" // Esnafa: dükkana yeni sipariş geldi
+	OrderPlaced   Kind = "order_placed"   // Müşteriye: sipariş alındı
+	OrderReady    Kind = "order_ready"    // Müşteriye: sipariş hazır, teslim alınabilir
+
+	// Esnafa: ürün yeniden sipariş eşiğine düştü veya tükendi
+	StockAlert Kind = "stock_alert"
+
+	// Hesap e-postaları; Transactional ile gönderilir
+	PasswordReset     Kind = "password_reset"
+	EmailVerification Kind = "email_verification"
+
+	// Telefonla girişte ve numara doğrulamada SMS kodu; SendNow ile gönderilir,
+	// yalnızca SMS şablonu var
+	PhoneCode         Kind = "phone_code"
+	PhoneVerification Kind = "phone_verification"
+)
+
+// OrderData sipariş bildirimlerinin şablon verisi.
+type OrderData struct {
+	OrderID      uint
+	ShopName     string
+	ShopAddress  string
+	ShopPhone    string
+	CustomerName string
+	Total        string // Para birimiyle, ör. "125.50 TRY"
+	ItemCount    int
+	Note         string
+}
+
+// StockAlertData stok uyarısı bildiriminin şablon verisi.
+type StockAlertData struct {
+	ProductID   uint
+	ProductName string // Çeşit varsa "Çay (Büyük)" biçiminde
+	Stock       string // Birimiyle, ör. "2 piece"
+	Threshold   string
+	OutOfStock  bool
+}
+
+// AccountData hesap e-postalarının ve SMS kodunun şablon verisi.
+type AccountData struct {
+	Name     string
+	Link     string        // Token'ı içeren istemci bağlantısı
+	Token    string        // Bağlantı açılamazsa elle girilebilsin diye; SMS'te kodun kendisi
+	ValidFor time.Duration // Bağlantının geçerlilik süresi
+}
+
+// Hours ValidFor tam saatse saat sayısını, değilse 0 döner.
+func (d AccountData) Hours() int {
+	if d.ValidFor < time.Hour || d.ValidFor%time.Hour != 0 {
+		return 0
+	}
+	return int(d.ValidFor / time.Hour)
+}
+
+// Minutes ValidFor'u dakika olarak döner.
+func (d AccountData) Minutes() int {
+	return int(d.ValidFor / time.Minute)
+}
+
+// Bir türün bir dildeki şablonları. Subject e-posta konusu ve push başlığıdır,
+// Body e-posta gövdesi, Short SMS ve push metnidir.
+type templateSet struct {
+	Subject string
+	Body    string
+	Short   string
+}
+
+var templateSources = map[Kind]map[string]templateSet{
+	OrderReceived: {
+		"tr": {
+			Subject: "Yeni sipariş #{{.OrderID}}",
+			Body: `Merhaba,
+
+{{.ShopName}} dükkanınıza {{.CustomerName}} tarafından yeni bir sipariş verildi.
+
+Sipariş no: #{{.OrderID}}
+Ürün sayısı: {{.ItemCount}}
+Toplam: {{.Total}}
+{{- if .Note}}
+Not: {{.Note}}
+{{- end}}
+
+Siparişi onaylamak için uygulamayı açın.`,
+			Short: "Yeni sipariş #{{.OrderID}}: {{.ItemCount}} ürün, {{.Total}}",
+		},
+		"en": {
+			Subject: "New order #{{.OrderID}}",
+			Body: `Hello,
+
+{{.CustomerName}} placed a new order at {{.ShopName}}.
+
+Order no: #{{.OrderID}}
+Items: {{.ItemCount}}
+Total: {{.Total}}
+{{- if .Note}}
+Note: {{.Note}}
+{{- end}}
+
+Open the app to confirm the order.`,
+			Short: "New order #{{.OrderID}}: {{.ItemCount}} items, {{.Total}}",
+		},
+	},
+	OrderPlaced: {
+		"tr": {
+			Subject: "Siparişiniz alındı (#{{.OrderID}})",
+			Body: `Merhaba {{.CustomerName}},
+
+{{.ShopName}} siparişinizi aldı. Sipariş hazır olduğunda size haber vereceğiz.
+
+Sipariş no: #{{.OrderID}}
+Ürün sayısı: {{.ItemCount}}
+Toplam: {{.Total}}`,
+			Short: "{{.ShopName}} siparişinizi aldı (#{{.OrderID}}, {{.Total}})",
+		},
+		"en": {
+			Subject: "Your order has been received (#{{.OrderID}})",
+			Body: `Hello {{.CustomerName}},
+
+{{.ShopName}} has received your order. We will let you know when it is ready.
+
+Order no: #{{.OrderID}}
+Items: {{.ItemCount}}
+Total: {{.Total}}`,
+			Short: "{{.ShopName}} received your order (#{{.OrderID}}, {{.Total}})",
+		},
+	},
+	OrderReady: {
+		"tr": {
+			Subject: "Siparişiniz hazır (#{{.OrderID}})",
+			Body: `Merhaba {{.CustomerName}},
+
+{{.ShopName}} siparişinizi hazırladı, teslim alabilirsiniz.
+
+Sipariş no: #{{.OrderID}}
+Toplam: {{.Total}}
+{{- if .ShopAddress}}
+Adres: {{.ShopAddress}}
+{{- end}}
+{{- if .ShopPhone}}
+Telefon: {{.ShopPhone}}
+{{- end}}`,
+			Short: "Siparişiniz hazır: {{.ShopName}} #{{.OrderID}}",
+		},
+		"en": {
+			Subject: "Your order is ready (#{{.OrderID}})",
+			Body: `Hello {{.CustomerName}},
+
+{{.ShopName}} has prepared your order and it is ready for pickup.
+
+Order no: #{{.OrderID}}
+Total: {{.Total}}
+{{- if .ShopAddress}}
+Address: {{.ShopAddress}}
+{{- end}}
+{{- if .ShopPhone}}
+Phone: {{.ShopPhone}}
+{{- end}}`,
+			Short: "Your order is ready: {{.ShopName}} #{{.OrderID}}",
+		},
+	},
+	StockAlert: {
+		"tr": {
+			Subject: "{{if .OutOfStock}}Stok tükendi{{else}}Stok azaldı{{end}}: {{.ProductName}}",
+			Body: `Merhaba,
+
+{{if .OutOfStock -}}
+{{.ProductName}} ürününün stoğu tükendi. Ürün yeniden stok girilene kadar
+sipariş alamaz.
+{{- else -}}
+{{.ProductName}} ürününün stoğu yeniden sipariş eşiğine düştü.
+
+Kalan stok: {{.Stock}}
+Eşik: {{.Threshold}}
+{{- end}}
+
+Stok girmek için uygulamayı açın.`,
+			Short: "{{if .OutOfStock}}Stok tükendi: {{.ProductName}}{{else}}Stok azaldı: {{.ProductName}}, kalan {{.Stock}}{{end}}",
+		},
+		"en": {
+			Subject: "{{if .OutOfStock}}Out of stock{{else}}Low stock{{end}}: {{.ProductName}}",
+			Body: `Hello,
+
+{{if .OutOfStock -}}
+{{.ProductName}} is out of stock. It cannot be ordered until it is
+restocked.
+{{- else -}}
+The stock of {{.ProductName}} has fallen to its reorder threshold.
+
+In stock: {{.Stock}}
+Threshold: {{.Threshold}}
+{{- end}}
+
+Open the app to restock.`,
+			Short: "{{if .OutOfStock}}Out of stock: {{.ProductName}}{{else}}Low stock: {{.ProductName}}, {{.Stock}} left{{end}}",
+		},
+	},
+	PasswordReset: {
+		"tr": {
+			Subject: "Şifre sıfırlama",
+			Body: `Merhaba {{.Name}},
+
+Hesabınız için şifre sıfırlama isteği aldık. Yeni şifrenizi belirlemek için
+aşağıdaki bağlantıyı açın:
+
+{{.Link}}
+
+Bağlantı {{if .Hours}}{{.Hours}} saat{{else}}{{.Minutes}} dakika{{end}} geçerlidir ve yalnızca bir kez kullanılabilir.
+Bu isteği siz yapmadıysanız bu e-postayı dikkate almayın; şifreniz değişmez.`,
+			Short: "Şifre sıfırlama kodunuz: {{.Token}}",
+		},
+		"en": {
+			Subject: "Reset your password",
+			Body: `Hello {{.Name}},
+
+We received a request to reset the password for your account. Open the link
+below to choose a new password:
+
+{{.Link}}
+
+The link is valid for {{if .Hours}}{{.Hours}} hour(s){{else}}{{.Minutes}} minutes{{end}} and can be used once.
+If you did not request this, ignore this email; your password will not change.`,
+			Short: "Your password reset code: {{.Token}}",
+		},
+	},
+	EmailVerification: {
+		"tr": {
+			Subject: "E-posta adresinizi doğrulayın",
+			Body: `Merhaba {{.Name}},
+
+E-posta adresinizi doğrulamak için aşağıdaki bağlantıyı açın:
+
+{{.Link}}
+
+Bağlantı {{if .Hours}}{{.Hours}} saat{{else}}{{.Minutes}} dakika{{end}} geçerlidir. Bazı dükkanlar yalnızca
+e-postası doğrulanmış müşterilerden sipariş kabul eder.`,
+			Short: "E-posta doğrulama kodunuz: {{.Token}}",
+		},
+		"en": {
+			Subject: "Verify your email address",
+			Body: `Hello {{.Name}},
+
+Open the link below to verify your email address:
+
+{{.Link}}
+
+The link is valid for {{if .Hours}}{{.Hours}} hour(s){{else}}{{.Minutes}} minutes{{end}}. Some shops only accept
+orders from customers with a verified email address.`,
+			Short: "Your email verification code: {{.Token}}",
+		},
+	},
+	PhoneCode: {
+		"tr": {
+			Short: "Esnaf giriş kodunuz: {{.Token}}. Kod {{.Minutes}} dakika geçerlidir, kimseyle paylaşmayın.",
+		},
+		"en": {
+			Short: "Your Esnaf login code: {{.Token}}. It is valid for {{.Minutes}} minutes, do not share it.",
+		},
+	},
+	PhoneVerification: {
+		"tr": {
+			Short: "Esnaf telefon doğrulama kodunuz: {{.Token}}. Kod {{.Minutes}} dakika geçerlidir, kimseyle paylaşmayın.",
+		},
+		"en": {
+			Short: "Your Esnaf phone verification code: {{.Token}}. It is valid for {{.Minutes}} minutes, do not share it.",
+		},
+	},
+}
+
+type parsedSet struct {
+	subject, body, short *template.Template
+}
+
+var templates = parseTemplates()
+
+func parseTemplates() map[Kind]map[string]parsedSet {
+	parsed := make(map[Kind]map[string]parsedSet, len(templateSources))
+	for kind, langs := range templateSources {
+		parsed[kind] = make(map[string]parsedSet, len(langs))
+		for lang, src := range langs {
+			name := string(kind) + "." + lang
+			parsed[kind][lang] = parsedSet{
+				subject: template.Must(template.New(name + ".subject").Parse(src.Subject)),
+				body:    template.Must(template.New(name + ".body").Parse(src.Body)),
+				short:   template.Must(template.New(name + ".short").Parse(src.Short)),
+			}
+		}
+	}
+	return parsed
+}
+
+// render türün kullanıcının dilindeki şablonunu kanal için işler. Dilin şablonu
+// yoksa varsayılan dil kullanılır; türün şablonu yoksa false döner.
+func render(kind Kind, channel models.NotificationChannel, lang string, data interface{}) (Message, bool, error) {
+	langs, ok := templates[kind]
+	if !ok {
+		return Message{}, false, nil
+	}
+	set, ok := langs[lang]
+	if !ok {
+		lang = models.NotificationLanguages[0]
+		set = langs[lang]
+	}
+
+	msg := Message{Channel: channel, Kind: kind, Language: lang}
+	var err error
+	switch channel {
+	case models.ChannelEmail:
+		if msg.Subject, err = execute(set.subject, data); err == nil {
+			msg.Body, err = execute(set.body, data)
+		}
+	case models.ChannelPush:
+		if msg.Subject, err = execute(set.subject, data); err == nil {
+			msg.Body, err = execute(set.short, data)
+		}
+	default:
+		msg.Body, err = execute(set.short, data)
+	}
+	if err != nil {
+		return msg, false, fmt.Errorf("%s şablonu işlenemedi: %w", kind, err)
+	}
+	return msg, true, nil
+}
+
+func execute(t *template.Template, data interface{}) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
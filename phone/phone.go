@@ -0,0 +1,74 @@
+// Package phone Türkiye cep telefonu numaralarını E.164 biçimine çevirir.
+//
+// Kullanıcılar numarayı "0555 444 44 44", "(555) 444-4444", "+90 555 444 44 44"
+// veya "0090 555 444 44 44" gibi farklı biçimlerde yazar; giriş ve SMS için
+// hepsi "+905554444444" olarak saklanır. SMS gönderilebilmesi için yalnızca
+// 5 ile başlayan cep numaraları kabul edilir.
+package phone
+
+import (
+	"errors"
+	"strings"
+)
+
+const countryCode = "90"
+
+var (
+	ErrInvalid     = errors.New("geçersiz telefon numarası, ör. 0555 444 44 44")
+	ErrNotMobile   = errors.New("telefon numarası 5 ile başlayan bir cep numarası olmalı")
+	ErrUnsupported = errors.New("yalnızca Türkiye (+90) numaraları destekleniyor")
+)
+
+// Normalize numarayı "+90" ile başlayan E.164 biçimine çevirir. Boşluk, tire,
+// nokta ve parantezler yok sayılır.
+func Normalize(raw string) (string, error) {
+	var b strings.Builder
+	plus := false
+	for i, r := range strings.TrimSpace(raw) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			plus = true
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", ErrInvalid
+		}
+	}
+	digits := b.String()
+
+	// Uluslararası ön ek: +90..., 0090..., 90... (12 hane)
+	switch {
+	case plus:
+		if !strings.HasPrefix(digits, countryCode) {
+			return "", ErrUnsupported
+		}
+		digits = digits[len(countryCode):]
+	case strings.HasPrefix(digits, "00"):
+		if !strings.HasPrefix(digits[2:], countryCode) {
+			return "", ErrUnsupported
+		}
+		digits = digits[2+len(countryCode):]
+	case len(digits) == 12 && strings.HasPrefix(digits, countryCode):
+		digits = digits[len(countryCode):]
+	case len(digits) == 11 && digits[0] == '0':
+		digits = digits[1:]
+	}
+
+	if len(digits) != 10 {
+		return "", ErrInvalid
+	}
+	if digits[0] != '5' {
+		return "", ErrNotMobile
+	}
+	return "+" + countryCode + digits, nil
+}
+
+// Mask normalize edilmiş numaranın yalnızca operatör kodunu ve son iki
+// hanesini gösterir: "+90 555 *** ** 44".
+func Mask(e164 string) string {
+	if len(e164) != 13 || !strings.HasPrefix(e164, "+"+countryCode) {
+		return e164
+	}
+	return e164[:3] + " " + e164[3:6] + " *** ** " + e164[11:]
+}
@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"strings"
+	"tradesman-api/config"
 	"tradesman-api/controllers"
 	"tradesman-api/middleware"
 	"tradesman-api/models"
@@ -11,22 +13,18 @@ import (
 )
 
 func SetupRoutes() *gin.Engine {
-	r := gin.Default()
+	// gin.Default yerine: akış uçlarının access_token parametresi günlüğe yazılmaz
+	r := gin.New()
+	r.Use(middleware.Logger(), gin.Recovery())
+
+	// İstemci adresi (SMS gönderim sınırları) yalnızca tanımlı vekil sunucuların
+	// X-Forwarded-For başlığından okunur; tanımlı değilse bağlantı adresi kullanılır
+	if err := r.SetTrustedProxies(config.App.TrustedProxies); err != nil {
+		panic(err)
+	}
 
 	// CORS middleware
-	r.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-
-		c.Next()
-	})
+	r.Use(middleware.CORS(config.App.CORSOrigins))
 
 	// Health check endpoint
 	r.GET("/", func(c *gin.Context) {
@@ -37,6 +35,11 @@ func SetupRoutes() *gin.Engine {
 		})
 	})
 
+	// Yerel depodaki yüklenen dosyalar (STORAGE_URL bir CDN adresiyse orası sunar)
+	if strings.HasPrefix(config.App.StorageURL, "/") {
+		r.Static(config.App.StorageURL, config.App.StorageDir)
+	}
+
 	// Swagger documentation
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -45,12 +48,22 @@ func SetupRoutes() *gin.Engine {
 	shopController := &controllers.ShopController{}
 	productController := &controllers.ProductController{}
 	orderController := &controllers.OrderController{}
+	userController := &controllers.UserController{}
+	categoryController := &controllers.CategoryController{}
+	webhookController := &controllers.WebhookController{}
+	notificationController := &controllers.NotificationController{}
 
 	// Public routes
 	auth := r.Group("/auth")
 	{
 		auth.POST("/register", authController.Register)
 		auth.POST("/login", authController.Login)
+		auth.POST("/refresh", authController.Refresh)
+		auth.POST("/forgot-password", authController.ForgotPassword)
+		auth.POST("/reset-password", authController.ResetPassword)
+		auth.POST("/verify-email", authController.VerifyEmail)
+		auth.POST("/phone/code", authController.RequestPhoneCode)
+		auth.POST("/phone/login", authController.PhoneLogin)
 	}
 
 	// Public shop and product routes (for customers to browse)
@@ -60,7 +73,19 @@ func SetupRoutes() *gin.Engine {
 		public.GET("/shops/:id", shopController.GetShop)
 		public.GET("/shops/:id/products", shopController.GetShopProducts)
 		public.GET("/products", productController.GetProducts)
+		public.GET("/products/search", productController.SearchProducts)
 		public.GET("/products/:id", productController.GetProduct)
+		public.GET("/categories", categoryController.GetCategories)
+		public.GET("/categories/:id", categoryController.GetCategory)
+		public.GET("/categories/:id/products", categoryController.GetCategoryProducts)
+	}
+
+	// Canlı sipariş akışı; tarayıcı istemcileri token'ı access_token parametresiyle gönderir
+	streamRoutes := r.Group("/orders")
+	streamRoutes.Use(middleware.TokenFromQuery(), middleware.AuthMiddleware())
+	{
+		streamRoutes.GET("/stream", orderController.StreamOrders)
+		streamRoutes.GET("/ws", orderController.OrdersWebSocket)
 	}
 
 	// Protected routes
@@ -69,6 +94,10 @@ func SetupRoutes() *gin.Engine {
 	{
 		// Auth routes
 		protected.GET("/auth/me", authController.Me)
+		protected.POST("/auth/logout", authController.Logout)
+		protected.POST("/auth/resend-verification", authController.ResendVerification)
+		protected.POST("/auth/phone/verify/code", middleware.RequireRole(models.RoleCustomer), authController.RequestPhoneVerification)
+		protected.POST("/auth/phone/verify", middleware.RequireRole(models.RoleCustomer), authController.VerifyPhone)
 
 		// Shop management (only for shop role)
 		shopRoutes := protected.Group("/shops")
@@ -82,18 +111,78 @@ func SetupRoutes() *gin.Engine {
 		productRoutes := protected.Group("/products")
 		{
 			productRoutes.POST("", middleware.RequireRole(models.RoleShop), productController.CreateProduct)
+			productRoutes.POST("/import", middleware.RequireRole(models.RoleShop), productController.ImportProducts)
+			productRoutes.GET("/export", middleware.RequireRole(models.RoleShop), productController.ExportProducts)
+			productRoutes.GET("/low-stock", middleware.RequireRole(models.RoleShop), productController.GetLowStock)
+			productRoutes.GET("/stock-alerts", middleware.RequireRole(models.RoleShop), productController.GetStockAlerts)
 			productRoutes.PUT("/:id", middleware.RequireRole(models.RoleShop), productController.UpdateProduct)
 			productRoutes.DELETE("/:id", middleware.RequireRole(models.RoleShop), productController.DeleteProduct)
+			productRoutes.GET("/:id/variants", middleware.RequireRole(models.RoleShop), productController.GetProductVariants)
+			productRoutes.POST("/:id/variants", middleware.RequireRole(models.RoleShop), productController.CreateVariant)
+			productRoutes.PUT("/:id/variants/:variantId", middleware.RequireRole(models.RoleShop), productController.UpdateVariant)
+			productRoutes.DELETE("/:id/variants/:variantId", middleware.RequireRole(models.RoleShop), productController.DeleteVariant)
+			productRoutes.POST("/:id/option-groups", middleware.RequireRole(models.RoleShop), productController.CreateOptionGroup)
+			productRoutes.PUT("/:id/option-groups/:groupId", middleware.RequireRole(models.RoleShop), productController.UpdateOptionGroup)
+			productRoutes.DELETE("/:id/option-groups/:groupId", middleware.RequireRole(models.RoleShop), productController.DeleteOptionGroup)
+			productRoutes.POST("/:id/images", middleware.RequireRole(models.RoleShop), productController.UploadProductImages)
+			productRoutes.PUT("/:id/images/order", middleware.RequireRole(models.RoleShop), productController.ReorderProductImages)
+			productRoutes.DELETE("/:id/images/:imageId", middleware.RequireRole(models.RoleShop), productController.DeleteProductImage)
+			productRoutes.GET("/:id/stock/movements", middleware.RequireRole(models.RoleShop), productController.GetStockMovements)
+			productRoutes.POST("/:id/stock/receive", middleware.RequireRole(models.RoleShop), productController.ReceiveStock)
+			productRoutes.POST("/:id/stock/adjust", middleware.RequireRole(models.RoleShop), productController.AdjustStock)
 		}
 
 		// Order management
 		orderRoutes := protected.Group("/orders")
 		{
-			orderRoutes.POST("", middleware.RequireRole(models.RoleCustomer), orderController.CreateOrder)
+			orderRoutes.POST("", middleware.RequireRole(models.RoleCustomer), middleware.Idempotency(), orderController.CreateOrder)
 			orderRoutes.GET("", orderController.GetMyOrders)
 			orderRoutes.GET("/:id", orderController.GetOrder)
+			orderRoutes.GET("/:id/history", orderController.GetOrderHistory)
+			orderRoutes.POST("/:id/cancel", middleware.RequireRole(models.RoleCustomer), orderController.CancelOrder)
 			orderRoutes.PUT("/:id/status", middleware.RequireRole(models.RoleShop), orderController.UpdateOrderStatus)
 		}
+
+		// Notification preferences and push devices
+		notificationRoutes := protected.Group("/notifications")
+		{
+			notificationRoutes.GET("/preferences", notificationController.GetPreferences)
+			notificationRoutes.PUT("/preferences", notificationController.UpdatePreferences)
+			notificationRoutes.GET("/devices", notificationController.GetDevices)
+			notificationRoutes.POST("/devices", notificationController.RegisterDevice)
+			notificationRoutes.DELETE("/devices/:id", notificationController.DeleteDevice)
+		}
+
+		// Webhooks (only for shop role)
+		webhookRoutes := protected.Group("/webhooks")
+		webhookRoutes.Use(middleware.RequireRole(models.RoleShop))
+		{
+			webhookRoutes.GET("", webhookController.GetWebhooks)
+			webhookRoutes.POST("", webhookController.CreateWebhook)
+			webhookRoutes.GET("/:id", webhookController.GetWebhook)
+			webhookRoutes.PUT("/:id", webhookController.UpdateWebhook)
+			webhookRoutes.DELETE("/:id", webhookController.DeleteWebhook)
+			webhookRoutes.POST("/:id/rotate-secret", webhookController.RotateWebhookSecret)
+			webhookRoutes.POST("/:id/ping", webhookController.PingWebhook)
+			webhookRoutes.GET("/:id/deliveries", webhookController.GetWebhookDeliveries)
+			webhookRoutes.GET("/:id/deliveries/:deliveryId", webhookController.GetWebhookDelivery)
+			webhookRoutes.POST("/:id/deliveries/:deliveryId/redeliver", webhookController.RedeliverWebhook)
+		}
+
+		// User management (only for admin role)
+		adminRoutes := protected.Group("/admin")
+		adminRoutes.Use(middleware.RequireRole(models.RoleAdmin))
+		{
+			adminRoutes.GET("/users", userController.GetUsers)
+			adminRoutes.GET("/users/:id", userController.GetUser)
+			adminRoutes.PUT("/users/:id/role", userController.UpdateUserRole)
+			adminRoutes.POST("/users/:id/suspend", userController.SuspendUser)
+			adminRoutes.POST("/users/:id/unsuspend", userController.UnsuspendUser)
+			adminRoutes.DELETE("/users/:id", userController.DeleteUser)
+			adminRoutes.POST("/categories", categoryController.CreateCategory)
+			adminRoutes.PUT("/categories/:id", categoryController.UpdateCategory)
+			adminRoutes.DELETE("/categories/:id", categoryController.DeleteCategory)
+		}
 	}
 
 	return r
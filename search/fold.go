@@ -0,0 +1,55 @@
+// Package search ürünler için tam metin arama indeksini yönetir.
+//
+// SQLite'ta product_fts sanal tablosu (FTS5, derlemede yoksa FTS4), Postgres'te
+// tsvector kolonlu product_search tablosu kullanılır. İndekse yazılan metin ve
+// arama terimleri Go tarafında Fold ile katlanır; böylece "İstanbul", "istanbul"
+// ve "ıstanbul" ya da "şiş" ve "sis" aynı kabul edilir.
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+const maxTerms = 10
+
+var foldReplacer = strings.NewReplacer(
+	"İ", "i", "I", "i", "ı", "i",
+	"Ş", "s", "ş", "s",
+	"Ğ", "g", "ğ", "g",
+	"Ç", "c", "ç", "c",
+	"Ö", "o", "ö", "o",
+	"Ü", "u", "ü", "u",
+	"Â", "a", "â", "a",
+	"Î", "i", "î", "i",
+	"Û", "u", "û", "u",
+)
+
+// Fold metni Türkçe kurallarıyla küçük harfe çevirir ve Türkçe karakterleri
+// ASCII karşılıklarına indirger.
+func Fold(s string) string {
+	return strings.ToLower(foldReplacer.Replace(s))
+}
+
+// Terms arama ifadesini katlanmış, tekrarsız kelimelere ayırır.
+func Terms(q string) []string {
+	words := strings.FieldsFunc(Fold(q), isSeparator)
+
+	seen := make(map[string]bool, len(words))
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+		if len(terms) == maxTerms {
+			break
+		}
+	}
+	return terms
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
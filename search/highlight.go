@@ -0,0 +1,120 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+	ellipsis  = "…"
+)
+
+type span struct {
+	start, end int
+	match      bool
+}
+
+// words metni kelime ve ayraç parçalarına böler; terimlerden biriyle başlayan
+// kelimeler match olarak işaretlenir.
+func words(text string, terms []string) []span {
+	var spans []span
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		folded := Fold(text[start:end])
+		match := false
+		for _, t := range terms {
+			if strings.HasPrefix(folded, t) {
+				match = true
+				break
+			}
+		}
+		spans = append(spans, span{start: start, end: end, match: match})
+		start = -1
+	}
+
+	for i, r := range text {
+		if isSeparator(r) {
+			flush(i)
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	flush(len(text))
+	return spans
+}
+
+// Highlight eşleşen kelimeleri <mark> etiketiyle işaretler. Metnin geri kalanı
+// HTML olarak kaçışlanır.
+func Highlight(text string, terms []string) string {
+	return render(text, words(text, terms), 0, len(text))
+}
+
+// Snippet ilk eşleşmenin çevresindeki en fazla size kelimeyi işaretlenmiş olarak
+// döner. Eşleşme yoksa metnin başı kullanılır.
+func Snippet(text string, terms []string, size int) string {
+	spans := words(text, terms)
+	if len(spans) <= size {
+		return Highlight(text, terms)
+	}
+
+	first := 0
+	for i, s := range spans {
+		if s.match {
+			first = i
+			break
+		}
+	}
+
+	from := first - size/2
+	if from < 0 {
+		from = 0
+	}
+	to := from + size
+	if to > len(spans) {
+		to = len(spans)
+		from = to - size
+	}
+
+	start, end := spans[from].start, spans[to-1].end
+	if to == len(spans) {
+		end = len(text)
+	}
+	out := render(text, spans[from:to], start, end)
+	if from > 0 {
+		out = ellipsis + out
+	}
+	if to < len(spans) {
+		out += ellipsis
+	}
+	return out
+}
+
+func render(text string, spans []span, start, end int) string {
+	var b strings.Builder
+	pos := start
+	for _, s := range spans {
+		if s.start < start || s.end > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:s.start]))
+		if s.match {
+			b.WriteString(markOpen)
+			b.WriteString(html.EscapeString(text[s.start:s.end]))
+			b.WriteString(markClose)
+		} else {
+			b.WriteString(html.EscapeString(text[s.start:s.end]))
+		}
+		pos = s.end
+	}
+	if pos < end {
+		b.WriteString(html.EscapeString(text[pos:end]))
+	}
+	return b.String()
+}
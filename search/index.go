@@ -0,0 +1,148 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// ErrFTS5Unavailable indeks FTS5 ile oluşturulmuşken sqlite sürücüsü FTS5'siz
+// derlendiğinde döner; bu durumda ürün kayıtları indekse yazılamaz.
+var ErrFTS5Unavailable = errors.New("arama indeksi FTS5 ile oluşturulmuş ancak bu derlemede FTS5 yok, `make build` veya `go build -tags sqlite_fts5` ile derleyin")
+
+type engine int
+
+const (
+	engineFTS5 engine = iota
+	engineFTS4
+	enginePostgres
+)
+
+// checked Check'in bulduğu indeks türünü engine+1 olarak tutar; 0 henüz
+// denetlenmediği anlamına gelir.
+var checked atomic.Int32
+
+// current Check'in sakladığı indeks türünü döner. Check çağrılmamışsa (ör.
+// sunucuyu açmayan araçlar) indeks türü her seferinde yeniden tespit edilir.
+func current(db *gorm.DB) (engine, error) {
+	if e := checked.Load(); e > 0 {
+		return engine(e - 1), nil
+	}
+	return detect(db)
+}
+
+// detect mevcut indeks tablosunun türünü döner.
+func detect(db *gorm.DB) (engine, error) {
+	if db.Dialector.Name() == "postgres" {
+		return enginePostgres, nil
+	}
+
+	var ddl string
+	if err := db.Raw("SELECT sql FROM sqlite_master WHERE name = 'product_fts'").Scan(&ddl).Error; err != nil {
+		return 0, err
+	}
+	if ddl == "" {
+		return 0, fmt.Errorf("arama indeksi bulunamadı, migrasyonları çalıştırın")
+	}
+	if !strings.Contains(strings.ToLower(ddl), "fts5") {
+		return engineFTS4, nil
+	}
+
+	fts5, err := FTS5Available(db)
+	if err != nil {
+		return 0, err
+	}
+	if !fts5 {
+		return 0, ErrFTS5Unavailable
+	}
+	return engineFTS5, nil
+}
+
+// FTS5Available sqlite sürücüsünün FTS5 modülüyle derlenip derlenmediğini döner.
+func FTS5Available(db *gorm.DB) (bool, error) {
+	var fts5 bool
+	err := db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&fts5).Error
+	return fts5, err
+}
+
+// Check indeksin bu derlemeyle kullanılabildiğini doğrular ve türünü saklar.
+// Sunucu açılırken çağrılır; böylece FTS modülü eksikse her ürün kaydı ayrı
+// ayrı başarısız olmaz ve kayıtlar ile aramalar katalog sorgusu yapmaz.
+func Check(db *gorm.DB) error {
+	checked.Store(0)
+	e, err := detect(db)
+	if err != nil {
+		return err
+	}
+	checked.Store(int32(e) + 1)
+	return nil
+}
+
+// IndexProduct ürünün indeks kaydını oluşturur veya günceller.
+func IndexProduct(db *gorm.DB, id uint, name, description string) error {
+	e, err := current(db)
+	if err != nil {
+		return err
+	}
+
+	if e == enginePostgres {
+		return db.Exec(`INSERT INTO product_search (product_id, document)
+			VALUES (?, setweight(to_tsvector('simple', ?), 'A') || setweight(to_tsvector('simple', ?), 'B'))
+			ON CONFLICT (product_id) DO UPDATE SET document = EXCLUDED.document`,
+			id, Fold(name), Fold(description)).Error
+	}
+
+	if err := db.Exec("DELETE FROM product_fts WHERE rowid = ?", id).Error; err != nil {
+		return err
+	}
+	return db.Exec("INSERT INTO product_fts (rowid, name, description) VALUES (?, ?, ?)",
+		id, Fold(name), Fold(description)).Error
+}
+
+// RemoveProduct ürünü indeksten çıkarır.
+func RemoveProduct(db *gorm.DB, id uint) error {
+	e, err := current(db)
+	if err != nil {
+		return err
+	}
+
+	if e == enginePostgres {
+		return db.Exec("DELETE FROM product_search WHERE product_id = ?", id).Error
+	}
+	return db.Exec("DELETE FROM product_fts WHERE rowid = ?", id).Error
+}
+
+// Rebuild indeksi silinmemiş tüm ürünlerden yeniden oluşturur.
+func Rebuild(db *gorm.DB) (int, error) {
+	e, err := current(db)
+	if err != nil {
+		return 0, err
+	}
+
+	table := "product_fts"
+	if e == enginePostgres {
+		table = "product_search"
+	}
+	if err := db.Exec("DELETE FROM " + table).Error; err != nil {
+		return 0, err
+	}
+
+	var products []struct {
+		ID          uint
+		Name        string
+		Description string
+	}
+	if err := db.Table("products").Select("id, name, description").Where("deleted_at IS NULL").Find(&products).Error; err != nil {
+		return 0, err
+	}
+
+	for _, p := range products {
+		if err := IndexProduct(db, p.ID, p.Name, p.Description); err != nil {
+			return 0, err
+		}
+	}
+	return len(products), nil
+}
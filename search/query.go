@@ -0,0 +1,79 @@
+package search
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Products terimlerin hepsini kelime başı (önek) olarak içeren aktif
+// ürünlerin ID'lerini alaka sırasıyla ve toplam eşleşme sayısını döner. İsimdeki
+// eşleşmeler açıklamadakilerden daha yüksek puan alır. where boş değilse
+// products tablosuna ek koşul olarak uygulanır (ör. görünürlük filtresi).
+func Products(db *gorm.DB, terms []string, limit, offset int, where string, whereArgs ...interface{}) ([]uint, int64, error) {
+	e, err := current(db)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var from, rank string
+	var match, rankArgs []interface{}
+
+	switch e {
+	case enginePostgres:
+		query := make([]string, len(terms))
+		for i, t := range terms {
+			query[i] = t + ":*"
+		}
+		from = "product_search s JOIN products ON products.id = s.product_id WHERE s.document @@ to_tsquery('simple', ?)"
+		match = []interface{}{strings.Join(query, " & ")}
+		// ts_rank büyük olan daha alakalıdır; artan sıralama için negatifi alınır
+		rank = "-ts_rank(s.document, to_tsquery('simple', ?))"
+		rankArgs = []interface{}{strings.Join(query, " & ")}
+	case engineFTS5:
+		query := make([]string, len(terms))
+		for i, t := range terms {
+			query[i] = `"` + t + `"*`
+		}
+		from = "product_fts JOIN products ON products.id = product_fts.rowid WHERE product_fts MATCH ?"
+		match = []interface{}{strings.Join(query, " ")}
+		rank = "bm25(product_fts, 10.0, 1.0)"
+	default:
+		// FTS4'te bm25 yok: tüm terimleri isimde geçen ürünler öne alınır
+		query := make([]string, len(terms))
+		nameQuery := make([]string, len(terms))
+		for i, t := range terms {
+			query[i] = t + "*"
+			nameQuery[i] = "name:" + t + "*"
+		}
+		from = "product_fts JOIN products ON products.id = product_fts.rowid WHERE product_fts MATCH ?"
+		match = []interface{}{strings.Join(query, " ")}
+		rank = "CASE WHEN products.id IN (SELECT rowid FROM product_fts WHERE product_fts MATCH ?) THEN 0 ELSE 1 END"
+		rankArgs = []interface{}{strings.Join(nameQuery, " ")}
+	}
+	from += " AND products.is_active = ? AND products.deleted_at IS NULL"
+	match = append(match, true)
+	if where != "" {
+		from += " AND " + where
+		match = append(match, whereArgs...)
+	}
+
+	var total int64
+	if err := db.Raw("SELECT COUNT(*) FROM "+from, match...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, nil
+	}
+
+	args := append(append([]interface{}{}, rankArgs...), match...)
+	args = append(args, limit, offset)
+
+	var ids []uint
+	err = db.Raw("SELECT p.id FROM (SELECT products.id, "+rank+" AS rank FROM "+from+") p ORDER BY p.rank, p.id LIMIT ? OFFSET ?", args...).
+		Scan(&ids).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return ids, total, nil
+}
@@ -0,0 +1,27 @@
+// Package storage yüklenen dosyaları (ürün görselleri gibi) saklar.
+//
+// Uygulama yalnızca Storage arayüzünü kullanır; dosyalar anahtarla
+// (ör. products/12/3f9a….jpg) yazılır, silinir ve herkese açık URL'leri
+// anahtardan üretilir. Varsayılan uygulama yerel dosya sistemidir (Local);
+// S3 gibi bir nesne deposu aynı arayüzle eklenebilir.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrInvalidKey = errors.New("geçersiz dosya anahtarı")
+
+type Storage interface {
+	// Put r'nin içeriğini key altına yazar; aynı anahtar varsa üzerine yazar.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Delete key'i siler; dosya yoksa hata dönmez.
+	Delete(ctx context.Context, key string) error
+	// URL key'in istemcilere verilecek adresini döner.
+	URL(key string) string
+}
+
+// Default uygulamanın kullandığı depodur; serve komutu konfigürasyona göre ayarlar.
+var Default Storage
@@ -0,0 +1,247 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"tradesman-api/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	pollInterval    = 5 * time.Second
+	batchSize       = 20
+	concurrency     = 4
+	maxResponseBody = 2 << 10 // Kaydedilen yanıt gövdesi sınırı
+	backoffBase     = time.Minute
+	backoffMax      = 6 * time.Hour
+	userAgent       = "tradesman-webhooks/1.0"
+)
+
+// Default sunucunun teslimatları gönderen dispatcher'ı; serve komutu kurar.
+// Kurulmamışsa (CLI komutları) teslimatlar kuyrukta bekler.
+var Default *Dispatcher
+
+// Dispatcher zamanı gelen teslimatları gönderir.
+type Dispatcher struct {
+	db          *gorm.DB
+	client      *http.Client
+	timeout     time.Duration
+	maxAttempts int
+	wake        chan struct{}
+}
+
+// NewDispatcher timeout süresinde yanıt vermeyen denemeleri başarısız sayar ve
+// teslimatı en fazla maxAttempts kez dener. allowPrivate false ise loopback ve
+// yerel ağ adreslerine bağlanılmaz.
+func NewDispatcher(db *gorm.DB, timeout time.Duration, maxAttempts int, allowPrivate bool) *Dispatcher {
+	return &Dispatcher{
+		db:          db,
+		client:      newClient(timeout, allowPrivate),
+		timeout:     timeout,
+		maxAttempts: maxAttempts,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+func wake() {
+	if Default != nil {
+		Default.Wake()
+	}
+}
+
+// Wake bekleyen teslimatların beklemeden işlenmesini sağlar.
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run ctx bitene kadar kuyruğu işler.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := d.RunDue(ctx)
+			if err != nil {
+				log.Printf("webhook kuyruğu işlenemedi: %v", err)
+				break
+			}
+			if n < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// RunDue zamanı gelen en fazla batchSize teslimatı gönderir ve gönderilen
+// sayısını döner.
+func (d *Dispatcher) RunDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	var due []models.WebhookDelivery
+	err := d.db.Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at").Limit(batchSize).Find(&due).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	sent := 0
+	for i := range due {
+		claimed, err := d.claim(due[i], now)
+		if err != nil {
+			return sent, err
+		}
+		if !claimed {
+			continue
+		}
+		sent++
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(delivery models.WebhookDelivery) {
+			defer func() { <-sem; wg.Done() }()
+			if err := d.deliver(ctx, delivery); err != nil {
+				log.Printf("webhook teslimatı kaydedilemedi (%d): %v", delivery.ID, err)
+			}
+		}(due[i])
+	}
+	wg.Wait()
+	return sent, nil
+}
+
+// claim teslimatı deneme süresince kiralar; böylece aynı kuyruğu işleyen başka
+// bir sunucu aynı teslimatı göndermez. Deneme yarıda kalırsa (sunucu kapanırsa)
+// kira bitince teslimat yeniden denenir.
+func (d *Dispatcher) claim(delivery models.WebhookDelivery, now time.Time) (bool, error) {
+	lease := now.Add(2*d.timeout + time.Minute)
+	result := d.db.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", delivery.ID, models.WebhookDeliveryPending, now).
+		Update("next_attempt_at", lease)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery) error {
+	var endpoint models.WebhookEndpoint
+	err := d.db.Unscoped().First(&endpoint, delivery.EndpointID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	switch {
+	case err != nil || endpoint.DeletedAt.Valid:
+		return d.giveUp(delivery, "Uç nokta silinmiş")
+	case !endpoint.IsActive:
+		return d.giveUp(delivery, "Uç nokta pasif")
+	}
+
+	attempt := d.send(ctx, endpoint, delivery)
+	attempt.DeliveryID = delivery.ID
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_attempt_at": now,
+		"response_code":   attempt.ResponseCode,
+		"error":           attempt.Error,
+	}
+	attempts := delivery.Attempts + 1
+	switch {
+	case attempt.ResponseCode != nil && *attempt.ResponseCode >= 200 && *attempt.ResponseCode < 300:
+		updates["status"] = models.WebhookDeliverySucceeded
+		updates["delivered_at"] = now
+		updates["next_attempt_at"] = nil
+	case attempts >= d.maxAttempts:
+		updates["status"] = models.WebhookDeliveryFailed
+		updates["next_attempt_at"] = nil
+	default:
+		updates["next_attempt_at"] = now.Add(Backoff(attempts))
+	}
+
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(updates).Error
+	})
+}
+
+// send tek bir denemeyi yapar ve sonucunu döner.
+func (d *Dispatcher) send(ctx context.Context, endpoint models.WebhookEndpoint, delivery models.WebhookDelivery) models.WebhookAttempt {
+	var attempt models.WebhookAttempt
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Event-Id", strconv.FormatUint(delivery.EventID, 10))
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", Sign(endpoint.Secret, timestamp, body))
+
+	start := time.Now()
+	resp, err := d.client.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	code := resp.StatusCode
+	attempt.ResponseCode = &code
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	attempt.ResponseBody = string(bytes.ToValidUTF8(snippet, nil))
+	if code < 200 || code >= 300 {
+		attempt.Error = fmt.Sprintf("beklenmeyen yanıt: %s", resp.Status)
+	}
+	return attempt
+}
+
+// giveUp gönderilemeyecek teslimatı deneme yapmadan başarısız sayar.
+func (d *Dispatcher) giveUp(delivery models.WebhookDelivery, reason string) error {
+	return d.db.Model(&models.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":          models.WebhookDeliveryFailed,
+		"error":           reason,
+		"next_attempt_at": nil,
+	}).Error
+}
+
+// Backoff n. başarısız denemeden sonraki bekleme süresini döner: 1, 2, 4, 8...
+// dakika, en fazla 6 saat.
+func Backoff(n int) time.Duration {
+	wait := backoffBase
+	for i := 1; i < n && wait < backoffMax; i++ {
+		wait *= 2
+	}
+	if wait > backoffMax {
+		wait = backoffMax
+	}
+	return wait
+}
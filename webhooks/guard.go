@@ -0,0 +1,81 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const maxURLLength = 2048
+
+var errPrivateAddress = errors.New("yerel ağ adreslerine webhook gönderilemez")
+
+// ValidateURL uç nokta adresini doğrular. allowPrivate false ise localhost ve
+// yerel ağ IP'leri reddedilir; alan adları bağlantı anında ayrıca denetlenir.
+func ValidateURL(raw string, allowPrivate bool) error {
+	if len(raw) > maxURLLength {
+		return fmt.Errorf("adres en fazla %d karakter olabilir", maxURLLength)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("adres http:// veya https:// ile başlayan geçerli bir URL olmalı")
+	}
+	if u.User != nil {
+		return errors.New("adres kullanıcı bilgisi içeremez")
+	}
+	if allowPrivate {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errPrivateAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && isPrivate(ip) {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+func isPrivate(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast()
+}
+
+// newClient webhook isteklerini yapan istemciyi kurar. Yönlendirmeler
+// izlenmez (3xx başarısız sayılır) ve allowPrivate false ise alan adı çözüldükten
+// sonra yerel ağ adreslerine bağlantı reddedilir; böylece esnaflar sunucunun iç
+// ağına istek yaptıramaz.
+func newClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivate(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 nil, // Vekil sunucu adres denetimini atlatmasın
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConnsPerHost:   2,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,166 @@
+// Package webhooks sipariş olaylarını esnafların kayıtlı adreslerine imzalı
+// HTTP istekleriyle iletir.
+//
+// Her olay, olaya abone olan her aktif uç nokta için webhook_deliveries
+// tablosuna bir teslimat olarak yazılır; gönderimi Dispatcher yapar. Başarısız
+// teslimatlar artan aralıklarla yeniden denenir ve her deneme yanıt koduyla
+// webhook_attempts tablosuna kaydedilir. Kuyruk veritabanında tutulduğu için
+// sunucu yeniden başlasa da bekleyen teslimatlar kaybolmaz; birden fazla sunucu
+// aynı kuyruğu işleyebilir.
+//
+// Gövde JSON'dur ({id, type, created_at, data}) ve şu başlıklarla gönderilir:
+//
+//	X-Webhook-Event:     order.created
+//	X-Webhook-Event-Id:  olay ID'si (tekrarlanan teslimatları ayıklamak için)
+//	X-Webhook-Delivery:  teslimat ID'si
+//	X-Webhook-Timestamp: gönderim zamanı (Unix saniye)
+//	X-Webhook-Signature: sha256=HMAC-SHA256(secret, timestamp + "." + gövde)
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"tradesman-api/events"
+	"tradesman-api/models"
+
+	"gorm.io/gorm"
+)
+
+// Yalnızca bağlantıyı denemek için gönderilen olay; abonelik gerektirmez.
+const EventPing = "webhook.ping"
+
+// Uç noktaların abone olabileceği olay tipleri
+var EventTypes = []string{events.OrderCreated, events.OrderStatusChanged, events.StockAlert}
+
+func IsEventType(t string) bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload alıcıya gönderilen gövde.
+type Payload struct {
+	ID        uint64      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// NewSecret yeni bir imza anahtarı üretir.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign gövdenin imzasını X-Webhook-Signature biçiminde döner. Alıcılar aynı
+// hesabı yapıp sabit zamanlı karşılaştırmalı ve eski zaman damgalarını
+// reddetmelidir.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Enqueue olayı dükkanın olaya abone aktif uç noktaları için kuyruğa yazar ve
+// oluşturulan teslimat sayısını döner.
+func Enqueue(db *gorm.DB, e events.Event) (int, error) {
+	var endpoints []models.WebhookEndpoint
+	if err := db.Where("shop_id = ? AND is_active = ?", e.ShopID, true).Find(&endpoints).Error; err != nil {
+		return 0, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribed(e.Type) {
+			continue
+		}
+		delivery, err := newDelivery(endpoint, e)
+		if err != nil {
+			return 0, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	if err := db.Create(&deliveries).Error; err != nil {
+		return 0, err
+	}
+	wake()
+	return len(deliveries), nil
+}
+
+// EnqueuePing uç noktaya deneme olayı gönderir. Deneme olayı sipariş akışına
+// yayınlanmaz; ID'si gönderim zamanından üretilir.
+func EnqueuePing(db *gorm.DB, endpoint models.WebhookEndpoint) (models.WebhookDelivery, error) {
+	now := time.Now()
+	e := events.Event{
+		ID:        uint64(now.UnixNano()),
+		Type:      EventPing,
+		ShopID:    endpoint.ShopID,
+		Data:      map[string]interface{}{"endpoint_id": endpoint.ID},
+		CreatedAt: now,
+	}
+	delivery, err := newDelivery(endpoint, e)
+	if err != nil {
+		return delivery, err
+	}
+	if err := db.Create(&delivery).Error; err != nil {
+		return delivery, err
+	}
+	wake()
+	return delivery, nil
+}
+
+func newDelivery(endpoint models.WebhookEndpoint, e events.Event) (models.WebhookDelivery, error) {
+	body, err := json.Marshal(Payload{ID: e.ID, Type: e.Type, CreatedAt: e.CreatedAt, Data: e.Data})
+	if err != nil {
+		return models.WebhookDelivery{}, fmt.Errorf("webhook gövdesi oluşturulamadı: %w", err)
+	}
+	now := time.Now()
+	return models.WebhookDelivery{
+		EndpointID:    endpoint.ID,
+		ShopID:        endpoint.ShopID,
+		EventID:       e.ID,
+		EventType:     e.Type,
+		Payload:       string(body),
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+	}, nil
+}
+
+// Redeliver teslimatı yeniden kuyruğa alır; deneme sayısı sıfırlanır, önceki
+// denemeler kayıtlarda kalır. Teslimat zaten bekliyorsa false döner.
+func Redeliver(db *gorm.DB, delivery *models.WebhookDelivery) (bool, error) {
+	now := time.Now()
+	result := db.Model(delivery).
+		Where("status <> ?", models.WebhookDeliveryPending).
+		Updates(map[string]interface{}{
+			"status":          models.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	wake()
+	return true, nil
+}
@@ -0,0 +1,339 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"tradesman-api/events"
+	"tradesman-api/migrations"
+	"tradesman-api/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testSecret = "whsec_test"
+
+// receiver gelen webhook isteklerini kaydeden ve status ile yanıt veren test sunucusu.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []received
+}
+
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+func newReceiver(t *testing.T, status int) *receiver {
+	r := &receiver{status: status}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, received{header: req.Header.Clone(), body: body})
+		status := r.status
+		r.mu.Unlock()
+		w.WriteHeader(status)
+		w.Write([]byte("tamam"))
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) setStatus(status int) {
+	r.mu.Lock()
+	r.status = status
+	r.mu.Unlock()
+}
+
+func (r *receiver) received() []received {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]received(nil), r.requests...)
+}
+
+// testDB migrasyonları uygulanmış geçici bir SQLite veritabanı açar.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("veritabanı açılamadı: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatalf("migrasyonlar uygulanamadı: %v", err)
+	}
+	return db
+}
+
+// enqueueOrder url'e abone bir uç nokta oluşturur ve bir order.created olayını
+// kuyruğa yazar.
+func enqueueOrder(t *testing.T, db *gorm.DB, url string) models.WebhookDelivery {
+	t.Helper()
+
+	owner := models.User{Name: "Esnaf", Email: "esnaf@example.com", Password: "x", Role: models.RoleShop}
+	if err := db.Create(&owner).Error; err != nil {
+		t.Fatal(err)
+	}
+	shop := models.Shop{UserID: owner.ID, Name: "Fırın", IsActive: true}
+	if err := db.Create(&shop).Error; err != nil {
+		t.Fatal(err)
+	}
+	endpoint := models.WebhookEndpoint{
+		ShopID:   shop.ID,
+		URL:      url,
+		Secret:   testSecret,
+		Events:   []string{events.OrderCreated},
+		IsActive: true,
+	}
+	if err := db.Create(&endpoint).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := Enqueue(db, events.Event{
+		ID:        42,
+		Type:      events.OrderCreated,
+		ShopID:    shop.ID,
+		Data:      map[string]interface{}{"order": map[string]interface{}{"id": 7}},
+		CreatedAt: time.Now(),
+	})
+	if err != nil || n != 1 {
+		t.Fatalf("olay kuyruğa yazılamadı: %d teslimat, %v", n, err)
+	}
+
+	var delivery models.WebhookDelivery
+	if err := db.Where("endpoint_id = ?", endpoint.ID).First(&delivery).Error; err != nil {
+		t.Fatal(err)
+	}
+	return delivery
+}
+
+// runDue zamanı gelen teslimatları gönderir ve teslimatın son halini döner.
+func runDue(t *testing.T, d *Dispatcher, db *gorm.DB, id uint) models.WebhookDelivery {
+	t.Helper()
+	if _, err := d.RunDue(context.Background()); err != nil {
+		t.Fatalf("kuyruk işlenemedi: %v", err)
+	}
+	var delivery models.WebhookDelivery
+	if err := db.First(&delivery, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return delivery
+}
+
+// makeDue bekleyen teslimatın deneme zamanını geçmişe çeker.
+func makeDue(t *testing.T, db *gorm.DB, id uint) {
+	t.Helper()
+	err := db.Model(&models.WebhookDelivery{}).Where("id = ?", id).
+		Update("next_attempt_at", time.Now().Add(-time.Second)).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeliverySigned(t *testing.T) {
+	db := testDB(t)
+	srv := newReceiver(t, http.StatusNoContent)
+	delivery := enqueueOrder(t, db, srv.URL)
+
+	d := NewDispatcher(db, 5*time.Second, 3, true)
+	delivery = runDue(t, d, db, delivery.ID)
+
+	requests := srv.received()
+	if len(requests) != 1 {
+		t.Fatalf("%d istek alındı, beklenen 1", len(requests))
+	}
+	req := requests[0]
+	if string(req.body) != delivery.Payload {
+		t.Errorf("gövde kuyruktaki yükten farklı:\n%s\n%s", req.body, delivery.Payload)
+	}
+
+	timestamp, err := strconv.ParseInt(req.header.Get("X-Webhook-Timestamp"), 10, 64)
+	if err != nil {
+		t.Fatalf("geçersiz X-Webhook-Timestamp: %v", err)
+	}
+	if want := Sign(testSecret, timestamp, req.body); req.header.Get("X-Webhook-Signature") != want {
+		t.Errorf("X-Webhook-Signature %q, beklenen %q", req.header.Get("X-Webhook-Signature"), want)
+	}
+	if got := Sign("whsec_baska", timestamp, req.body); got == req.header.Get("X-Webhook-Signature") {
+		t.Error("farklı anahtarla aynı imza üretildi")
+	}
+	for header, want := range map[string]string{
+		"X-Webhook-Event":    events.OrderCreated,
+		"X-Webhook-Event-Id": "42",
+		"X-Webhook-Delivery": strconv.FormatUint(uint64(delivery.ID), 10),
+	} {
+		if got := req.header.Get(header); got != want {
+			t.Errorf("%s %q, beklenen %q", header, got, want)
+		}
+	}
+
+	if delivery.Status != models.WebhookDeliverySucceeded || delivery.DeliveredAt == nil || delivery.NextAttemptAt != nil {
+		t.Errorf("teslimat başarılı sayılmadı: %+v", delivery)
+	}
+	if delivery.Attempts != 1 || delivery.ResponseCode == nil || *delivery.ResponseCode != http.StatusNoContent {
+		t.Errorf("deneme kaydı beklenen gibi değil: %+v", delivery)
+	}
+}
+
+func TestDeliveryRetriesThenFails(t *testing.T) {
+	db := testDB(t)
+	srv := newReceiver(t, http.StatusInternalServerError)
+	delivery := enqueueOrder(t, db, srv.URL)
+
+	const maxAttempts = 3
+	d := NewDispatcher(db, 5*time.Second, maxAttempts, true)
+
+	for attempt := 1; attempt < maxAttempts; attempt++ {
+		before := time.Now()
+		delivery = runDue(t, d, db, delivery.ID)
+		after := time.Now()
+
+		if delivery.Status != models.WebhookDeliveryPending || delivery.Attempts != attempt {
+			t.Fatalf("%d. deneme sonrası durum %s, deneme %d", attempt, delivery.Status, delivery.Attempts)
+		}
+		if delivery.ResponseCode == nil || *delivery.ResponseCode != http.StatusInternalServerError || delivery.Error == "" {
+			t.Errorf("%d. denemenin sonucu kaydedilmedi: %+v", attempt, delivery)
+		}
+		wait := Backoff(attempt)
+		if delivery.NextAttemptAt == nil ||
+			delivery.NextAttemptAt.Before(before.Add(wait)) || delivery.NextAttemptAt.After(after.Add(wait)) {
+			t.Errorf("%d. deneme sonrası sonraki deneme %v, beklenen yaklaşık %v sonra", attempt, delivery.NextAttemptAt, wait)
+		}
+
+		// Zamanı gelmeyen teslimat gönderilmez
+		if n, _ := d.RunDue(context.Background()); n != 0 {
+			t.Fatalf("bekleme süresi dolmadan %d teslimat gönderildi", n)
+		}
+		makeDue(t, db, delivery.ID)
+	}
+
+	delivery = runDue(t, d, db, delivery.ID)
+	if delivery.Status != models.WebhookDeliveryFailed || delivery.Attempts != maxAttempts || delivery.NextAttemptAt != nil {
+		t.Errorf("denemeler bitince teslimat başarısız sayılmadı: %+v", delivery)
+	}
+	if got := len(srv.received()); got != maxAttempts {
+		t.Errorf("%d istek alındı, beklenen %d", got, maxAttempts)
+	}
+
+	var history int64
+	db.Model(&models.WebhookAttempt{}).Where("delivery_id = ?", delivery.ID).Count(&history)
+	if history != maxAttempts {
+		t.Errorf("%d deneme kaydı, beklenen %d", history, maxAttempts)
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	db := testDB(t)
+	srv := newReceiver(t, http.StatusBadGateway)
+	delivery := enqueueOrder(t, db, srv.URL)
+
+	d := NewDispatcher(db, 5*time.Second, 1, true)
+	delivery = runDue(t, d, db, delivery.ID)
+	if delivery.Status != models.WebhookDeliveryFailed {
+		t.Fatalf("teslimat durumu %s, beklenen failed", delivery.Status)
+	}
+
+	queued, err := Redeliver(db, &delivery)
+	if err != nil || !queued {
+		t.Fatalf("başarısız teslimat yeniden kuyruğa alınamadı: %v, %v", queued, err)
+	}
+	if err := db.First(&delivery, delivery.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != models.WebhookDeliveryPending || delivery.Attempts != 0 || delivery.NextAttemptAt == nil {
+		t.Errorf("yeniden kuyruğa alınan teslimat %+v", delivery)
+	}
+
+	// Bekleyen teslimat ikinci kez kuyruğa alınmaz
+	if queued, err := Redeliver(db, &delivery); err != nil || queued {
+		t.Errorf("bekleyen teslimat yeniden kuyruğa alındı: %v, %v", queued, err)
+	}
+
+	srv.setStatus(http.StatusOK)
+	delivery = runDue(t, d, db, delivery.ID)
+	if delivery.Status != models.WebhookDeliverySucceeded || delivery.Attempts != 1 {
+		t.Errorf("yeniden gönderilen teslimat %+v", delivery)
+	}
+
+	// Önceki denemeler kayıtlarda kalır
+	var history int64
+	db.Model(&models.WebhookAttempt{}).Where("delivery_id = ?", delivery.ID).Count(&history)
+	if history != 2 {
+		t.Errorf("%d deneme kaydı, beklenen 2", history)
+	}
+}
+
+func TestPrivateAddressGuard(t *testing.T) {
+	for _, raw := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://api.localhost/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+	} {
+		if err := ValidateURL(raw, false); !errors.Is(err, errPrivateAddress) {
+			t.Errorf("ValidateURL(%q) = %v, beklenen yerel ağ hatası", raw, err)
+		}
+		if err := ValidateURL(raw, true); err != nil {
+			t.Errorf("ValidateURL(%q, allowPrivate) = %v", raw, err)
+		}
+	}
+	if err := ValidateURL("https://pos.example.com/hook", false); err != nil {
+		t.Errorf("genel adres reddedildi: %v", err)
+	}
+
+	// Kayıtlı adres doğrulamayı atlatsa da bağlantı anında reddedilir
+	db := testDB(t)
+	srv := newReceiver(t, http.StatusOK)
+	delivery := enqueueOrder(t, db, srv.URL)
+
+	d := NewDispatcher(db, 5*time.Second, 3, false)
+	delivery = runDue(t, d, db, delivery.ID)
+	if got := len(srv.received()); got != 0 {
+		t.Errorf("loopback adrese %d istek gönderildi", got)
+	}
+	if delivery.Status != models.WebhookDeliveryPending || delivery.Attempts != 1 || delivery.ResponseCode != nil {
+		t.Errorf("reddedilen bağlantı başarısız deneme sayılmadı: %+v", delivery)
+	}
+	var attempt models.WebhookAttempt
+	if err := db.Where("delivery_id = ?", delivery.ID).First(&attempt).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(attempt.Error, errPrivateAddress.Error()) {
+		t.Errorf("deneme hatası %q, beklenen %q", attempt.Error, errPrivateAddress)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for n, want := range map[int]time.Duration{
+		1:  time.Minute,
+		2:  2 * time.Minute,
+		3:  4 * time.Minute,
+		9:  256 * time.Minute,
+		10: 6 * time.Hour,
+		20: 6 * time.Hour,
+	} {
+		if got := Backoff(n); got != want {
+			t.Errorf("Backoff(%d) = %v, beklenen %v", n, got, want)
+		}
+	}
+}